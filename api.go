@@ -1,8 +1,11 @@
 package goethkzg
 
 import (
-	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
 
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/crate-crypto/go-eth-kzg/internal/domain"
 	"github.com/crate-crypto/go-eth-kzg/internal/erasure_code"
 	"github.com/crate-crypto/go-eth-kzg/internal/kzg"
@@ -12,9 +15,11 @@ import (
 
 // Context holds the necessary configuration needed to create and verify proofs.
 //
-// Note: We could marshall this object so that clients won't need to process the SRS each time. The time to process is
-// about 2-5 seconds.
+// Processing the SRS takes about 2-5 seconds. To avoid doing this on every start, the Context can be serialized with
+// [Context.MarshalBinary] and loaded again with [UnmarshalContext].
 type Context struct {
+	params Params
+
 	domain            *domain.Domain
 	domainExtended    *domain.Domain
 	commitKeyLagrange *kzg.CommitKey
@@ -25,6 +30,18 @@ type Context struct {
 	fk20 *fk20.FK20
 
 	dataRecovery *erasure_code.DataRecovery
+
+	// deterministicVerification is set when the batch verification methods should use the
+	// Fiat-Shamir challenges from the spec instead of sampling random numbers.
+	// See [Context.WithDeterministicVerification].
+	deterministicVerification bool
+
+	// setupFingerprint identifies the trusted setup that the context was created from.
+	setupFingerprint Fingerprint
+
+	// randomness is the source of the random numbers used in batch verification, or nil
+	// to use crypto/rand. See [Context.WithRandomness].
+	randomness *randomnessSource
 }
 
 // BlsModulus is the bytes representation of the bls12-381 scalar field modulus.
@@ -56,9 +73,7 @@ func NewContext4096Secure() (*Context, error) {
 		panic("this method is named `NewContext4096Secure` we expect SCALARS_PER_BLOB to be 4096")
 	}
 
-	parsedSetup := JSONTrustedSetup{}
-
-	err := json.Unmarshal([]byte(testKzgSetupStr), &parsedSetup)
+	parsedSetup, err := LoadTrustedSetupJSON(strings.NewReader(testKzgSetupStr))
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +82,7 @@ func NewContext4096Secure() (*Context, error) {
 		// This is a library method and so we panic
 		panic("this method is named `NewContext4096Secure` we expect the number of G1 elements in the trusted setup to be 4096")
 	}
-	return NewContext4096(&parsedSetup)
+	return NewContext4096(parsedSetup)
 }
 
 // NewContext4096 creates a new context object which will hold the state needed for one to use the EIP-4844 methods. The
@@ -86,64 +101,271 @@ func NewContext4096Secure() (*Context, error) {
 //   - G2points = {H, alpha * H, alpha^2 * H, ..., alpha^n * H}
 //   - Lagrange G1Points = {L_0(alpha^0) * G, L_1(alpha) * G, L_2(alpha^2) * G, ..., L_n(alpha^n) * G}
 //
+// See [ContextOption] for the options that can be passed.
+//
 // [Full Danksharding]: https://notes.ethereum.org/@dankrad/new_sharding
-func NewContext4096(trustedSetup *JSONTrustedSetup) (*Context, error) {
+func NewContext4096(trustedSetup *JSONTrustedSetup, opts ...ContextOption) (*Context, error) {
+	return newContext(trustedSetup, MainnetParams, opts, contextParts{
+		eip4844Prover:   true,
+		eip7594Prover:   true,
+		eip7594Verifier: true,
+	})
+}
+
+// NewContextWithParams creates a new context object for blobs and cells whose sizes are given by `params`.
+//
+// The trusted setup must have exactly `params.FieldElementsPerBlob` G1 points in both monomial and lagrange form, and
+// more G2 points than `params.FieldElementsPerCell`. The points are assumed to be in the same order as described in
+// [NewContext4096].
+//
+// See [Params] for the methods that should be used when `params` is not [MainnetParams].
+func NewContextWithParams(trustedSetup *JSONTrustedSetup, params Params) (*Context, error) {
+	return newContext(trustedSetup, params, nil, contextParts{
+		eip4844Prover:   true,
+		eip7594Prover:   true,
+		eip7594Verifier: true,
+	})
+}
+
+// NewVerifierContext creates a new context object that can only be used to verify EIP-4844 and EIP-7594 proofs.
+//
+// Only the G2 points and as many monomial G1 points as there are G2 points are processed. This makes the context much
+// cheaper to create and to hold in memory than one created with [NewContextWithParams], which makes it a good fit for
+// deployments that never compute proofs. Methods that compute commitments, proofs or cells will return
+// [ErrProverUnavailable].
+func NewVerifierContext(trustedSetup *JSONTrustedSetup, params Params) (*Context, error) {
+	return newContext(trustedSetup, params, nil, contextParts{
+		eip7594Verifier: true,
+	})
+}
+
+// NewContext4844Only creates a new context object that can only be used for the EIP-4844 methods.
+//
+// None of the tables needed for EIP-7594 are built and the monomial G1 points are not processed. Calling an EIP-7594
+// method will return [ErrEIP7594Unavailable].
+func NewContext4844Only(trustedSetup *JSONTrustedSetup, params Params) (*Context, error) {
+	return newContext(trustedSetup, params, nil, contextParts{
+		eip4844Prover: true,
+	})
+}
+
+// contextParts selects the optional parts of a Context that a constructor builds.
+//
+// The domain and the EIP-4844 opening key are cheap to build, so they are always present.
+type contextParts struct {
+	// eip4844Prover builds the lagrange commit key.
+	eip4844Prover bool
+	// eip7594Prover builds the monomial commit key, the FK20 tables and the data recovery tables.
+	eip7594Prover bool
+	// eip7594Verifier builds the opening key for cell proofs.
+	eip7594Verifier bool
+}
+
+func newContext(trustedSetup *JSONTrustedSetup, params Params, opts []ContextOption, parts contextParts) (*Context, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	var options contextOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	// This is cheap compared to processing the setup, so we check it first
+	setupFingerprint, err := checkSetupFingerprint(trustedSetup, options)
+	if err != nil {
+		return nil, err
+	}
+
 	// This should not happen for the ETH protocol
 	// However since it's a public method, we add the check.
 	if len(trustedSetup.SetupG2) < 2 {
 		return nil, kzg.ErrMinSRSSize
 	}
 
+	if err := checkTrustedSetupLen(trustedSetup, params, parts); err != nil {
+		return nil, err
+	}
+
 	// Parse the trusted setup from hex strings to G1 and G2 points
-	genG1, setupMonomialG1Points, setupLagrangeG1Points, setupG2Points := parseTrustedSetup(trustedSetup)
+	//
+	// The G1 generator is the first element of the monomial G1 points.
+	// We do not always parse those and so we use the fact that the setup
+	// started at the canonical generator point.
+	_, _, genG1, _ := bls12381.Generators()
+	setupG2Points, err := parseG2PointsNoSubgroupCheck(trustedSetup.SetupG2)
+	if err != nil {
+		return nil, fmt.Errorf("%w: g2_monomial %w", ErrMalformedTrustedSetup, err)
+	}
+
+	// The prover needs all of the monomial G1 points, whereas the verifier
+	// only needs as many as there are G2 points.
+	var setupMonomialG1Points []bls12381.G1Affine
+	if parts.eip7594Prover {
+		setupMonomialG1Points, err = parseG1PointsNoSubgroupCheck(trustedSetup.SetupG1Monomial)
+	} else if parts.eip7594Verifier {
+		setupMonomialG1Points, err = parseG1PointsNoSubgroupCheck(trustedSetup.SetupG1Monomial[:len(setupG2Points)])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: g1_monomial %w", ErrMalformedTrustedSetup, err)
+	}
 
 	// Get the generator points and the degree-1 element for G2 points
 	// The generators are the degree-0 elements in the trusted setup
 	//
 	// This will never panic as we checked the minimum SRS size is >= 2
-	// and `ScalarsPerBlob` is 4096
 	genG2 := setupG2Points[0]
 	alphaGenG2 := setupG2Points[1]
 
-	commitKeyLagrange := kzg.CommitKey{
-		G1: setupLagrangeG1Points,
-	}
-	commitKeyMonomial := kzg.CommitKey{
-		G1: setupMonomialG1Points,
-	}
-
-	if len(setupG2Points) < scalarsPerCell {
-		panic("The number of G2 points in the trusted setup is less than the number of scalars per blob")
-	}
-
 	openingKey4844 := kzg.OpeningKey{
 		GenG1:   genG1,
 		GenG2:   genG2,
 		AlphaG2: alphaGenG2,
 	}
 
-	openingKey7594 := kzgmulti.NewOpeningKey(setupMonomialG1Points[:len(setupG2Points)], setupG2Points, ScalarsPerBlob, scalarsPerExtBlob, scalarsPerCell)
-
-	domainBlobLen := domain.NewDomain(ScalarsPerBlob)
+	domainBlobLen := domain.NewDomain(params.FieldElementsPerBlob)
 	// Bit-Reverse the roots and the trusted setup according to the specs
 	// The bit reversal is not needed for simple KZG however it was
 	// implemented to make the step for full dank-sharding easier.
-	commitKeyLagrange.ReversePoints()
 	domainBlobLen.ReverseRoots()
 
-	domainExtended := domain.NewDomain(scalarsPerExtBlob)
-	domainExtended.ReverseRoots()
-
-	fk20 := fk20.NewFK20(commitKeyMonomial.G1, scalarsPerExtBlob, scalarsPerCell)
-
-	return &Context{
-		domain:            domainBlobLen,
-		domainExtended:    domainExtended,
-		commitKeyLagrange: &commitKeyLagrange,
-		commitKeyMonomial: &commitKeyMonomial,
-		openKey4844:       &openingKey4844,
-		openKey7594:       openingKey7594,
-		fk20:              &fk20,
-		dataRecovery:      erasure_code.NewDataRecovery(scalarsPerCell, ScalarsPerBlob, expansionFactor),
-	}, nil
+	ctx := &Context{
+		params:           params,
+		domain:           domainBlobLen,
+		openKey4844:      &openingKey4844,
+		setupFingerprint: setupFingerprint,
+	}
+
+	if parts.eip4844Prover {
+		setupLagrangeG1Points, err := parseG1PointsNoSubgroupCheck(trustedSetup.SetupG1Lagrange)
+		if err != nil {
+			return nil, fmt.Errorf("%w: g1_lagrange %w", ErrMalformedTrustedSetup, err)
+		}
+		commitKeyLagrange := kzg.CommitKey{
+			G1: setupLagrangeG1Points,
+		}
+		commitKeyLagrange.ReversePoints()
+		ctx.commitKeyLagrange = &commitKeyLagrange
+	}
+
+	scalarsPerExtBlob := params.FieldElementsPerExtBlob()
+
+	if parts.eip7594Verifier {
+		ctx.openKey7594 = kzgmulti.NewOpeningKey(setupMonomialG1Points[:len(setupG2Points)], setupG2Points, params.FieldElementsPerBlob, scalarsPerExtBlob, params.FieldElementsPerCell)
+	}
+
+	if parts.eip7594Prover {
+		commitKeyMonomial := kzg.CommitKey{
+			G1: setupMonomialG1Points,
+		}
+
+		domainExtended := domain.NewDomain(scalarsPerExtBlob)
+		domainExtended.ReverseRoots()
+
+		fk20 := fk20.NewFK20(commitKeyMonomial.G1, int(scalarsPerExtBlob), int(params.FieldElementsPerCell))
+
+		ctx.commitKeyMonomial = &commitKeyMonomial
+		ctx.domainExtended = domainExtended
+		ctx.fk20 = &fk20
+		ctx.dataRecovery = erasure_code.NewDataRecovery(int(params.FieldElementsPerCell), int(params.FieldElementsPerBlob), int(params.ExpansionFactor))
+	}
+
+	return ctx, nil
+}
+
+// checkTrustedSetupLen checks that the trusted setup has the points that are needed to build `parts`.
+func checkTrustedSetupLen(trustedSetup *JSONTrustedSetup, params Params, parts contextParts) error {
+	if parts.eip4844Prover && uint64(len(trustedSetup.SetupG1Lagrange)) != params.FieldElementsPerBlob {
+		return ErrInvalidTrustedSetupLen
+	}
+	if parts.eip7594Prover && uint64(len(trustedSetup.SetupG1Monomial)) != params.FieldElementsPerBlob {
+		return ErrInvalidTrustedSetupLen
+	}
+	// The opening key for cells needs the G2 point of degree `FieldElementsPerCell`
+	// and a monomial G1 point for each G2 point.
+	if parts.eip7594Verifier {
+		if uint64(len(trustedSetup.SetupG2)) <= params.FieldElementsPerCell ||
+			len(trustedSetup.SetupG2) > len(trustedSetup.SetupG1Monomial) {
+			return ErrInvalidTrustedSetupLen
+		}
+	}
+	return nil
+}
+
+// Params returns the parameters that the context was created with.
+func (ctx *Context) Params() Params {
+	return ctx.params
+}
+
+// WithDeterministicVerification returns a copy of the context whose batch verification methods derive the number
+// used to combine the proofs by hashing their inputs, exactly as the spec does, instead of sampling it at random.
+//
+// The spec computes it with [compute_verify_cell_kzg_proof_batch_challenge] when verifying cells, and in
+// [verify_kzg_proof_batch] when verifying blobs. Using the same number as the spec means that the outcome of every
+// verification can be reproduced bit-for-bit, including by other clients, which is useful when debugging.
+//
+// The copy shares the precomputed tables with `ctx`, so this is cheap.
+//
+// [compute_verify_cell_kzg_proof_batch_challenge]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_verify_cell_kzg_proof_batch_challenge
+// [verify_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof_batch
+func (ctx *Context) WithDeterministicVerification() *Context {
+	deterministic := *ctx
+	deterministic.deterministicVerification = true
+	return &deterministic
+}
+
+// WithRandomness returns a copy of the context whose batch verification methods sample the number used to combine
+// the proofs from `reader`, instead of from crypto/rand.
+//
+// This allows a node to use its own CSPRNG, and tests to seed the verifier so that a failing verification can be
+// replayed. Passing nil restores the default source. The reader is only called while holding a lock, so it does not
+// need to be safe for concurrent use. An error from the reader is returned wrapped in [ErrReadRandomness].
+//
+// The security of batch verification relies on the numbers being unpredictable to whoever created the proofs, so
+// a fixed seed must never be used outside of tests. The randomness is not used by a context returned from
+// [Context.WithDeterministicVerification].
+//
+// The copy shares the precomputed tables with `ctx`, so this is cheap.
+func (ctx *Context) WithRandomness(reader io.Reader) *Context {
+	seeded := *ctx
+	seeded.randomness = nil
+	if reader != nil {
+		seeded.randomness = &randomnessSource{reader: reader}
+	}
+	return &seeded
+}
+
+// checkFixedSizeTypes returns an error if the context cannot be used with the fixed-size
+// [Blob] and [Cell] types.
+func (ctx *Context) checkFixedSizeTypes() error {
+	if ctx.params != MainnetParams {
+		return ErrParamsMismatch
+	}
+	return nil
+}
+
+// checkEIP4844Prover returns an error if the context cannot compute EIP-4844 commitments and proofs.
+func (ctx *Context) checkEIP4844Prover() error {
+	if ctx.commitKeyLagrange == nil {
+		return ErrProverUnavailable
+	}
+	return nil
+}
+
+// checkEIP7594Prover returns an error if the context cannot compute or recover cells and their proofs.
+func (ctx *Context) checkEIP7594Prover() error {
+	if ctx.openKey7594 == nil {
+		return ErrEIP7594Unavailable
+	}
+	if ctx.fk20 == nil {
+		return ErrProverUnavailable
+	}
+	return nil
+}
+
+// checkEIP7594Verifier returns an error if the context cannot verify cell proofs.
+func (ctx *Context) checkEIP7594Verifier() error {
+	if ctx.openKey7594 == nil {
+		return ErrEIP7594Unavailable
+	}
+	return nil
 }
@@ -1,49 +1,51 @@
 package goethkzg
 
 import (
+	"context"
 	"slices"
 
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/crate-crypto/go-eth-kzg/internal/domain"
 	kzgmulti "github.com/crate-crypto/go-eth-kzg/internal/kzg_multi"
+	"github.com/crate-crypto/go-eth-kzg/internal/utils"
 )
 
-func (ctx *Context) ComputeCells(blob *Blob, numGoRoutines int) ([CellsPerExtBlob]*Cell, error) {
-	polynomial, err := DeserializeBlob(blob)
-	if err != nil {
+func (c *Context) ComputeCells(blob *Blob, numGoRoutines int) ([CellsPerExtBlob]*Cell, error) {
+	if err := c.checkFixedSizeTypes(); err != nil {
 		return [CellsPerExtBlob]*Cell{}, err
 	}
 
-	// Bit reverse the polynomial representing the Blob so that it is in normal order
-	domain.BitReverse(polynomial)
+	polyCoeff, err := c.blobToPolyCoeff(context.Background(), blobBytes(blob))
+	if err != nil {
+		return [CellsPerExtBlob]*Cell{}, err
+	}
 
-	// Convert the polynomial in lagrange form to a polynomial in monomial form (in place)
-	ctx.domain.IfftFr(polynomial)
-	polyCoeff := polynomial
+	return c.computeCellsFromPolyCoeff(context.Background(), polyCoeff, numGoRoutines)
+}
 
-	return ctx.computeCellsFromPolyCoeff(polyCoeff, numGoRoutines)
+func (c *Context) ComputeCellsAndKZGProofs(blob *Blob, numGoRoutines int) ([CellsPerExtBlob]*Cell, [CellsPerExtBlob]KZGProof, error) {
+	return c.ComputeCellsAndKZGProofsCtx(context.Background(), blob, numGoRoutines)
 }
 
-func (ctx *Context) ComputeCellsAndKZGProofs(blob *Blob, numGoRoutines int) ([CellsPerExtBlob]*Cell, [CellsPerExtBlob]KZGProof, error) {
-	polynomial, err := DeserializeBlob(blob)
-	if err != nil {
+// ComputeCellsAndKZGProofsCtx is the same as [Context.ComputeCellsAndKZGProofs], but stops early and returns
+// ctx.Err() if `ctx` is cancelled or its deadline passes.
+func (c *Context) ComputeCellsAndKZGProofsCtx(ctx context.Context, blob *Blob, numGoRoutines int) ([CellsPerExtBlob]*Cell, [CellsPerExtBlob]KZGProof, error) {
+	if err := c.checkFixedSizeTypes(); err != nil {
 		return [CellsPerExtBlob]*Cell{}, [CellsPerExtBlob]KZGProof{}, err
 	}
 
-	// Bit reverse the polynomial representing the Blob so that it is in normal order
-	domain.BitReverse(polynomial)
-
-	// Convert the polynomial in lagrange form to a polynomial in monomial form (in place)
-	ctx.domain.IfftFr(polynomial)
-	polyCoeff := polynomial
+	polyCoeff, err := c.blobToPolyCoeff(ctx, blobBytes(blob))
+	if err != nil {
+		return [CellsPerExtBlob]*Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
 
-	cells, err := ctx.computeCellsFromPolyCoeff(polyCoeff, numGoRoutines)
+	cells, err := c.computeCellsFromPolyCoeff(ctx, polyCoeff, numGoRoutines)
 	if err != nil {
 		return [CellsPerExtBlob]*Cell{}, [CellsPerExtBlob]KZGProof{}, err
 	}
 
-	proofs, err := ctx.computeKZGProofsFromPolyCoeff(polyCoeff, numGoRoutines)
+	proofs, err := c.computeKZGProofsFromPolyCoeff(ctx, polyCoeff, numGoRoutines)
 	if err != nil {
 		return [CellsPerExtBlob]*Cell{}, [CellsPerExtBlob]KZGProof{}, err
 	}
@@ -51,14 +53,253 @@ func (ctx *Context) ComputeCellsAndKZGProofs(blob *Blob, numGoRoutines int) ([Ce
 	return cells, proofs, nil
 }
 
-func (ctx *Context) computeCellsFromPolyCoeff(polyCoeff []fr.Element, _ int) ([CellsPerExtBlob]*Cell, error) {
-	cosetEvaluations := ctx.fk20.ComputeExtendedPolynomial(polyCoeff)
+// ComputeCellsAndKZGProofsBatch computes the cells and proofs for each of the `blobs`, in the same way as
+// [Context.ComputeCellsAndKZGProofs] does for a single blob.
+//
+// The results are in column-major order: cells[i][j] is the i'th cell of blobs[j], and proofs[i][j] is its proof.
+// This is the order in which cells are grouped into a DataColumnSidecar.
+//
+// numGoRoutines bounds the number of go-routines used for the whole batch. Setting this value to a negative number
+// or 0 will make it default to the number of CPUs. The blobs are handed out to a pool of workers, and when there
+// are fewer blobs than go-routines, the rest are used to speed up each blob.
+//
+// If a blob cannot be deserialized, the first such blob is reported as a [BatchError].
+func (c *Context) ComputeCellsAndKZGProofsBatch(blobs []*Blob, numGoRoutines int) ([CellsPerExtBlob][]*Cell, [CellsPerExtBlob][]KZGProof, error) {
+	if err := c.checkFixedSizeTypes(); err != nil {
+		return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, err
+	}
+	if err := c.checkEIP7594Prover(); err != nil {
+		return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, err
+	}
+
+	numBlobs := len(blobs)
+	numGoRoutines = utils.ResolveNumGoRoutines(numGoRoutines)
+
+	// 1. Deserialize all of the blobs in parallel.
+	//
+	// The polynomials share a single scratch buffer, which is turned into their monomial form in-place.
+	polys := make([]fr.Element, numBlobs*ScalarsPerBlob)
+	errs := make([]error, numBlobs)
+	utils.ParallelFor(numBlobs, numGoRoutines, func(start, end int) {
+		for i := start; i < end; i++ {
+			if err := c.deserializeBlobInto(polys[i*ScalarsPerBlob:(i+1)*ScalarsPerBlob], blobBytes(blobs[i])); err != nil {
+				errs[i] = newBatchError(i, ErrorKindMalformedBlob, err)
+			}
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, err
+		}
+	}
+
+	// 2. Compute the cells and proofs for each blob.
+	//
+	// All of the cells share a single allocation, laid out in column-major order, as do the proofs.
+	cellsBuf := make([]Cell, CellsPerExtBlob*numBlobs)
+	proofsBuf := make([]KZGProof, CellsPerExtBlob*numBlobs)
+	var cells [CellsPerExtBlob][]*Cell
+	var proofs [CellsPerExtBlob][]KZGProof
+	for column := 0; column < CellsPerExtBlob; column++ {
+		cells[column] = make([]*Cell, numBlobs)
+		for row := 0; row < numBlobs; row++ {
+			cells[column][row] = &cellsBuf[column*numBlobs+row]
+		}
+		proofs[column] = proofsBuf[column*numBlobs : (column+1)*numBlobs : (column+1)*numBlobs]
+	}
+
+	numWorkers := max(1, min(numGoRoutines, numBlobs))
+	goRoutinesPerBlob := max(1, numGoRoutines/numWorkers)
+	utils.ParallelForEach(numBlobs, numWorkers, func(row int) {
+		polyCoeff := polys[row*ScalarsPerBlob : (row+1)*ScalarsPerBlob]
+
+		// Bit reverse the polynomial representing the Blob so that it is in normal order
+		domain.BitReverse(polyCoeff)
+		// Convert the polynomial in lagrange form to a polynomial in monomial form (in place)
+		c.domain.IfftFr(polyCoeff)
+
+		cosetEvaluations, err := c.fk20.ComputeExtendedPolynomial(context.Background(), polyCoeff, goRoutinesPerBlob)
+		if err != nil {
+			errs[row] = err
+			return
+		}
+		for column, cosetEval := range cosetEvaluations {
+			serializeScalars(cells[column][row][:], cosetEval)
+		}
+
+		proofsG1, err := kzgmulti.ComputeMultiPointKZGProofs(context.Background(), c.fk20, polyCoeff, goRoutinesPerBlob)
+		if err != nil {
+			errs[row] = err
+			return
+		}
+		for column, proof := range proofsG1 {
+			proofs[column][row] = KZGProof(SerializeG1Point(proof))
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, err
+		}
+	}
+
+	return cells, proofs, nil
+}
+
+// ComputeCellsAndKZGProofsBytes is the same as [Context.ComputeCellsAndKZGProofs] for a blob whose size is given by
+// the context parameters. See [Params].
+//
+// The result holds [Params.CellsPerExtBlob] cells and proofs.
+func (c *Context) ComputeCellsAndKZGProofsBytes(blob []byte, numGoRoutines int) ([][]byte, []KZGProof, error) {
+	polyCoeff, err := c.blobToPolyCoeff(context.Background(), blob)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cells, err := c.computeCellBytesFromPolyCoeff(context.Background(), polyCoeff, numGoRoutines)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proofs, err := c.computeProofsFromPolyCoeff(context.Background(), polyCoeff, numGoRoutines)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cells, proofs, nil
+}
+
+// ComputeCellsForIndices computes the cells at `cellIndices` of the extended blob. These are the same as the
+// corresponding cells returned by [Context.ComputeCells], and are returned in the same order as `cellIndices`.
+//
+// This is useful for nodes that only custody a few of the columns. When there are only a few indices, each cell is
+// computed on its own, which is much cheaper than computing all of them.
+func (c *Context) ComputeCellsForIndices(blob *Blob, cellIndices []uint64, numGoRoutines int) ([]*Cell, error) {
+	if err := c.checkFixedSizeTypes(); err != nil {
+		return nil, err
+	}
+	if err := c.checkCellIndices(cellIndices); err != nil {
+		return nil, err
+	}
+
+	polyCoeff, err := c.blobToPolyCoeff(context.Background(), blobBytes(blob))
+	if err != nil {
+		return nil, err
+	}
+
+	cosetEvaluations, err := c.fk20.ComputeExtendedPolynomialForCosets(context.Background(), polyCoeff, cellIndices, numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+
+	cells := make([]*Cell, len(cosetEvaluations))
+	for i, cosetEval := range cosetEvaluations {
+		if len(cosetEval) != scalarsPerCell {
+			return nil, ErrCosetEvaluationLengthCheck
+		}
+		cells[i] = serializeEvaluations((*[scalarsPerCell]fr.Element)(cosetEval))
+	}
+
+	return cells, nil
+}
+
+// ComputeCellKZGProofsForIndices computes the proofs for the cells at `cellIndices` of the extended blob. These are
+// the same as the corresponding proofs returned by [Context.ComputeCellsAndKZGProofs], and are returned in the same
+// order as `cellIndices`.
+//
+// When there are only a few indices, the proof for each cell is computed on its own, which is much cheaper than
+// computing all of them with FK20.
+func (c *Context) ComputeCellKZGProofsForIndices(blob *Blob, cellIndices []uint64, numGoRoutines int) ([]KZGProof, error) {
+	if err := c.checkFixedSizeTypes(); err != nil {
+		return nil, err
+	}
+	if err := c.checkCellIndices(cellIndices); err != nil {
+		return nil, err
+	}
+
+	polyCoeff, err := c.blobToPolyCoeff(context.Background(), blobBytes(blob))
+	if err != nil {
+		return nil, err
+	}
+
+	proofs, err := kzgmulti.ComputeMultiPointKZGProofsForCosets(context.Background(), c.fk20, c.commitKeyMonomial, polyCoeff, cellIndices, numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedProofs := make([]KZGProof, len(proofs))
+	for i, proof := range proofs {
+		serializedProofs[i] = KZGProof(SerializeG1Point(proof))
+	}
+
+	return serializedProofs, nil
+}
+
+// checkCellIndices checks that every cell index refers to a cell of the extended blob. The first index that does not
+// is reported as a [BatchError].
+func (c *Context) checkCellIndices(cellIndices []uint64) error {
+	for i, cellIndex := range cellIndices {
+		if cellIndex >= c.params.CellsPerExtBlob() {
+			return newBatchError(i, ErrorKindInvalidCellIndex, ErrInvalidCellID)
+		}
+	}
+	return nil
+}
+
+// blobToPolyCoeff deserializes a blob and returns the polynomial that it represents in monomial form.
+//
+// It is the first step of every method that computes cells, so it also checks that the context can compute them.
+func (c *Context) blobToPolyCoeff(ctx context.Context, blob []byte) ([]fr.Element, error) {
+	if err := c.checkEIP7594Prover(); err != nil {
+		return nil, err
+	}
+
+	polynomial, err := c.deserializeBlob(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bit reverse the polynomial representing the Blob so that it is in normal order
+	domain.BitReverse(polynomial)
+
+	// Convert the polynomial in lagrange form to a polynomial in monomial form (in place)
+	if err := c.domain.IfftFrCtx(ctx, polynomial, 1); err != nil {
+		return nil, err
+	}
+
+	return polynomial, nil
+}
+
+func (c *Context) computeCellsFromPolyCoeff(ctx context.Context, polyCoeff []fr.Element, numGoRoutines int) ([CellsPerExtBlob]*Cell, error) {
+	cosetEvaluations, err := c.fk20.ComputeExtendedPolynomial(ctx, polyCoeff, numGoRoutines)
+	if err != nil {
+		return [CellsPerExtBlob]*Cell{}, err
+	}
 
 	return serializeCells(cosetEvaluations)
 }
 
-func (ctx *Context) computeKZGProofsFromPolyCoeff(polyCoeff []fr.Element, _ int) ([CellsPerExtBlob]KZGProof, error) {
-	proofs, err := kzgmulti.ComputeMultiPointKZGProofs(ctx.fk20, polyCoeff)
+// computeCellBytesFromPolyCoeff is the same as computeCellsFromPolyCoeff, but returns cells whose size
+// is given by the context parameters.
+func (c *Context) computeCellBytesFromPolyCoeff(ctx context.Context, polyCoeff []fr.Element, numGoRoutines int) ([][]byte, error) {
+	cosetEvaluations, err := c.fk20.ComputeExtendedPolynomial(ctx, polyCoeff, numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+
+	// All of the cells share a single allocation
+	cellSize := c.params.BytesPerCell()
+	buf := make([]byte, len(cosetEvaluations)*cellSize)
+	cells := make([][]byte, len(cosetEvaluations))
+	for i, cosetEval := range cosetEvaluations {
+		cells[i] = buf[i*cellSize : (i+1)*cellSize : (i+1)*cellSize]
+		serializeScalars(cells[i], cosetEval)
+	}
+
+	return cells, nil
+}
+
+func (c *Context) computeKZGProofsFromPolyCoeff(ctx context.Context, polyCoeff []fr.Element, numGoRoutines int) ([CellsPerExtBlob]KZGProof, error) {
+	proofs, err := c.computeProofsFromPolyCoeff(ctx, polyCoeff, numGoRoutines)
 	if err != nil {
 		return [CellsPerExtBlob]KZGProof{}, err
 	}
@@ -67,8 +308,18 @@ func (ctx *Context) computeKZGProofsFromPolyCoeff(polyCoeff []fr.Element, _ int)
 		return [CellsPerExtBlob]KZGProof{}, ErrNumProofsCheck
 	}
 
+	return [CellsPerExtBlob]KZGProof(proofs), nil
+}
+
+// computeProofsFromPolyCoeff computes and serializes the proofs for every cell of the extended blob.
+func (c *Context) computeProofsFromPolyCoeff(ctx context.Context, polyCoeff []fr.Element, numGoRoutines int) ([]KZGProof, error) {
+	proofs, err := kzgmulti.ComputeMultiPointKZGProofs(ctx, c.fk20, polyCoeff, numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+
 	// Serialize proofs
-	var serializedProofs [CellsPerExtBlob]KZGProof
+	serializedProofs := make([]KZGProof, len(proofs))
 	for i, proof := range proofs {
 		serializedProofs[i] = KZGProof(SerializeG1Point(proof))
 	}
@@ -90,7 +341,11 @@ func serializeCells(cosetEvaluations [][]fr.Element) ([CellsPerExtBlob]*Cell, er
 	return Cells, nil
 }
 
-func (ctx *Context) recoverPolynomialCoeffs(cellIDs []uint64, cells []*Cell) ([]fr.Element, error) {
+func (c *Context) recoverPolynomialCoeffs(ctx context.Context, cellIDs []uint64, cells [][]byte) ([]fr.Element, error) {
+	if err := c.checkEIP7594Prover(); err != nil {
+		return nil, err
+	}
+
 	if len(cellIDs) != len(cells) {
 		return nil, ErrNumCellIDsNotEqualNumCells
 	}
@@ -101,34 +356,32 @@ func (ctx *Context) recoverPolynomialCoeffs(cellIDs []uint64, cells []*Cell) ([]
 	}
 
 	// Check that each CellId is less than CellsPerExtBlob
+	cellsPerExtBlob := c.params.CellsPerExtBlob()
 	for _, cellID := range cellIDs {
-		if cellID >= CellsPerExtBlob {
+		if cellID >= cellsPerExtBlob {
 			return nil, ErrFoundInvalidCellID
 		}
 	}
 
 	// Check that we have enough cells to perform reconstruction
-	if len(cellIDs) < ctx.dataRecovery.NumBlocksNeededToReconstruct() {
+	if len(cellIDs) < c.dataRecovery.NumBlocksNeededToReconstruct() {
 		return nil, ErrNotEnoughCellsForReconstruction
 	}
 
-	// Find the missing cell IDs and bit reverse them
-	// So that they are in normal order
-	missingCellIds := make([]uint64, 0, CellsPerExtBlob)
-	for cellID := uint64(0); cellID < CellsPerExtBlob; cellID++ {
-		if !slices.Contains(cellIDs, cellID) {
-			missingCellIds = append(missingCellIds, (domain.BitReverseInt(cellID, CellsPerExtBlob)))
-		}
-	}
+	missingCellIds := c.missingCellIDs(cellIDs)
 
 	// Convert Cells to field elements
-	extendedBlob := make([]fr.Element, scalarsPerExtBlob)
+	scalarsPerCell := c.params.FieldElementsPerCell
+	extendedBlob := make([]fr.Element, c.params.FieldElementsPerExtBlob())
 	// for each cellId, we get the corresponding cell in cells
 	// then use the cellId to place the cell in the correct position in the data(extendedBlob) array
 	for i, cellID := range cellIDs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		cell := cells[i]
 		// Deserialize the cell
-		cellEvals, err := deserializeCell(cell)
+		cellEvals, err := c.deserializeCell(cell)
 		if err != nil {
 			return nil, err
 		}
@@ -138,21 +391,43 @@ func (ctx *Context) recoverPolynomialCoeffs(cellIDs []uint64, cells []*Cell) ([]
 	// Bit reverse the extendedBlob so that it is in normal order
 	domain.BitReverse(extendedBlob)
 
-	return ctx.dataRecovery.RecoverPolynomialCoefficients(extendedBlob, missingCellIds)
+	return c.dataRecovery.RecoverPolynomialCoefficients(ctx, extendedBlob, missingCellIds)
 }
 
-func (ctx *Context) RecoverCellsAndComputeKZGProofs(cellIDs []uint64, cells []*Cell, numGoRoutines int) ([CellsPerExtBlob]*Cell, [CellsPerExtBlob]KZGProof, error) {
-	polyCoeff, err := ctx.recoverPolynomialCoeffs(cellIDs, cells)
+// missingCellIDs returns the IDs of the cells that are not in `cellIDs`, bit reversed so that they are in normal order.
+func (c *Context) missingCellIDs(cellIDs []uint64) []uint64 {
+	cellsPerExtBlob := c.params.CellsPerExtBlob()
+	missingCellIds := make([]uint64, 0, cellsPerExtBlob)
+	for cellID := uint64(0); cellID < cellsPerExtBlob; cellID++ {
+		if !slices.Contains(cellIDs, cellID) {
+			missingCellIds = append(missingCellIds, (domain.BitReverseInt(cellID, cellsPerExtBlob)))
+		}
+	}
+	return missingCellIds
+}
+
+func (c *Context) RecoverCellsAndComputeKZGProofs(cellIDs []uint64, cells []*Cell, numGoRoutines int) ([CellsPerExtBlob]*Cell, [CellsPerExtBlob]KZGProof, error) {
+	return c.RecoverCellsAndComputeKZGProofsCtx(context.Background(), cellIDs, cells, numGoRoutines)
+}
+
+// RecoverCellsAndComputeKZGProofsCtx is the same as [Context.RecoverCellsAndComputeKZGProofs], but stops early and
+// returns ctx.Err() if `ctx` is cancelled or its deadline passes.
+func (c *Context) RecoverCellsAndComputeKZGProofsCtx(ctx context.Context, cellIDs []uint64, cells []*Cell, numGoRoutines int) ([CellsPerExtBlob]*Cell, [CellsPerExtBlob]KZGProof, error) {
+	if err := c.checkFixedSizeTypes(); err != nil {
+		return [CellsPerExtBlob]*Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	polyCoeff, err := c.recoverPolynomialCoeffs(ctx, cellIDs, cellsBytes(cells))
 	if err != nil {
 		return [CellsPerExtBlob]*Cell{}, [CellsPerExtBlob]KZGProof{}, err
 	}
 
-	recoveredCells, err := ctx.computeCellsFromPolyCoeff(polyCoeff, numGoRoutines)
+	recoveredCells, err := c.computeCellsFromPolyCoeff(ctx, polyCoeff, numGoRoutines)
 	if err != nil {
 		return [CellsPerExtBlob]*Cell{}, [CellsPerExtBlob]KZGProof{}, err
 	}
 
-	proofs, err := ctx.computeKZGProofsFromPolyCoeff(polyCoeff, numGoRoutines)
+	proofs, err := c.computeKZGProofsFromPolyCoeff(ctx, polyCoeff, numGoRoutines)
 	if err != nil {
 		return [CellsPerExtBlob]*Cell{}, [CellsPerExtBlob]KZGProof{}, err
 	}
@@ -160,14 +435,67 @@ func (ctx *Context) RecoverCellsAndComputeKZGProofs(cellIDs []uint64, cells []*C
 	return recoveredCells, proofs, nil
 }
 
-func (ctx *Context) VerifyCellKZGProofBatch(commitments []KZGCommitment, cellIndices []uint64, cells []*Cell, proofs []KZGProof) error {
+// RecoverCellsAndComputeKZGProofsBytes is the same as [Context.RecoverCellsAndComputeKZGProofs] for cells whose size
+// is given by the context parameters. See [Params].
+func (c *Context) RecoverCellsAndComputeKZGProofsBytes(cellIDs []uint64, cells [][]byte, numGoRoutines int) ([][]byte, []KZGProof, error) {
+	polyCoeff, err := c.recoverPolynomialCoeffs(context.Background(), cellIDs, cells)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recoveredCells, err := c.computeCellBytesFromPolyCoeff(context.Background(), polyCoeff, numGoRoutines)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proofs, err := c.computeProofsFromPolyCoeff(context.Background(), polyCoeff, numGoRoutines)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return recoveredCells, proofs, nil
+}
+
+// VerifyCellKZGProofBatch implements [verify_cell_kzg_proof_batch].
+//
+// Errors that are caused by the inputs are returned as a [BatchError], whose Index is the position of the offending
+// cell in the batch. If the cells are well-formed but the proofs do not verify, the error wraps
+// [ErrVerifyOpeningProof]. Since the proofs are checked together, the Index is then only known for a batch of one.
+// [Context.FindInvalidCellProofs] can be used to find the proofs that failed.
+//
+// [verify_cell_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#verify_cell_kzg_proof_batch
+func (c *Context) VerifyCellKZGProofBatch(commitments []KZGCommitment, cellIndices []uint64, cells []*Cell, proofs []KZGProof) error {
+	return c.VerifyCellKZGProofBatchCtx(context.Background(), commitments, cellIndices, cells, proofs)
+}
+
+// VerifyCellKZGProofBatchCtx is the same as [Context.VerifyCellKZGProofBatch], but stops early and returns ctx.Err()
+// if `ctx` is cancelled or its deadline passes.
+func (c *Context) VerifyCellKZGProofBatchCtx(ctx context.Context, commitments []KZGCommitment, cellIndices []uint64, cells []*Cell, proofs []KZGProof) error {
+	if err := c.checkFixedSizeTypes(); err != nil {
+		return err
+	}
+
+	return c.verifyCellKZGProofBatch(ctx, commitments, cellIndices, cellsBytes(cells), proofs)
+}
+
+// VerifyCellKZGProofBatchBytes is the same as [Context.VerifyCellKZGProofBatch] for cells whose size is given by the
+// context parameters. See [Params].
+func (c *Context) VerifyCellKZGProofBatchBytes(commitments []KZGCommitment, cellIndices []uint64, cells [][]byte, proofs []KZGProof) error {
+	return c.verifyCellKZGProofBatch(context.Background(), commitments, cellIndices, cells, proofs)
+}
+
+func (c *Context) verifyCellKZGProofBatch(ctx context.Context, commitments []KZGCommitment, cellIndices []uint64, cells [][]byte, proofs []KZGProof) error {
+	if err := c.checkEIP7594Verifier(); err != nil {
+		return err
+	}
+
 	rowCommitments, rowIndices := deduplicateKZGCommitments(commitments)
 
 	// Check that all components in the batch have the same size, expect the rowCommitments
 	batchSize := len(rowIndices)
 	lengthsAreEqual := batchSize == len(cellIndices) && batchSize == len(cells) && batchSize == len(proofs)
 	if !lengthsAreEqual {
-		return ErrBatchLengthCheck
+		return newBatchError(-1, ErrorKindBatchLength, ErrBatchLengthCheck)
 	}
 
 	if batchSize == 0 {
@@ -181,37 +509,49 @@ func (ctx *Context) VerifyCellKZGProofBatch(commitments []KZGCommitment, cellInd
 		}
 	}
 
-	for _, cellIndex := range cellIndices {
-		if cellIndex >= CellsPerExtBlob {
-			return ErrInvalidCellID
-		}
+	if err := c.checkCellIndices(cellIndices); err != nil {
+		return err
 	}
 
 	commitmentsG1 := make([]bls12381.G1Affine, len(rowCommitments))
 	for i := 0; i < len(rowCommitments); i++ {
 		comm, err := DeserializeKZGCommitment(rowCommitments[i])
 		if err != nil {
-			return err
+			// Report the first cell that refers to this commitment
+			return newBatchError(slices.Index(rowIndices, uint64(i)), ErrorKindMalformedCommitment, err)
 		}
 		commitmentsG1[i] = comm
 	}
 	proofsG1 := make([]bls12381.G1Affine, len(proofs))
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	for i := 0; i < len(proofs); i++ {
 		proof, err := DeserializeKZGProof(proofs[i])
 		if err != nil {
-			return err
+			return newBatchError(i, ErrorKindMalformedProof, err)
 		}
 		proofsG1[i] = proof
 	}
 	cosetsEvals := make([][]fr.Element, len(cells))
 	for i := 0; i < len(cells); i++ {
-		cosetEvals, err := deserializeCell(cells[i])
-		if err != nil {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
+		cosetEvals, err := c.deserializeCell(cells[i])
+		if err != nil {
+			return newBatchError(i, ErrorKindMalformedCell, err)
+		}
 		cosetsEvals[i] = cosetEvals
 	}
-	return kzgmulti.VerifyMultiPointKZGProofBatch(commitmentsG1, rowIndices, cellIndices, proofsG1, cosetsEvals, ctx.openKey7594)
+
+	r, err := c.batchVerificationScalar(func() fr.Element {
+		return computeCellBatchChallenge(c.params, rowCommitments, rowIndices, cellIndices, cells, proofs)
+	})
+	if err != nil {
+		return err
+	}
+	return verificationError(batchSize, kzgmulti.VerifyMultiPointKZGProofBatch(ctx, commitmentsG1, rowIndices, cellIndices, proofsG1, cosetsEvals, r, c.openKey7594))
 }
 
 // isAscending checks if a uint64 slice is in ascending order
@@ -1,8 +1,14 @@
 package goethkzg_test
 
 import (
+	"bytes"
+	"context"
+	"io"
 	"math/big"
+	"math/rand"
+	"slices"
 	"testing"
+	"time"
 
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -78,6 +84,112 @@ func TestNonCanonicalSmoke(t *testing.T) {
 	require.Error(t, err, "expected an error since blob was not canonical")
 }
 
+func TestNewVerifierContext(t *testing.T) {
+	setup := newInsecureSetup(1337, int(smallParams.FieldElementsPerBlob), int(smallParams.FieldElementsPerCell)+1)
+	fullCtx, err := goethkzg.NewContextWithParams(setup, smallParams)
+	require.NoError(t, err)
+
+	// The verifier only needs the G2 points and as many monomial G1 points
+	setup.SetupG1Lagrange = nil
+	setup.SetupG1Monomial = setup.SetupG1Monomial[:len(setup.SetupG2)]
+	verifierCtx, err := goethkzg.NewVerifierContext(setup, smallParams)
+	require.NoError(t, err)
+
+	blob := getRandBlobBytes(smallParams, 1)
+	commitment, err := fullCtx.BlobToKZGCommitmentBytes(blob, NumGoRoutines)
+	require.NoError(t, err)
+	blobProof, err := fullCtx.ComputeBlobKZGProofBytes(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	inputPoint := GetRandFieldElement(2)
+	proof, claimedValue, err := fullCtx.ComputeKZGProofBytes(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	cells, cellProofs, err := fullCtx.ComputeCellsAndKZGProofsBytes(blob, NumGoRoutines)
+	require.NoError(t, err)
+	commitments := make([]goethkzg.KZGCommitment, len(cells))
+	cellIndices := make([]uint64, len(cells))
+	for i := range cells {
+		commitments[i] = commitment
+		cellIndices[i] = uint64(i)
+	}
+
+	verifyAll := func(verifierCtx *goethkzg.Context) {
+		require.NoError(t, verifierCtx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof))
+		require.NoError(t, verifierCtx.VerifyBlobKZGProofBytes(blob, commitment, blobProof))
+		require.NoError(t, verifierCtx.VerifyBlobKZGProofBatchBytes([][]byte{blob}, []goethkzg.KZGCommitment{commitment}, []goethkzg.KZGProof{blobProof}))
+		require.NoError(t, verifierCtx.VerifyCellKZGProofBatchBytes(commitments, cellIndices, cells, cellProofs))
+
+		_, err := verifierCtx.BlobToKZGCommitmentBytes(blob, NumGoRoutines)
+		require.ErrorIs(t, err, goethkzg.ErrProverUnavailable)
+		_, err = verifierCtx.ComputeBlobKZGProofBytes(blob, commitment, NumGoRoutines)
+		require.ErrorIs(t, err, goethkzg.ErrProverUnavailable)
+		_, _, err = verifierCtx.ComputeKZGProofBytes(blob, inputPoint, NumGoRoutines)
+		require.ErrorIs(t, err, goethkzg.ErrProverUnavailable)
+		_, _, err = verifierCtx.ComputeCellsAndKZGProofsBytes(blob, NumGoRoutines)
+		require.ErrorIs(t, err, goethkzg.ErrProverUnavailable)
+		_, _, err = verifierCtx.RecoverCellsAndComputeKZGProofsBytes(cellIndices, cells, NumGoRoutines)
+		require.ErrorIs(t, err, goethkzg.ErrProverUnavailable)
+	}
+	verifyAll(verifierCtx)
+
+	// The serialized verifier context should be much smaller than the full one
+	// and keep the same capabilities once it is loaded
+	serialized, err := verifierCtx.MarshalBinary()
+	require.NoError(t, err)
+	serializedFull, err := fullCtx.MarshalBinary()
+	require.NoError(t, err)
+	require.Less(t, 4*len(serialized), len(serializedFull))
+
+	loaded, err := goethkzg.UnmarshalContext(serialized)
+	require.NoError(t, err)
+	verifyAll(loaded)
+
+	// The verifier still needs more G2 points than there are field elements in a cell
+	setup.SetupG2 = setup.SetupG2[:smallParams.FieldElementsPerCell]
+	_, err = goethkzg.NewVerifierContext(setup, smallParams)
+	require.ErrorIs(t, err, goethkzg.ErrInvalidTrustedSetupLen)
+}
+
+func TestNewContext4844Only(t *testing.T) {
+	setup := newInsecureSetup(1337, int(smallParams.FieldElementsPerBlob), int(smallParams.FieldElementsPerCell)+1)
+	fullCtx, err := goethkzg.NewContextWithParams(setup, smallParams)
+	require.NoError(t, err)
+
+	// Only the lagrange points are needed for EIP-4844
+	setup.SetupG1Monomial = nil
+	ctx4844, err := goethkzg.NewContext4844Only(setup, smallParams)
+	require.NoError(t, err)
+
+	check := func(ctx4844 *goethkzg.Context) {
+		blob := getRandBlobBytes(smallParams, 1)
+		expectedCommitment, err := fullCtx.BlobToKZGCommitmentBytes(blob, NumGoRoutines)
+		require.NoError(t, err)
+		commitment, err := ctx4844.BlobToKZGCommitmentBytes(blob, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expectedCommitment, commitment)
+
+		blobProof, err := ctx4844.ComputeBlobKZGProofBytes(blob, commitment, NumGoRoutines)
+		require.NoError(t, err)
+		require.NoError(t, ctx4844.VerifyBlobKZGProofBytes(blob, commitment, blobProof))
+
+		cells, proofs, err := fullCtx.ComputeCellsAndKZGProofsBytes(blob, NumGoRoutines)
+		require.NoError(t, err)
+
+		_, _, err = ctx4844.ComputeCellsAndKZGProofsBytes(blob, NumGoRoutines)
+		require.ErrorIs(t, err, goethkzg.ErrEIP7594Unavailable)
+		_, _, err = ctx4844.RecoverCellsAndComputeKZGProofsBytes([]uint64{0}, cells[:1], NumGoRoutines)
+		require.ErrorIs(t, err, goethkzg.ErrEIP7594Unavailable)
+		err = ctx4844.VerifyCellKZGProofBatchBytes([]goethkzg.KZGCommitment{commitment}, []uint64{0}, cells[:1], proofs[:1])
+		require.ErrorIs(t, err, goethkzg.ErrEIP7594Unavailable)
+	}
+	check(ctx4844)
+
+	serialized, err := ctx4844.MarshalBinary()
+	require.NoError(t, err)
+	loaded, err := goethkzg.UnmarshalContext(serialized)
+	require.NoError(t, err)
+	check(loaded)
+}
+
 // Below are helper methods which allow us to change a serialized element into
 // its non-canonical counterpart by adding the modulus
 func modifyBlob(blob *goethkzg.Blob, newValue goethkzg.Scalar, index int) {
@@ -120,3 +232,493 @@ func addModP(x big.Int) big.Int {
 
 	return xPlusModulus
 }
+
+func TestCtxMethods(t *testing.T) {
+	blob := GetRandBlob(5)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	blobProof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+
+	// With a context that is never cancelled, the results should match the methods without a context
+	expectedCells, expectedProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofsCtx(context.Background(), blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, cells)
+	require.Equal(t, expectedProofs, proofs)
+
+	commitments := make([]goethkzg.KZGCommitment, goethkzg.CellsPerExtBlob)
+	cellIndices := make([]uint64, goethkzg.CellsPerExtBlob)
+	for i := range commitments {
+		commitments[i] = commitment
+		cellIndices[i] = uint64(i)
+	}
+	require.NoError(t, ctx.VerifyCellKZGProofBatchCtx(context.Background(), commitments, cellIndices, cells[:], proofs[:]))
+
+	cellIDs := make([]uint64, 0, goethkzg.CellsPerExtBlob/2)
+	halfCells := make([]*goethkzg.Cell, 0, goethkzg.CellsPerExtBlob/2)
+	for i := 0; i < goethkzg.CellsPerExtBlob; i += 2 {
+		cellIDs = append(cellIDs, uint64(i))
+		halfCells = append(halfCells, cells[i])
+	}
+	recoveredCells, recoveredProofs, err := ctx.RecoverCellsAndComputeKZGProofsCtx(context.Background(), cellIDs, halfCells, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, recoveredCells)
+	require.Equal(t, expectedProofs, recoveredProofs)
+
+	blobs := []*goethkzg.Blob{blob, blob}
+	blobCommitments := []goethkzg.KZGCommitment{commitment, commitment}
+	blobProofs := []goethkzg.KZGProof{blobProof, blobProof}
+	require.NoError(t, ctx.VerifyBlobKZGProofBatchParCtx(context.Background(), blobs, blobCommitments, blobProofs))
+
+	// An invalid proof should still be reported, rather than the cancellation of the other go-routines
+	otherBlob := GetRandBlob(6)
+	err = ctx.VerifyBlobKZGProofBatchParCtx(context.Background(), []*goethkzg.Blob{blob, otherBlob}, blobCommitments, blobProofs)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, context.Canceled)
+
+	// A context that is already cancelled should stop every method
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err = ctx.ComputeCellsAndKZGProofsCtx(cancelledCtx, blob, NumGoRoutines)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, _, err = ctx.RecoverCellsAndComputeKZGProofsCtx(cancelledCtx, cellIDs, halfCells, NumGoRoutines)
+	require.ErrorIs(t, err, context.Canceled)
+
+	err = ctx.VerifyCellKZGProofBatchCtx(cancelledCtx, commitments, cellIndices, cells[:], proofs[:])
+	require.ErrorIs(t, err, context.Canceled)
+
+	err = ctx.VerifyBlobKZGProofBatchParCtx(cancelledCtx, blobs, blobCommitments, blobProofs)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// A deadline that has passed should be reported as such
+	expiredCtx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	_, _, err = ctx.ComputeCellsAndKZGProofsCtx(expiredCtx, blob, NumGoRoutines)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestComputeCellsAndKZGProofsCtxCancelDuringComputation(t *testing.T) {
+	blob := GetRandBlob(7)
+
+	// Cancel the context once the computation has started. Computing the proofs takes far longer than
+	// this, so the cancellation should be noticed part way through.
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	timer := time.AfterFunc(5*time.Millisecond, cancel)
+	defer timer.Stop()
+
+	start := time.Now()
+	_, _, err := ctx.ComputeCellsAndKZGProofsCtx(cancelCtx, blob, NumGoRoutines)
+	if err == nil {
+		t.Skip("computation finished before the context was cancelled")
+	}
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestComputeCellsAndKZGProofsNumGoRoutines(t *testing.T) {
+	blob := GetRandBlob(8)
+
+	expectedCells, expectedProofs, err := ctx.ComputeCellsAndKZGProofs(blob, 1)
+	require.NoError(t, err)
+
+	for _, numGoRoutines := range []int{2, 3, 16, NumGoRoutines} {
+		cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob, numGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expectedCells, cells, "numGoRoutines: %d", numGoRoutines)
+		require.Equal(t, expectedProofs, proofs, "numGoRoutines: %d", numGoRoutines)
+	}
+}
+
+func TestComputeCellsAndKZGProofsBatch(t *testing.T) {
+	blobs := []*goethkzg.Blob{GetRandBlob(9), GetRandBlob(10), GetRandBlob(11)}
+
+	for _, numGoRoutines := range []int{1, 2, NumGoRoutines} {
+		cells, proofs, err := ctx.ComputeCellsAndKZGProofsBatch(blobs, numGoRoutines)
+		require.NoError(t, err)
+
+		// The results are grouped by column
+		for row, blob := range blobs {
+			expectedCells, expectedProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+			require.NoError(t, err)
+			for column := 0; column < goethkzg.CellsPerExtBlob; column++ {
+				require.Len(t, cells[column], len(blobs))
+				require.Len(t, proofs[column], len(blobs))
+				require.Equal(t, expectedCells[column], cells[column][row])
+				require.Equal(t, expectedProofs[column], proofs[column][row])
+			}
+		}
+	}
+
+	// An empty batch gives empty columns
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofsBatch(nil, NumGoRoutines)
+	require.NoError(t, err)
+	require.Empty(t, cells[0])
+	require.Empty(t, proofs[0])
+
+	// A single invalid blob fails the whole batch
+	blobBad := GetRandBlob(12)
+	modifyBlob(blobBad, nonCanonicalScalar(12), 0)
+	_, _, err = ctx.ComputeCellsAndKZGProofsBatch([]*goethkzg.Blob{blobs[0], blobBad}, NumGoRoutines)
+	require.ErrorIs(t, err, goethkzg.ErrNonCanonicalScalar)
+
+	_, _, err = ctx.ComputeCellsAndKZGProofsBatch([]*goethkzg.Blob{blobs[0], nil}, NumGoRoutines)
+	require.ErrorIs(t, err, goethkzg.ErrDeserializeNilInput)
+}
+
+func TestComputeCellsAndProofsForIndices(t *testing.T) {
+	blob := GetRandBlob(13)
+	expectedCells, expectedProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	everyCell := make([]uint64, goethkzg.CellsPerExtBlob)
+	for i := range everyCell {
+		everyCell[i] = uint64(i)
+	}
+	cellIndexSets := [][]uint64{
+		{},
+		{0},
+		{127, 5, 5},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+		everyCell,
+	}
+
+	for _, cellIndices := range cellIndexSets {
+		cells, err := ctx.ComputeCellsForIndices(blob, cellIndices, NumGoRoutines)
+		require.NoError(t, err)
+		proofs, err := ctx.ComputeCellKZGProofsForIndices(blob, cellIndices, NumGoRoutines)
+		require.NoError(t, err)
+
+		require.Len(t, cells, len(cellIndices))
+		require.Len(t, proofs, len(cellIndices))
+		for i, cellIndex := range cellIndices {
+			require.Equal(t, expectedCells[cellIndex], cells[i])
+			require.Equal(t, expectedProofs[cellIndex], proofs[i])
+		}
+	}
+
+	_, err = ctx.ComputeCellsForIndices(blob, []uint64{goethkzg.CellsPerExtBlob}, NumGoRoutines)
+	require.ErrorIs(t, err, goethkzg.ErrInvalidCellID)
+	_, err = ctx.ComputeCellKZGProofsForIndices(blob, []uint64{goethkzg.CellsPerExtBlob}, NumGoRoutines)
+	require.ErrorIs(t, err, goethkzg.ErrInvalidCellID)
+}
+
+func TestWithDeterministicVerification(t *testing.T) {
+	deterministicCtx := ctx.WithDeterministicVerification()
+
+	blob := GetRandBlob(14)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	blobProof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	commitments := make([]goethkzg.KZGCommitment, goethkzg.CellsPerExtBlob)
+	cellIndices := make([]uint64, goethkzg.CellsPerExtBlob)
+	for i := range commitments {
+		commitments[i] = commitment
+		cellIndices[i] = uint64(i)
+	}
+
+	otherBlob := GetRandBlob(15)
+	otherCommitment, err := ctx.BlobToKZGCommitment(otherBlob, NumGoRoutines)
+	require.NoError(t, err)
+	blobs := []*goethkzg.Blob{blob, otherBlob}
+	blobCommitments := []goethkzg.KZGCommitment{commitment, otherCommitment}
+	badBlobProofs := []goethkzg.KZGProof{blobProof, blobProof}
+	otherBlobProof, err := ctx.ComputeBlobKZGProof(otherBlob, otherCommitment, NumGoRoutines)
+	require.NoError(t, err)
+	blobProofs := []goethkzg.KZGProof{blobProof, otherBlobProof}
+
+	badProofs := proofs
+	badProofs[3] = proofs[4]
+
+	// Verification is repeatable, so every run must give the same outcome
+	for i := 0; i < 2; i++ {
+		require.NoError(t, deterministicCtx.VerifyCellKZGProofBatch(commitments, cellIndices, cells[:], proofs[:]))
+		require.NoError(t, deterministicCtx.VerifyBlobKZGProofBatch(blobs, blobCommitments, blobProofs))
+		require.NoError(t, deterministicCtx.VerifyBlobKZGProofBatchPar(blobs, blobCommitments, blobProofs))
+
+		require.Error(t, deterministicCtx.VerifyCellKZGProofBatch(commitments, cellIndices, cells[:], badProofs[:]))
+		require.Error(t, deterministicCtx.VerifyBlobKZGProofBatch(blobs, blobCommitments, badBlobProofs))
+	}
+
+	// The original context is unchanged and still accepts the same valid batches
+	require.NoError(t, ctx.VerifyCellKZGProofBatch(commitments, cellIndices, cells[:], proofs[:]))
+	require.NoError(t, ctx.VerifyBlobKZGProofBatch(blobs, blobCommitments, blobProofs))
+}
+
+// recordingReader records every byte that is read from the underlying reader.
+type recordingReader struct {
+	reader io.Reader
+	read   bytes.Buffer
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read.Write(p[:n])
+	return n, err
+}
+
+func TestWithRandomness(t *testing.T) {
+	blob := GetRandBlob(16)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	blobProof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	commitments := make([]goethkzg.KZGCommitment, goethkzg.CellsPerExtBlob)
+	cellIndices := make([]uint64, goethkzg.CellsPerExtBlob)
+	for i := range commitments {
+		commitments[i] = commitment
+		cellIndices[i] = uint64(i)
+	}
+	blobs := []*goethkzg.Blob{blob, blob}
+	blobCommitments := []goethkzg.KZGCommitment{commitment, commitment}
+	blobProofs := []goethkzg.KZGProof{blobProof, blobProof}
+
+	// verify runs every randomised verification method and returns the randomness they consumed
+	verify := func(seed int64) []byte {
+		reader := &recordingReader{reader: rand.New(rand.NewSource(seed))}
+		seededCtx := ctx.WithRandomness(reader)
+		require.NoError(t, seededCtx.VerifyCellKZGProofBatch(commitments, cellIndices, cells[:], proofs[:]))
+		require.NoError(t, seededCtx.VerifyBlobKZGProofBatch(blobs, blobCommitments, blobProofs))
+		return reader.read.Bytes()
+	}
+
+	// The same seed gives the same random numbers, so a verification can be replayed
+	first := verify(42)
+	require.NotEmpty(t, first)
+	require.Equal(t, first, verify(42))
+	require.NotEqual(t, first, verify(43))
+
+	// Errors from the reader are returned rather than falling back to another source
+	failingCtx := ctx.WithRandomness(bytes.NewReader(nil))
+	err = failingCtx.VerifyCellKZGProofBatch(commitments, cellIndices, cells[:], proofs[:])
+	require.ErrorIs(t, err, goethkzg.ErrReadRandomness)
+	require.ErrorIs(t, err, io.EOF)
+	err = failingCtx.VerifyBlobKZGProofBatch(blobs, blobCommitments, blobProofs)
+	require.ErrorIs(t, err, goethkzg.ErrReadRandomness)
+
+	// Passing nil restores crypto/rand
+	require.NoError(t, failingCtx.WithRandomness(nil).VerifyBlobKZGProofBatch(blobs, blobCommitments, blobProofs))
+
+	// Deterministic verification does not consume any randomness
+	reader := &recordingReader{reader: rand.New(rand.NewSource(42))}
+	require.NoError(t, ctx.WithRandomness(reader).WithDeterministicVerification().VerifyBlobKZGProofBatch(blobs, blobCommitments, blobProofs))
+	require.Zero(t, reader.read.Len())
+}
+
+// requireBatchError checks that `err` is a [goethkzg.BatchError] for the element at `index`.
+func requireBatchError(t *testing.T, err error, index int, kind goethkzg.ErrorKind) {
+	t.Helper()
+	var batchErr *goethkzg.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, index, batchErr.Index)
+	require.Equal(t, kind, batchErr.Kind)
+}
+
+func TestBatchError(t *testing.T) {
+	malformedPoint := [48]byte{0xff}
+
+	blob := GetRandBlob(17)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	blobProof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	badBlob := GetRandBlob(18)
+	modifyBlob(badBlob, nonCanonicalScalar(18), 0)
+
+	t.Run("blob batch", func(t *testing.T) {
+		verifyBoth := func(blobs []*goethkzg.Blob, commitments []goethkzg.KZGCommitment, proofs []goethkzg.KZGProof) []error {
+			return []error{
+				ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs),
+				ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs),
+			}
+		}
+		blobs := []*goethkzg.Blob{blob, blob, blob}
+		commitments := []goethkzg.KZGCommitment{commitment, commitment, commitment}
+		proofs := []goethkzg.KZGProof{blobProof, blobProof, blobProof}
+
+		for _, err := range verifyBoth(blobs, commitments, proofs[:2]) {
+			requireBatchError(t, err, -1, goethkzg.ErrorKindBatchLength)
+			require.ErrorIs(t, err, goethkzg.ErrBatchLengthCheck)
+		}
+		for _, err := range verifyBoth(blobs, []goethkzg.KZGCommitment{commitment, malformedPoint, commitment}, proofs) {
+			requireBatchError(t, err, 1, goethkzg.ErrorKindMalformedCommitment)
+		}
+		for _, err := range verifyBoth(blobs, commitments, []goethkzg.KZGProof{blobProof, blobProof, malformedPoint}) {
+			requireBatchError(t, err, 2, goethkzg.ErrorKindMalformedProof)
+		}
+		for _, err := range verifyBoth([]*goethkzg.Blob{blob, badBlob, blob}, commitments, proofs) {
+			requireBatchError(t, err, 1, goethkzg.ErrorKindMalformedBlob)
+			require.ErrorIs(t, err, goethkzg.ErrNonCanonicalScalar)
+		}
+
+		// A well-formed proof that does not verify can only be attributed to an element by the parallel version,
+		// or when there is only one element
+		wrongProofs := []goethkzg.KZGProof{blobProof, blobProof, goethkzg.KZGProof(goethkzg.PointAtInfinity)}
+		errs := verifyBoth(blobs, commitments, wrongProofs)
+		requireBatchError(t, errs[0], -1, goethkzg.ErrorKindInvalidProof)
+		requireBatchError(t, errs[1], 2, goethkzg.ErrorKindInvalidProof)
+		for _, err := range errs {
+			require.ErrorIs(t, err, goethkzg.ErrVerifyOpeningProof)
+		}
+		for _, err := range verifyBoth(blobs[:1], commitments[:1], wrongProofs[2:]) {
+			requireBatchError(t, err, 0, goethkzg.ErrorKindInvalidProof)
+		}
+
+		// The method for a single blob returns the underlying error
+		err := ctx.VerifyBlobKZGProof(blob, commitment, goethkzg.KZGProof(goethkzg.PointAtInfinity))
+		require.Equal(t, goethkzg.ErrVerifyOpeningProof, err)
+	})
+
+	t.Run("cell batch", func(t *testing.T) {
+		cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+		require.NoError(t, err)
+		commitments := []goethkzg.KZGCommitment{commitment, commitment, commitment}
+		cellIndices := []uint64{0, 1, 2}
+		batchCells := cells[:3]
+		batchProofs := proofs[:3]
+		require.NoError(t, ctx.VerifyCellKZGProofBatch(commitments, cellIndices, batchCells, batchProofs))
+
+		err = ctx.VerifyCellKZGProofBatch(commitments, cellIndices[:2], batchCells, batchProofs)
+		requireBatchError(t, err, -1, goethkzg.ErrorKindBatchLength)
+
+		err = ctx.VerifyCellKZGProofBatch(commitments, []uint64{0, goethkzg.CellsPerExtBlob, 2}, batchCells, batchProofs)
+		requireBatchError(t, err, 1, goethkzg.ErrorKindInvalidCellIndex)
+		require.ErrorIs(t, err, goethkzg.ErrInvalidCellID)
+
+		err = ctx.VerifyCellKZGProofBatch([]goethkzg.KZGCommitment{commitment, commitment, malformedPoint}, cellIndices, batchCells, batchProofs)
+		requireBatchError(t, err, 2, goethkzg.ErrorKindMalformedCommitment)
+
+		err = ctx.VerifyCellKZGProofBatch(commitments, cellIndices, batchCells, []goethkzg.KZGProof{proofs[0], malformedPoint, proofs[2]})
+		requireBatchError(t, err, 1, goethkzg.ErrorKindMalformedProof)
+
+		badCell := *cells[2]
+		nonCanonical := nonCanonicalScalar(19)
+		copy(badCell[:], nonCanonical[:])
+		err = ctx.VerifyCellKZGProofBatch(commitments, cellIndices, []*goethkzg.Cell{cells[0], cells[1], &badCell}, batchProofs)
+		requireBatchError(t, err, 2, goethkzg.ErrorKindMalformedCell)
+		require.ErrorIs(t, err, goethkzg.ErrNonCanonicalScalar)
+
+		err = ctx.VerifyCellKZGProofBatch(commitments, cellIndices, batchCells, []goethkzg.KZGProof{proofs[1], proofs[0], proofs[2]})
+		requireBatchError(t, err, -1, goethkzg.ErrorKindInvalidProof)
+		require.ErrorIs(t, err, goethkzg.ErrVerifyOpeningProof)
+	})
+
+	t.Run("batch prover", func(t *testing.T) {
+		_, _, err := ctx.ComputeCellsAndKZGProofsBatch([]*goethkzg.Blob{blob, badBlob}, NumGoRoutines)
+		requireBatchError(t, err, 1, goethkzg.ErrorKindMalformedBlob)
+	})
+
+	t.Run("kinds", func(t *testing.T) {
+		for _, kind := range []goethkzg.ErrorKind{
+			goethkzg.ErrorKindBatchLength,
+			goethkzg.ErrorKindMalformedBlob,
+			goethkzg.ErrorKindMalformedCell,
+			goethkzg.ErrorKindMalformedCommitment,
+			goethkzg.ErrorKindMalformedProof,
+			goethkzg.ErrorKindInvalidCellIndex,
+		} {
+			require.True(t, kind.IsMalformed(), kind.String())
+		}
+		require.False(t, goethkzg.ErrorKindInvalidProof.IsMalformed())
+		require.Equal(t, "ErrorKind(0)", goethkzg.ErrorKind(0).String())
+
+		err := &goethkzg.BatchError{Index: 3, Kind: goethkzg.ErrorKindMalformedProof, Err: goethkzg.ErrNonCanonicalScalar}
+		require.Equal(t, "malformed proof at batch index 3: "+goethkzg.ErrNonCanonicalScalar.Error(), err.Error())
+	})
+}
+
+func TestFindInvalidProofs(t *testing.T) {
+	blobs := make([]*goethkzg.Blob, 4)
+	commitments := make([]goethkzg.KZGCommitment, len(blobs))
+	blobProofs := make([]goethkzg.KZGProof, len(blobs))
+	cellCommitments := make([]goethkzg.KZGCommitment, 0, 2*len(blobs))
+	cellIndices := make([]uint64, 0, 2*len(blobs))
+	cells := make([]*goethkzg.Cell, 0, 2*len(blobs))
+	cellProofs := make([]goethkzg.KZGProof, 0, 2*len(blobs))
+	for i := range blobs {
+		blobs[i] = GetRandBlob(int64(20 + i))
+		commitment, err := ctx.BlobToKZGCommitment(blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i] = commitment
+		blobProofs[i], err = ctx.ComputeBlobKZGProof(blobs[i], commitment, NumGoRoutines)
+		require.NoError(t, err)
+
+		cellIndicesForBlob := []uint64{uint64(i), uint64(100 + i)}
+		blobCells, err := ctx.ComputeCellsForIndices(blobs[i], cellIndicesForBlob, NumGoRoutines)
+		require.NoError(t, err)
+		proofs, err := ctx.ComputeCellKZGProofsForIndices(blobs[i], cellIndicesForBlob, NumGoRoutines)
+		require.NoError(t, err)
+		for j := range cellIndicesForBlob {
+			cellCommitments = append(cellCommitments, commitment)
+			cellIndices = append(cellIndices, cellIndicesForBlob[j])
+			cells = append(cells, blobCells[j])
+			cellProofs = append(cellProofs, proofs[j])
+		}
+	}
+
+	t.Run("blobs", func(t *testing.T) {
+		invalid, err := ctx.FindInvalidBlobProofs(blobs, commitments, blobProofs)
+		require.NoError(t, err)
+		require.Empty(t, invalid)
+
+		// Swap two proofs, so that they are well-formed but wrong, and break the encoding of another
+		badProofs := slices.Clone(blobProofs)
+		badProofs[0], badProofs[3] = badProofs[3], badProofs[0]
+		badCommitments := slices.Clone(commitments)
+		badCommitments[2] = [48]byte{0xff}
+		require.Error(t, ctx.VerifyBlobKZGProofBatch(blobs, badCommitments, badProofs))
+
+		invalid, err = ctx.FindInvalidBlobProofs(blobs, badCommitments, badProofs)
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 2, 3}, invalid)
+
+		// The search gives the same result in deterministic mode
+		invalid, err = ctx.WithDeterministicVerification().FindInvalidBlobProofs(blobs, badCommitments, badProofs)
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 2, 3}, invalid)
+
+		_, err = ctx.FindInvalidBlobProofs(blobs, commitments[:1], blobProofs)
+		require.ErrorIs(t, err, goethkzg.ErrBatchLengthCheck)
+	})
+
+	t.Run("cells", func(t *testing.T) {
+		invalid, err := ctx.FindInvalidCellProofs(cellCommitments, cellIndices, cells, cellProofs)
+		require.NoError(t, err)
+		require.Empty(t, invalid)
+
+		badCommitments := slices.Clone(cellCommitments)
+		badCellIndices := slices.Clone(cellIndices)
+		badProofs := slices.Clone(cellProofs)
+		// A well-formed proof for the wrong cell
+		badProofs[1] = cellProofs[0]
+		// A cell index that does not exist
+		badCellIndices[4] = goethkzg.CellsPerExtBlob
+		// A commitment that cannot be deserialized is reported for every cell that refers to it
+		malformedCommitment := goethkzg.KZGCommitment{0xff}
+		badCommitments[6] = malformedCommitment
+		badCommitments[7] = malformedCommitment
+		require.Error(t, ctx.VerifyCellKZGProofBatch(badCommitments, badCellIndices, cells, badProofs))
+
+		invalid, err = ctx.FindInvalidCellProofs(badCommitments, badCellIndices, cells, badProofs)
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 4, 6, 7}, invalid)
+
+		invalid, err = ctx.WithDeterministicVerification().FindInvalidCellProofs(badCommitments, badCellIndices, cells, badProofs)
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 4, 6, 7}, invalid)
+
+		// The cells are left unchanged
+		require.NoError(t, ctx.VerifyCellKZGProofBatch(cellCommitments, cellIndices, cells, cellProofs))
+	})
+}
@@ -1,6 +1,8 @@
 package goethkzg_test
 
 import (
+	"fmt"
+	"runtime"
 	"testing"
 
 	goethkzg "github.com/crate-crypto/go-eth-kzg"
@@ -75,3 +77,105 @@ func BenchmarkEIP7594(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkComputeCellsAndKZGProofsScaling shows how computing the cells and proofs for a
+// single blob scales with the number of go-routines, up to the number of CPUs.
+func BenchmarkComputeCellsAndKZGProofsScaling(b *testing.B) {
+	blob := GetRandBlob(int64(42))
+
+	for numGoRoutines := 1; numGoRoutines <= runtime.NumCPU(); numGoRoutines *= 2 {
+		b.Run(fmt.Sprintf("numGoRoutines=%d", numGoRoutines), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _, _ = ctx.ComputeCellsAndKZGProofs(blob, numGoRoutines)
+			}
+		})
+	}
+}
+
+func BenchmarkComputeCellsAndKZGProofsBatch(b *testing.B) {
+	const numBlobs = 6
+	blobs := make([]*goethkzg.Blob, numBlobs)
+	for i := range blobs {
+		blobs[i] = GetRandBlob(int64(i))
+	}
+
+	b.Run(fmt.Sprintf("Loop(count=%d)", numBlobs), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for _, blob := range blobs {
+				_, _, _ = ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+			}
+		}
+	})
+
+	b.Run(fmt.Sprintf("Batch(count=%d)", numBlobs), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _, _ = ctx.ComputeCellsAndKZGProofsBatch(blobs, NumGoRoutines)
+		}
+	})
+}
+
+func BenchmarkRecoverMatrix(b *testing.B) {
+	const numBlobs = 6
+	blobs := make([]*goethkzg.Blob, numBlobs)
+	for i := range blobs {
+		blobs[i] = GetRandBlob(int64(i))
+	}
+	cells, _, err := ctx.ComputeCellsAndKZGProofsBatch(blobs, NumGoRoutines)
+	require.NoError(b, err)
+
+	// Half of the columns are missing
+	cellsByColumn := make(map[uint64][]*goethkzg.Cell)
+	var columnIndices []uint64
+	for i := uint64(0); i < goethkzg.CellsPerExtBlob; i += 2 {
+		cellsByColumn[i] = cells[i]
+		columnIndices = append(columnIndices, i)
+	}
+
+	b.Run(fmt.Sprintf("Loop(count=%d)", numBlobs), func(b *testing.B) {
+		b.ReportAllocs()
+		rowCells := make([]*goethkzg.Cell, len(columnIndices))
+		for n := 0; n < b.N; n++ {
+			for row := 0; row < numBlobs; row++ {
+				for i, columnIndex := range columnIndices {
+					rowCells[i] = cellsByColumn[columnIndex][row]
+				}
+				_, _, _ = ctx.RecoverCellsAndComputeKZGProofs(columnIndices, rowCells, NumGoRoutines)
+			}
+		}
+	})
+
+	b.Run(fmt.Sprintf("Matrix(count=%d)", numBlobs), func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _, _ = ctx.RecoverMatrix(cellsByColumn, numBlobs)
+		}
+	})
+}
+
+func BenchmarkComputeCellsAndProofsForIndices(b *testing.B) {
+	blob := GetRandBlob(int64(42))
+
+	for _, count := range []int{1, 8, 32, 128} {
+		cellIndices := make([]uint64, count)
+		for i := range cellIndices {
+			cellIndices[i] = uint64(i * goethkzg.CellsPerExtBlob / count)
+		}
+
+		b.Run(fmt.Sprintf("ComputeCellsForIndices(count=%d)", count), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = ctx.ComputeCellsForIndices(blob, cellIndices, NumGoRoutines)
+			}
+		})
+
+		b.Run(fmt.Sprintf("ComputeCellKZGProofsForIndices(count=%d)", count), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = ctx.ComputeCellKZGProofsForIndices(blob, cellIndices, NumGoRoutines)
+			}
+		})
+	}
+}
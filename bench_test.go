@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -144,3 +146,44 @@ func BenchmarkDeserializeBlob(b *testing.B) {
 		b.Fatalf("have %s want %s", have, want)
 	}
 }
+
+func BenchmarkContextLoading(b *testing.B) {
+	serialized, err := ctx.MarshalBinary()
+	require.NoError(b, err)
+
+	b.Run("NewContext4096Secure", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _ = goethkzg.NewContext4096Secure()
+		}
+	})
+
+	b.Run("UnmarshalContext", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _ = goethkzg.UnmarshalContext(serialized)
+		}
+	})
+
+	setupJSON, err := os.ReadFile("trusted_setup.json")
+	require.NoError(b, err)
+	var setup goethkzg.JSONTrustedSetup
+	require.NoError(b, json.Unmarshal(setupJSON, &setup))
+
+	constructors := []struct {
+		name string
+		new  func(*goethkzg.JSONTrustedSetup, goethkzg.Params) (*goethkzg.Context, error)
+	}{
+		{"NewContextWithParams", goethkzg.NewContextWithParams},
+		{"NewContext4844Only", goethkzg.NewContext4844Only},
+		{"NewVerifierContext", goethkzg.NewVerifierContext},
+	}
+	for _, constructor := range constructors {
+		b.Run(constructor.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = constructor.new(&setup, goethkzg.MainnetParams)
+			}
+		})
+	}
+}
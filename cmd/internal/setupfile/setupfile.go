@@ -0,0 +1,109 @@
+// Package setupfile reads and writes trusted setup files for the command-line tools.
+//
+// A trusted setup can be in the JSON format of trusted_setup.json, the c-kzg text format or the binary format of
+// [goethkzg.LoadTrustedSetupBinary]. Unless a format is given, the format of a file is given by its extension: .json,
+// .txt, and anything else is read as binary. The path "-" stands for stdin or stdout.
+package setupfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+)
+
+// Names of the trusted setup formats.
+const (
+	FormatJSON   = "json"
+	FormatText   = "text"
+	FormatBinary = "binary"
+)
+
+// ResolveFormat returns `format` if it is set, and otherwise the format given by the extension of `path`.
+func ResolveFormat(path, format string) (string, error) {
+	switch format {
+	case FormatJSON, FormatText, FormatBinary:
+		return format, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unknown format %q, expected %s, %s or %s", format, FormatJSON, FormatText, FormatBinary)
+	}
+
+	switch filepath.Ext(path) {
+	case ".json":
+		return FormatJSON, nil
+	case ".txt":
+		return FormatText, nil
+	default:
+		return FormatBinary, nil
+	}
+}
+
+// Load reads the trusted setup at `path` in the given format, or in the format given by its extension. The path "-"
+// reads from `stdin`.
+func Load(stdin io.Reader, path, format string) (*goethkzg.JSONTrustedSetup, error) {
+	if path == "" {
+		return nil, errors.New("no trusted setup file given")
+	}
+	format, err := ResolveFormat(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	r := stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	switch format {
+	case FormatJSON:
+		return goethkzg.LoadTrustedSetupJSON(r)
+	case FormatText:
+		return goethkzg.LoadTrustedSetupText(r)
+	default:
+		return goethkzg.LoadTrustedSetupBinary(r)
+	}
+}
+
+// Write writes the trusted setup to `path` in the given format, or in the format given by its extension. The path "-"
+// writes to `stdout`.
+func Write(stdout io.Writer, trustedSetup *goethkzg.JSONTrustedSetup, path, format string) (err error) {
+	if path == "" {
+		return errors.New("no output file given")
+	}
+	format, err = ResolveFormat(path, format)
+	if err != nil {
+		return err
+	}
+
+	w := stdout
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = file
+	}
+
+	switch format {
+	case FormatJSON:
+		return goethkzg.WriteTrustedSetupJSON(w, trustedSetup)
+	case FormatText:
+		return goethkzg.WriteTrustedSetupText(w, trustedSetup)
+	default:
+		return goethkzg.WriteTrustedSetupBinary(w, trustedSetup)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/crate-crypto/go-eth-kzg/cmd/internal/setupfile"
+)
+
+// setupSummary describes a trusted setup in the output of the commands.
+type setupSummary struct {
+	G1Points    int    `json:"g1_points"`
+	G2Points    int    `json:"g2_points"`
+	Fingerprint string `json:"fingerprint"`
+	Mainnet     bool   `json:"mainnet"`
+}
+
+func summarize(trustedSetup *goethkzg.JSONTrustedSetup) (setupSummary, error) {
+	fingerprint, err := goethkzg.ComputeSetupFingerprint(trustedSetup)
+	if err != nil {
+		return setupSummary{}, err
+	}
+	return setupSummary{
+		G1Points:    len(trustedSetup.SetupG1Lagrange),
+		G2Points:    len(trustedSetup.SetupG2),
+		Fingerprint: fingerprint.String(),
+		Mainnet:     fingerprint.String() == goethkzg.MainnetSetupFingerprint,
+	}, nil
+}
+
+func runConvert(env *env, args []string) error {
+	flags := newFlagSet(env, "convert")
+	in := flags.String("in", "", "trusted setup to convert, or - for stdin")
+	inFormat := flags.String("in-format", "", "format of -in: json, text or binary (default: from the extension)")
+	out := flags.String("out", "", "file to write the converted setup to, or - for stdout")
+	outFormat := flags.String("out-format", "", "format of -out: json, text or binary (default: from the extension)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	trustedSetup, err := setupfile.Load(env.stdin, *in, *inFormat)
+	if err != nil {
+		return err
+	}
+	if err := setupfile.Write(env.stdout, trustedSetup, *out, *outFormat); err != nil {
+		return err
+	}
+
+	// The converted setup is the output when writing to stdout
+	if *out == "-" {
+		return nil
+	}
+	summary, err := summarize(trustedSetup)
+	if err != nil {
+		return err
+	}
+	return writeJSON(env.stdout, summary)
+}
+
+func runGenerate(env *env, args []string) error {
+	flags := newFlagSet(env, "generate")
+	secretFlag := flags.String("secret", "", "secret to generate the setup from, in decimal or 0x-prefixed hex")
+	size := flags.Uint64("size", goethkzg.ScalarsPerBlob, "number of G1 points, which is the number of field elements in a blob")
+	cellSize := flags.Uint64("cell-size", goethkzg.MainnetParams.FieldElementsPerCell, "number of field elements in a cell; the setup has one more G2 point than this")
+	out := flags.String("out", "", "file to write the setup to, or - for stdout")
+	format := flags.String("format", "", "format of -out: json, text or binary (default: from the extension)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *secretFlag == "" {
+		return errors.New("-secret is required")
+	}
+	secret, ok := new(big.Int).SetString(*secretFlag, 0)
+	if !ok {
+		return fmt.Errorf("-secret: %q is not a number", *secretFlag)
+	}
+
+	params := goethkzg.Params{
+		FieldElementsPerBlob: *size,
+		FieldElementsPerCell: *cellSize,
+		ExpansionFactor:      goethkzg.MainnetParams.ExpansionFactor,
+	}
+	trustedSetup, err := goethkzg.NewInsecureTrustedSetupForTesting(secret, params)
+	if err != nil {
+		return err
+	}
+	if err := setupfile.Write(env.stdout, trustedSetup, *out, *format); err != nil {
+		return err
+	}
+
+	if *out == "-" {
+		return nil
+	}
+	summary, err := summarize(trustedSetup)
+	if err != nil {
+		return err
+	}
+	return writeJSON(env.stdout, summary)
+}
+
+// verification is the result of the verify command.
+type verification struct {
+	setupSummary
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+func runVerify(env *env, args []string) error {
+	flags := newFlagSet(env, "verify")
+	setup := flags.String("setup", "", "trusted setup to verify, or - for stdin")
+	format := flags.String("format", "", "format of -setup: json, text or binary (default: from the extension)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	trustedSetup, err := setupfile.Load(env.stdin, *setup, *format)
+	if err != nil {
+		return err
+	}
+	summary, err := summarize(trustedSetup)
+	if err != nil {
+		return err
+	}
+
+	// This does the subgroup checks as well as the consistency checks
+	result := verification{setupSummary: summary, Valid: true}
+	if err := goethkzg.VerifyTrustedSetupConsistency(trustedSetup); err != nil {
+		result.Valid = false
+		result.Error = err.Error()
+	}
+	if err := writeJSON(env.stdout, result); err != nil {
+		return err
+	}
+	if !result.Valid {
+		return errInvalid
+	}
+	return nil
+}
+
+func runFingerprint(env *env, args []string) error {
+	flags := newFlagSet(env, "fingerprint")
+	setup := flags.String("setup", "", "trusted setup to fingerprint, or - for stdin")
+	format := flags.String("format", "", "format of -setup: json, text or binary (default: from the extension)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	trustedSetup, err := setupfile.Load(env.stdin, *setup, *format)
+	if err != nil {
+		return err
+	}
+	summary, err := summarize(trustedSetup)
+	if err != nil {
+		return err
+	}
+	return writeJSON(env.stdout, summary)
+}
@@ -0,0 +1,116 @@
+// Command kzg-setup converts, generates and verifies trusted setups.
+//
+// Usage:
+//
+//	kzg-setup <command> [flags]
+//
+// A trusted setup can be in the JSON format of trusted_setup.json, the c-kzg text format or the binary format of
+// [goethkzg.LoadTrustedSetupBinary]. The format of a file is given by its extension: .json, .txt, and anything else is
+// read as binary. The -format flags override this, which is needed to read from stdin or write to stdout with "-".
+//
+// Results are written to stdout as JSON. The verify command exits with status 1 if the setup is not valid, and every
+// command exits with status 2 if it could not run.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes of the command.
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitError   = 2
+)
+
+// command is a subcommand of the tool.
+type command struct {
+	name    string
+	summary string
+	run     func(env *env, args []string) error
+}
+
+var commands = []command{
+	{"convert", "convert a trusted setup to another format", runConvert},
+	{"generate", "generate an INSECURE trusted setup from a known secret, for devnets and tests", runGenerate},
+	{"verify", "check that a trusted setup is well-formed and consistent", runVerify},
+	{"fingerprint", "print the fingerprint of a trusted setup", runFingerprint},
+}
+
+// env holds the streams that a command reads from and writes to.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// errInvalid is returned by the verify command when the setup is not valid. The result has already been written
+// out, so only the exit status is left to set.
+var errInvalid = errors.New("trusted setup is not valid")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command given by `args` and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return exitError
+		}
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(&env{stdin: stdin, stdout: stdout, stderr: stderr}, args[1:])
+		switch {
+		case err == nil:
+			return exitOK
+		case errors.Is(err, errInvalid):
+			return exitInvalid
+		case errors.Is(err, flag.ErrHelp):
+			return exitOK
+		default:
+			fmt.Fprintf(stderr, "kzg-setup %s: %v\n", cmd.name, err)
+			return exitError
+		}
+	}
+
+	fmt.Fprintf(stderr, "kzg-setup: unknown command %q\n\n", args[0])
+	usage(stderr)
+	return exitError
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: kzg-setup <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'kzg-setup <command> -h' for the flags of a command.")
+}
+
+// newFlagSet creates the flag set for a command, which reports errors instead of exiting.
+func newFlagSet(env *env, name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("kzg-setup "+name, flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	return flags
+}
+
+// writeJSON writes the result of a command.
+func writeJSON(w io.Writer, result any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/crate-crypto/go-eth-kzg/cmd/internal/setupfile"
+	"github.com/stretchr/testify/require"
+)
+
+// runJSON runs the tool and decodes its output into a map.
+func runJSON(t *testing.T, expectedStatus int, args ...string) map[string]any {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(""), &stdout, &stderr)
+	require.Equal(t, expectedStatus, status, "stderr: %s", stderr.String())
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	return result
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "setup.json")
+	binaryPath := filepath.Join(dir, "setup.bin")
+	textPath := filepath.Join(dir, "setup.txt")
+
+	generated := runJSON(t, exitOK, "generate", "-secret", "0x539", "-size", "64", "-cell-size", "8", "-out", jsonPath)
+	require.Equal(t, float64(64), generated["g1_points"])
+	require.Equal(t, float64(9), generated["g2_points"])
+	require.Equal(t, false, generated["mainnet"])
+
+	// The fingerprint does not depend on the format
+	runJSON(t, exitOK, "convert", "-in", jsonPath, "-out", binaryPath)
+	runJSON(t, exitOK, "convert", "-in", binaryPath, "-out", textPath)
+	fingerprint := runJSON(t, exitOK, "fingerprint", "-setup", textPath)
+	require.Equal(t, generated, fingerprint)
+
+	// The secret can be given in decimal too
+	runJSON(t, exitOK, "generate", "-secret", "1337", "-size", "64", "-cell-size", "8", "-out", filepath.Join(dir, "decimal.txt"))
+	expected, err := os.ReadFile(textPath)
+	require.NoError(t, err)
+	actual, err := os.ReadFile(filepath.Join(dir, "decimal.txt"))
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	verified := runJSON(t, exitOK, "verify", "-setup", binaryPath)
+	require.Equal(t, true, verified["valid"])
+	require.Equal(t, generated["fingerprint"], verified["fingerprint"])
+
+	// Swapping two lagrange points keeps the setup well-formed, but not consistent
+	trustedSetup, err := setupfile.Load(nil, jsonPath, "")
+	require.NoError(t, err)
+	trustedSetup.SetupG1Lagrange[1], trustedSetup.SetupG1Lagrange[2] = trustedSetup.SetupG1Lagrange[2], trustedSetup.SetupG1Lagrange[1]
+	tamperedPath := filepath.Join(dir, "tampered.json")
+	require.NoError(t, setupfile.Write(nil, trustedSetup, tamperedPath, ""))
+	verified = runJSON(t, exitInvalid, "verify", "-setup", tamperedPath)
+	require.Equal(t, false, verified["valid"])
+	require.Contains(t, verified["error"], goethkzg.ErrTrustedSetupInconsistent.Error())
+}
+
+func TestRunMainnetFingerprint(t *testing.T) {
+	fingerprint := runJSON(t, exitOK, "fingerprint", "-setup", "../../trusted_setup.json")
+	require.Equal(t, goethkzg.MainnetSetupFingerprint, fingerprint["fingerprint"])
+	require.Equal(t, true, fingerprint["mainnet"])
+}
+
+func TestRunStdio(t *testing.T) {
+	var generated, converted, stderr bytes.Buffer
+	status := run([]string{"generate", "-secret", "1337", "-size", "16", "-cell-size", "4", "-out", "-", "-format", "text"}, nil, &generated, &stderr)
+	require.Equal(t, exitOK, status, "stderr: %s", stderr.String())
+
+	status = run([]string{"convert", "-in", "-", "-in-format", "text", "-out", "-", "-out-format", "json"}, bytes.NewReader(generated.Bytes()), &converted, &stderr)
+	require.Equal(t, exitOK, status, "stderr: %s", stderr.String())
+
+	trustedSetup, err := goethkzg.LoadTrustedSetupJSON(&converted)
+	require.NoError(t, err)
+	require.Len(t, trustedSetup.SetupG1Monomial, 16)
+}
+
+func TestRunErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitError, run(nil, nil, &stdout, &stderr))
+	require.Equal(t, exitError, run([]string{"unknown"}, nil, &stdout, &stderr))
+
+	stderr.Reset()
+	require.Equal(t, exitError, run([]string{"generate", "-out", "-"}, nil, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "-secret is required")
+
+	stderr.Reset()
+	require.Equal(t, exitError, run([]string{"generate", "-secret", "1", "-out", "-"}, nil, &stdout, &stderr))
+	require.Contains(t, stderr.String(), goethkzg.ErrInvalidSecret.Error())
+
+	stderr.Reset()
+	require.Equal(t, exitError, run([]string{"generate", "-secret", "1337", "-size", "100", "-out", "-"}, nil, &stdout, &stderr))
+	require.Contains(t, stderr.String(), goethkzg.ErrInvalidParams.Error())
+
+	stderr.Reset()
+	require.Equal(t, exitError, run([]string{"fingerprint", "-setup", "-", "-format", "yaml"}, nil, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "unknown format")
+}
@@ -0,0 +1,57 @@
+// Command kzg-vectors writes random test vectors in the format of the consensus-specs KZG tests.
+//
+// Usage:
+//
+//	kzg-vectors -out <dir> [-seed <seed>] [-count <count>]
+//
+// Each round writes test cases with valid inputs, with proofs that do not verify and with invalid inputs for all nine
+// spec functions, under <dir>/<function>/kzg-mainnet/. The same seed always writes the same vectors.
+//
+// Test cases from custom inputs, such as an edge case found while debugging, can be written with the
+// [testvectors.Generator] methods for each spec function.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/crate-crypto/go-eth-kzg/testvectors"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "kzg-vectors: %v\n", err)
+		}
+		os.Exit(2)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("kzg-vectors", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	out := flags.String("out", "", "directory to write the test vectors to")
+	seed := flags.Int64("seed", 1, "seed of the random inputs")
+	count := flags.Int("count", 1, "number of rounds of test cases to write")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+	if *count < 1 {
+		return fmt.Errorf("-count must be positive, found %d", *count)
+	}
+
+	ctx, err := goethkzg.NewContext4096Secure()
+	if err != nil {
+		return err
+	}
+	generator := testvectors.NewGenerator(ctx, *out, testvectors.MainnetPreset)
+	return generator.GenerateRandom(rand.New(rand.NewSource(*seed)), *count)
+}
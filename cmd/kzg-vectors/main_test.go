@@ -0,0 +1,15 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunErrors(t *testing.T) {
+	var stderr bytes.Buffer
+	require.ErrorContains(t, run(nil, &stderr), "-out is required")
+	require.ErrorContains(t, run([]string{"-out", t.TempDir(), "-count", "0"}, &stderr), "-count must be positive")
+	require.Error(t, run([]string{"-unknown"}, &stderr))
+}
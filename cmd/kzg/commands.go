@@ -0,0 +1,399 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+)
+
+func runCommit(env *env, args []string) error {
+	var setup setupFlags
+	flags := newFlagSet(env, "commit")
+	blobFlag := flags.String("blob", "", "blob to commit to")
+	setup.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	blob, err := readRequired(env, "blob", *blobFlag)
+	if err != nil {
+		return err
+	}
+	ctx, err := setup.newContext(env)
+	if err != nil {
+		return err
+	}
+
+	commitment, err := ctx.BlobToKZGCommitmentBytes(blob, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	return writeJSON(env.stdout, struct {
+		Commitment string `json:"commitment"`
+	}{encodeHex(commitment[:])})
+}
+
+func runProveBlob(env *env, args []string) error {
+	var setup setupFlags
+	flags := newFlagSet(env, "prove-blob")
+	blobFlag := flags.String("blob", "", "blob to compute the proof for")
+	commitmentFlag := flags.String("commitment", "", "commitment to the blob (default: computed from the blob)")
+	setup.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	blob, err := readRequired(env, "blob", *blobFlag)
+	if err != nil {
+		return err
+	}
+	ctx, err := setup.newContext(env)
+	if err != nil {
+		return err
+	}
+
+	var commitment goethkzg.KZGCommitment
+	if *commitmentFlag != "" {
+		byts, err := readFixed(env, "commitment", *commitmentFlag, goethkzg.CompressedG1Size)
+		if err != nil {
+			return err
+		}
+		commitment = goethkzg.KZGCommitment(byts)
+	} else {
+		commitment, err = ctx.BlobToKZGCommitmentBytes(blob, numGoRoutines)
+		if err != nil {
+			return err
+		}
+	}
+
+	proof, err := ctx.ComputeBlobKZGProofBytes(blob, commitment, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	return writeJSON(env.stdout, struct {
+		Commitment string `json:"commitment"`
+		Proof      string `json:"proof"`
+	}{encodeHex(commitment[:]), encodeHex(proof[:])})
+}
+
+func runProvePoint(env *env, args []string) error {
+	var setup setupFlags
+	flags := newFlagSet(env, "prove-point")
+	blobFlag := flags.String("blob", "", "blob to compute the proof for")
+	zFlag := flags.String("z", "", "field element to evaluate the blob at")
+	setup.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	blob, err := readRequired(env, "blob", *blobFlag)
+	if err != nil {
+		return err
+	}
+	z, err := readFixed(env, "z", *zFlag, goethkzg.SerializedScalarSize)
+	if err != nil {
+		return err
+	}
+	ctx, err := setup.newContext(env)
+	if err != nil {
+		return err
+	}
+
+	commitment, err := ctx.BlobToKZGCommitmentBytes(blob, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	proof, y, err := ctx.ComputeKZGProofBytes(blob, goethkzg.Scalar(z), numGoRoutines)
+	if err != nil {
+		return err
+	}
+	return writeJSON(env.stdout, struct {
+		Commitment string `json:"commitment"`
+		Z          string `json:"z"`
+		Y          string `json:"y"`
+		Proof      string `json:"proof"`
+	}{encodeHex(commitment[:]), encodeHex(z), encodeHex(y[:]), encodeHex(proof[:])})
+}
+
+func runVerifyBlob(env *env, args []string) error {
+	var setup setupFlags
+	flags := newFlagSet(env, "verify-blob")
+	blobFlag := flags.String("blob", "", "blob that the proof is for")
+	commitmentFlag := flags.String("commitment", "", "commitment to the blob")
+	proofFlag := flags.String("proof", "", "proof to verify")
+	setup.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	blob, err := readRequired(env, "blob", *blobFlag)
+	if err != nil {
+		return err
+	}
+	commitment, err := readFixed(env, "commitment", *commitmentFlag, goethkzg.CompressedG1Size)
+	if err != nil {
+		return err
+	}
+	proof, err := readFixed(env, "proof", *proofFlag, goethkzg.CompressedG1Size)
+	if err != nil {
+		return err
+	}
+	ctx, err := setup.newContext(env)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.VerifyBlobKZGProofBytes(blob, goethkzg.KZGCommitment(commitment), goethkzg.KZGProof(proof))
+	return writeVerification(env.stdout, err)
+}
+
+func runVerifyPoint(env *env, args []string) error {
+	var setup setupFlags
+	flags := newFlagSet(env, "verify-point")
+	commitmentFlag := flags.String("commitment", "", "commitment to the blob")
+	zFlag := flags.String("z", "", "field element that the blob was evaluated at")
+	yFlag := flags.String("y", "", "claimed evaluation of the blob at z")
+	proofFlag := flags.String("proof", "", "proof to verify")
+	setup.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	commitment, err := readFixed(env, "commitment", *commitmentFlag, goethkzg.CompressedG1Size)
+	if err != nil {
+		return err
+	}
+	z, err := readFixed(env, "z", *zFlag, goethkzg.SerializedScalarSize)
+	if err != nil {
+		return err
+	}
+	y, err := readFixed(env, "y", *yFlag, goethkzg.SerializedScalarSize)
+	if err != nil {
+		return err
+	}
+	proof, err := readFixed(env, "proof", *proofFlag, goethkzg.CompressedG1Size)
+	if err != nil {
+		return err
+	}
+	ctx, err := setup.newContext(env)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.VerifyKZGProof(goethkzg.KZGCommitment(commitment), goethkzg.Scalar(z), goethkzg.Scalar(y), goethkzg.KZGProof(proof))
+	return writeVerification(env.stdout, err)
+}
+
+// cellsAndProofs is the result of the commands that compute cells.
+type cellsAndProofs struct {
+	Cells  []string `json:"cells"`
+	Proofs []string `json:"proofs"`
+}
+
+func newCellsAndProofs(cells [][]byte, proofs []goethkzg.KZGProof) cellsAndProofs {
+	result := cellsAndProofs{
+		Cells:  make([]string, len(cells)),
+		Proofs: make([]string, len(proofs)),
+	}
+	for i, cell := range cells {
+		result.Cells[i] = encodeHex(cell)
+	}
+	for i, proof := range proofs {
+		result.Proofs[i] = encodeHex(proof[:])
+	}
+	return result
+}
+
+func runComputeCells(env *env, args []string) error {
+	var setup setupFlags
+	flags := newFlagSet(env, "compute-cells")
+	blobFlag := flags.String("blob", "", "blob to extend")
+	setup.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	blob, err := readRequired(env, "blob", *blobFlag)
+	if err != nil {
+		return err
+	}
+	ctx, err := setup.newContext(env)
+	if err != nil {
+		return err
+	}
+
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofsBytes(blob, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	return writeJSON(env.stdout, newCellsAndProofs(cells, proofs))
+}
+
+func runVerifyCells(env *env, args []string) error {
+	var setup setupFlags
+	flags := newFlagSet(env, "verify-cells")
+	commitmentsFlag := flags.String("commitments", "", "commitments to the blobs of the cells, or a single commitment for all of them")
+	cellIndicesFlag := flags.String("cell-indices", "", "comma-separated indices of the cells")
+	cellsFlag := flags.String("cells", "", "cells to verify")
+	proofsFlag := flags.String("proofs", "", "proofs of the cells")
+	setup.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, err := setup.newContext(env)
+	if err != nil {
+		return err
+	}
+	serCommitments, err := readList(env, "commitments", *commitmentsFlag, goethkzg.CompressedG1Size)
+	if err != nil {
+		return err
+	}
+	cellIndices, err := parseIndices("cell-indices", *cellIndicesFlag)
+	if err != nil {
+		return err
+	}
+	cells, err := readList(env, "cells", *cellsFlag, ctx.Params().BytesPerCell())
+	if err != nil {
+		return err
+	}
+	serProofs, err := readList(env, "proofs", *proofsFlag, goethkzg.CompressedG1Size)
+	if err != nil {
+		return err
+	}
+
+	commitments := make([]goethkzg.KZGCommitment, len(serCommitments))
+	for i, commitment := range serCommitments {
+		commitments[i] = goethkzg.KZGCommitment(commitment)
+	}
+	if len(commitments) == 1 {
+		for len(commitments) < len(cells) {
+			commitments = append(commitments, commitments[0])
+		}
+	}
+	proofs := make([]goethkzg.KZGProof, len(serProofs))
+	for i, proof := range serProofs {
+		proofs[i] = goethkzg.KZGProof(proof)
+	}
+
+	err = ctx.VerifyCellKZGProofBatchBytes(commitments, cellIndices, cells, proofs)
+	if errors.Is(err, goethkzg.ErrBatchLengthCheck) {
+		return fmt.Errorf("found %d commitments, %d cell indices, %d cells and %d proofs", len(commitments), len(cellIndices), len(cells), len(proofs))
+	}
+	return writeVerification(env.stdout, err)
+}
+
+func runRecover(env *env, args []string) error {
+	var setup setupFlags
+	flags := newFlagSet(env, "recover")
+	cellIndicesFlag := flags.String("cell-indices", "", "comma-separated indices of the cells, in ascending order")
+	cellsFlag := flags.String("cells", "", "cells to recover the rest from")
+	setup.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, err := setup.newContext(env)
+	if err != nil {
+		return err
+	}
+	cellIndices, err := parseIndices("cell-indices", *cellIndicesFlag)
+	if err != nil {
+		return err
+	}
+	cells, err := readList(env, "cells", *cellsFlag, ctx.Params().BytesPerCell())
+	if err != nil {
+		return err
+	}
+
+	recoveredCells, proofs, err := ctx.RecoverCellsAndComputeKZGProofsBytes(cellIndices, cells, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	return writeJSON(env.stdout, newCellsAndProofs(recoveredCells, proofs))
+}
+
+func runVersionedHash(env *env, args []string) error {
+	flags := newFlagSet(env, "versioned-hash")
+	commitmentFlag := flags.String("commitment", "", "commitment to hash")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	commitment, err := readFixed(env, "commitment", *commitmentFlag, goethkzg.CompressedG1Size)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(env.stdout, struct {
+		VersionedHash goethkzg.VersionedHash `json:"versioned_hash"`
+	}{goethkzg.KZGCommitment(commitment).VersionedHash()})
+}
+
+// inspection is the result of the inspect command.
+type inspection struct {
+	FieldElements int   `json:"field_elements"`
+	NonCanonical  []int `json:"non_canonical"`
+	// Evaluation is only set if a point was given and the blob could be evaluated at it.
+	Evaluation      *evaluation `json:"evaluation,omitempty"`
+	EvaluationError string      `json:"evaluation_error,omitempty"`
+}
+
+type evaluation struct {
+	Z string `json:"z"`
+	Y string `json:"y"`
+}
+
+func runInspect(env *env, args []string) error {
+	var setup setupFlags
+	flags := newFlagSet(env, "inspect")
+	blobFlag := flags.String("blob", "", "blob to inspect")
+	zFlag := flags.String("z", "", "field element to evaluate the blob at (optional)")
+	setup.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	blob, err := readRequired(env, "blob", *blobFlag)
+	if err != nil {
+		return err
+	}
+	if len(blob)%goethkzg.SerializedScalarSize != 0 {
+		return fmt.Errorf("-blob: expected a multiple of %d bytes, found %d", goethkzg.SerializedScalarSize, len(blob))
+	}
+
+	// 1. Find the field elements that are not canonical, which makes the blob invalid
+	//
+	result := inspection{
+		FieldElements: len(blob) / goethkzg.SerializedScalarSize,
+		NonCanonical:  []int{},
+	}
+	for i := 0; i < result.FieldElements; i++ {
+		scalar := goethkzg.Scalar(blob[i*goethkzg.SerializedScalarSize : (i+1)*goethkzg.SerializedScalarSize])
+		if _, err := goethkzg.DeserializeScalar(scalar); err != nil {
+			result.NonCanonical = append(result.NonCanonical, i)
+		}
+	}
+
+	// 2. Evaluate the blob, which is done by computing a proof for the point
+	//
+	if *zFlag != "" {
+		z, err := readFixed(env, "z", *zFlag, goethkzg.SerializedScalarSize)
+		if err != nil {
+			return err
+		}
+		ctx, err := setup.newContext(env)
+		if err != nil {
+			return err
+		}
+		_, y, err := ctx.ComputeKZGProofBytes(blob, goethkzg.Scalar(z), numGoRoutines)
+		if err != nil {
+			result.EvaluationError = err.Error()
+		} else {
+			result.Evaluation = &evaluation{Z: encodeHex(z), Y: encodeHex(y[:])}
+		}
+	}
+
+	return writeJSON(env.stdout, result)
+}
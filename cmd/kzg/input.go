@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readInput returns the bytes given by `value`, which is either a 0x-prefixed hex string or the path of a file. The
+// file may hold the raw bytes or a 0x-prefixed hex string, and the path "-" reads from stdin.
+func readInput(env *env, value string) ([]byte, error) {
+	if strings.HasPrefix(value, "0x") {
+		return decodeHex(value)
+	}
+
+	var data []byte
+	var err error
+	if value == "-" {
+		data, err = io.ReadAll(env.stdin)
+	} else {
+		data, err = os.ReadFile(value)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("0x")) {
+		return decodeHex(string(trimmed))
+	}
+	return data, nil
+}
+
+// decodeHex decodes a 0x-prefixed hex string.
+func decodeHex(s string) ([]byte, error) {
+	byts, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string: %w", err)
+	}
+	return byts, nil
+}
+
+// encodeHex encodes bytes as a 0x-prefixed hex string.
+func encodeHex(byts []byte) string {
+	return "0x" + hex.EncodeToString(byts)
+}
+
+// readRequired reads the input of the flag `name`, which must have been set.
+func readRequired(env *env, name, value string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("-%s is required", name)
+	}
+	byts, err := readInput(env, value)
+	if err != nil {
+		return nil, fmt.Errorf("-%s: %w", name, err)
+	}
+	return byts, nil
+}
+
+// readFixed reads the input of the flag `name`, which must be exactly `size` bytes long.
+func readFixed(env *env, name, value string, size int) ([]byte, error) {
+	byts, err := readRequired(env, name, value)
+	if err != nil {
+		return nil, err
+	}
+	if len(byts) != size {
+		return nil, fmt.Errorf("-%s: expected %d bytes, found %d", name, size, len(byts))
+	}
+	return byts, nil
+}
+
+// readList reads the comma-separated inputs of the flag `name`, each of which holds one or more items of `itemSize`
+// bytes.
+func readList(env *env, name, value string, itemSize int) ([][]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("-%s is required", name)
+	}
+
+	var items [][]byte
+	for _, part := range strings.Split(value, ",") {
+		byts, err := readInput(env, part)
+		if err != nil {
+			return nil, fmt.Errorf("-%s: %w", name, err)
+		}
+		if len(byts) == 0 || len(byts)%itemSize != 0 {
+			return nil, fmt.Errorf("-%s: expected a multiple of %d bytes, found %d", name, itemSize, len(byts))
+		}
+		for start := 0; start < len(byts); start += itemSize {
+			items = append(items, byts[start:start+itemSize])
+		}
+	}
+	return items, nil
+}
+
+// parseIndices parses a comma-separated list of cell indices.
+func parseIndices(name, value string) ([]uint64, error) {
+	if value == "" {
+		return nil, fmt.Errorf("-%s is required", name)
+	}
+
+	parts := strings.Split(value, ",")
+	indices := make([]uint64, len(parts))
+	for i, part := range parts {
+		index, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("-%s: invalid cell index %q", name, part)
+		}
+		indices[i] = index
+	}
+	return indices, nil
+}
@@ -0,0 +1,207 @@
+// Command kzg computes and verifies the KZG commitments, proofs and cells that are used by EIP-4844 and EIP-7594.
+//
+// Usage:
+//
+//	kzg <command> [flags]
+//
+// Inputs such as blobs, commitments and proofs are given as a 0x-prefixed hex string, or as the path of a file that
+// holds either the raw bytes or a 0x-prefixed hex string. The path "-" reads from stdin. Flags that take a list accept
+// comma-separated values, and a file in a list may hold several items back to back, such as a column of cells.
+//
+// Results are written to stdout as JSON. The verify commands exit with status 1 if the proofs do not verify, and every
+// command exits with status 2 if it could not run, which includes inputs that could not be decoded.
+//
+// The mainnet trusted setup is used unless -setup names a trusted setup file in the JSON (.json), c-kzg text (.txt)
+// or binary format. The blob size is then taken from the number of points in the setup, and the cell size from
+// -cell-size, or from the number of G2 points if it is not given.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/crate-crypto/go-eth-kzg/cmd/internal/setupfile"
+)
+
+// Exit codes of the command.
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitError   = 2
+)
+
+// numGoRoutines lets the library use all of the available CPUs.
+const numGoRoutines = 0
+
+// command is a subcommand of the tool.
+type command struct {
+	name    string
+	summary string
+	run     func(env *env, args []string) error
+}
+
+var commands = []command{
+	{"commit", "compute the commitment to a blob", runCommit},
+	{"prove-blob", "compute the proof for a blob and its commitment", runProveBlob},
+	{"prove-point", "compute the proof for the evaluation of a blob at a point", runProvePoint},
+	{"verify-blob", "verify the proof for a blob and its commitment", runVerifyBlob},
+	{"verify-point", "verify the proof for the evaluation of a blob at a point", runVerifyPoint},
+	{"compute-cells", "compute the cells of the extended blob and their proofs", runComputeCells},
+	{"verify-cells", "verify the proofs for a batch of cells", runVerifyCells},
+	{"recover", "recover all of the cells and their proofs from half of the cells", runRecover},
+	{"versioned-hash", "compute the versioned hash of a commitment", runVersionedHash},
+	{"inspect", "report the non-canonical field elements of a blob and evaluate it at a point", runInspect},
+}
+
+// env holds the streams that a command reads from and writes to.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// errInvalid is returned by the verify commands when the proofs do not verify. The result has already been written
+// out, so only the exit status is left to set.
+var errInvalid = errors.New("verification failed")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command given by `args` and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return exitError
+		}
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(&env{stdin: stdin, stdout: stdout, stderr: stderr}, args[1:])
+		switch {
+		case err == nil:
+			return exitOK
+		case errors.Is(err, errInvalid):
+			return exitInvalid
+		case errors.Is(err, flag.ErrHelp):
+			return exitOK
+		default:
+			fmt.Fprintf(stderr, "kzg %s: %v\n", cmd.name, err)
+			return exitError
+		}
+	}
+
+	fmt.Fprintf(stderr, "kzg: unknown command %q\n\n", args[0])
+	usage(stderr)
+	return exitError
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: kzg <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-15s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'kzg <command> -h' for the flags of a command.")
+}
+
+// newFlagSet creates the flag set for a command, which reports errors instead of exiting.
+func newFlagSet(env *env, name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("kzg "+name, flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	return flags
+}
+
+// setupFlags are the flags of the commands that need a [goethkzg.Context].
+type setupFlags struct {
+	path            string
+	cellSize        uint64
+	expansionFactor uint64
+}
+
+func (s *setupFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&s.path, "setup", "", "trusted setup file in the JSON (.json), c-kzg text (.txt) or binary format (default: mainnet)")
+	flags.Uint64Var(&s.cellSize, "cell-size", 0, "number of field elements in a cell, when using -setup (default: one less than the number of G2 points)")
+	flags.Uint64Var(&s.expansionFactor, "expansion-factor", 2, "ratio of the extended blob size to the blob size, when using -setup")
+}
+
+// newContext creates the context for the trusted setup given by the flags.
+func (s *setupFlags) newContext(env *env) (*goethkzg.Context, error) {
+	if s.path == "" {
+		return goethkzg.NewContext4096Secure()
+	}
+
+	trustedSetup, err := setupfile.Load(env.stdin, s.path, "")
+	if err != nil {
+		return nil, err
+	}
+	// The cell verifier needs one more G2 point than there are field elements in a cell, so this is the largest
+	// cell size that the setup allows. A setup may have more G2 points than the cells that it is used with.
+	cellSize := s.cellSize
+	if cellSize == 0 {
+		cellSize = uint64(len(trustedSetup.SetupG2)) - 1
+	}
+	params := goethkzg.Params{
+		FieldElementsPerBlob: uint64(len(trustedSetup.SetupG1Lagrange)),
+		FieldElementsPerCell: cellSize,
+		ExpansionFactor:      s.expansionFactor,
+	}
+	return goethkzg.NewContextWithParams(trustedSetup, params)
+}
+
+// writeJSON writes the result of a command.
+func writeJSON(w io.Writer, result any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// verification is the result of the verify commands.
+type verification struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// writeVerification writes the result of a verify command, and returns errInvalid if `err` is set.
+//
+// Inputs that could not be decoded are not a failed verification, so they are returned as an error instead.
+func writeVerification(w io.Writer, err error) error {
+	if isMalformed(err) {
+		return err
+	}
+
+	result := verification{Valid: err == nil}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if writeErr := writeJSON(w, result); writeErr != nil {
+		return writeErr
+	}
+	if err != nil {
+		return errInvalid
+	}
+	return nil
+}
+
+// isMalformed returns true if `err` was caused by inputs that could not be decoded. The batch methods say so with the
+// kind of their [goethkzg.BatchError]. The other methods return the error of the input that could not be decoded, so
+// for them any error other than a failed verification is about the inputs.
+func isMalformed(err error) bool {
+	var batchErr *goethkzg.BatchError
+	if errors.As(err, &batchErr) {
+		return batchErr.Kind.IsMalformed()
+	}
+	return err != nil && !errors.Is(err, goethkzg.ErrVerifyOpeningProof)
+}
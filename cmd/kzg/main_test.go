@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/stretchr/testify/require"
+)
+
+var testParams = goethkzg.Params{
+	FieldElementsPerBlob: 64,
+	FieldElementsPerCell: 8,
+	ExpansionFactor:      2,
+}
+
+// writeTestSetup writes an insecure trusted setup for testParams and returns its path.
+func writeTestSetup(t *testing.T) string {
+	t.Helper()
+	trustedSetup, err := goethkzg.NewInsecureTrustedSetupForTesting(big.NewInt(1337), testParams)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "setup.txt")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	require.NoError(t, goethkzg.WriteTrustedSetupText(file, trustedSetup))
+	return path
+}
+
+// runJSON runs the tool and decodes its output into a map.
+func runJSON(t *testing.T, expectedStatus int, args ...string) map[string]any {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(""), &stdout, &stderr)
+	require.Equal(t, expectedStatus, status, "stderr: %s", stderr.String())
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	return result
+}
+
+func testBlob() []byte {
+	blob := make([]byte, testParams.BytesPerBlob())
+	for i := 0; i < len(blob); i += goethkzg.SerializedScalarSize {
+		blob[i+goethkzg.SerializedScalarSize-1] = byte(i)
+	}
+	return blob
+}
+
+func TestRun(t *testing.T) {
+	setup := writeTestSetup(t)
+	blob := testBlob()
+
+	// The blob is given as a raw file, everything else as hex
+	blobPath := filepath.Join(t.TempDir(), "blob.bin")
+	require.NoError(t, os.WriteFile(blobPath, blob, 0o600))
+
+	commit := runJSON(t, exitOK, "commit", "-setup", setup, "-blob", blobPath)
+	commitment := commit["commitment"].(string)
+
+	proveBlob := runJSON(t, exitOK, "prove-blob", "-setup", setup, "-blob", encodeHex(blob))
+	require.Equal(t, commitment, proveBlob["commitment"])
+	proof := proveBlob["proof"].(string)
+
+	verifyBlob := runJSON(t, exitOK, "verify-blob", "-setup", setup, "-blob", blobPath, "-commitment", commitment, "-proof", proof)
+	require.Equal(t, true, verifyBlob["valid"])
+	verifyBlob = runJSON(t, exitInvalid, "verify-blob", "-setup", setup, "-blob", blobPath, "-commitment", commitment, "-proof", commitment)
+	require.Equal(t, false, verifyBlob["valid"])
+	require.NotEmpty(t, verifyBlob["error"])
+
+	z := encodeHex(make([]byte, 31)) + "05"
+	provePoint := runJSON(t, exitOK, "prove-point", "-setup", setup, "-blob", blobPath, "-z", z)
+	require.Equal(t, commitment, provePoint["commitment"])
+	y := provePoint["y"].(string)
+	verifyPoint := runJSON(t, exitOK, "verify-point", "-setup", setup, "-commitment", commitment, "-z", z, "-y", y, "-proof", provePoint["proof"].(string))
+	require.Equal(t, true, verifyPoint["valid"])
+
+	inspect := runJSON(t, exitOK, "inspect", "-setup", setup, "-blob", blobPath, "-z", z)
+	require.Equal(t, float64(testParams.FieldElementsPerBlob), inspect["field_elements"])
+	require.Empty(t, inspect["non_canonical"])
+	require.Equal(t, y, inspect["evaluation"].(map[string]any)["y"])
+
+	computeCells := runJSON(t, exitOK, "compute-cells", "-setup", setup, "-blob", blobPath)
+	cells := computeCells["cells"].([]any)
+	proofs := computeCells["proofs"].([]any)
+	require.Len(t, cells, int(testParams.CellsPerExtBlob()))
+
+	// Verify and recover using every other cell, with the cells given as one column file
+	var indices, cellProofs []string
+	var column []byte
+	for i := 0; i < len(cells); i += 2 {
+		indices = append(indices, strconv.Itoa(i))
+		cellProofs = append(cellProofs, proofs[i].(string))
+		cell, err := decodeHex(cells[i].(string))
+		require.NoError(t, err)
+		column = append(column, cell...)
+	}
+	columnPath := filepath.Join(t.TempDir(), "column.bin")
+	require.NoError(t, os.WriteFile(columnPath, column, 0o600))
+
+	verifyCells := runJSON(t, exitOK, "verify-cells", "-setup", setup, "-commitments", commitment, "-cell-indices", strings.Join(indices, ","), "-cells", columnPath, "-proofs", strings.Join(cellProofs, ","))
+	require.Equal(t, true, verifyCells["valid"])
+
+	recovered := runJSON(t, exitOK, "recover", "-setup", setup, "-cell-indices", strings.Join(indices, ","), "-cells", columnPath)
+	require.Equal(t, computeCells, recovered)
+
+	// Known answer from the versioned hash of the point at infinity
+	versionedHash := runJSON(t, exitOK, "versioned-hash", "-commitment", encodeHex(goethkzg.PointAtInfinity[:]))
+	require.Equal(t, "0x010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c444014", versionedHash["versioned_hash"])
+}
+
+func TestRunInspectNonCanonical(t *testing.T) {
+	blob := testBlob()
+	copy(blob[3*goethkzg.SerializedScalarSize:], goethkzg.BlsModulus[:])
+
+	inspect := runJSON(t, exitOK, "inspect", "-blob", encodeHex(blob))
+	require.Equal(t, []any{float64(3)}, inspect["non_canonical"])
+	require.Nil(t, inspect["evaluation"])
+}
+
+func TestRunErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitError, run(nil, nil, &stdout, &stderr))
+	require.Equal(t, exitError, run([]string{"unknown"}, nil, &stdout, &stderr))
+	require.Equal(t, exitOK, run([]string{"help"}, nil, &stdout, &stderr))
+
+	stderr.Reset()
+	require.Equal(t, exitError, run([]string{"versioned-hash"}, nil, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "-commitment is required")
+
+	stderr.Reset()
+	require.Equal(t, exitError, run([]string{"versioned-hash", "-commitment", "0x1234"}, nil, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "expected 48 bytes, found 2")
+
+	stderr.Reset()
+	require.Equal(t, exitError, run([]string{"versioned-hash", "-commitment", "0xzz"}, nil, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "invalid hex string")
+}
+
+func TestRunCellSize(t *testing.T) {
+	// The setup has enough G2 points for cells that are twice as large as testParams
+	params := testParams
+	params.FieldElementsPerCell *= 2
+	trustedSetup, err := goethkzg.NewInsecureTrustedSetupForTesting(big.NewInt(1337), params)
+	require.NoError(t, err)
+	setup := filepath.Join(t.TempDir(), "setup.json")
+	file, err := os.Create(setup)
+	require.NoError(t, err)
+	require.NoError(t, goethkzg.WriteTrustedSetupJSON(file, trustedSetup))
+	require.NoError(t, file.Close())
+
+	computeCells := runJSON(t, exitOK, "compute-cells", "-setup", setup, "-blob", encodeHex(testBlob()))
+	require.Len(t, computeCells["cells"], int(params.CellsPerExtBlob()))
+
+	computeCells = runJSON(t, exitOK, "compute-cells", "-setup", setup, "-cell-size", strconv.Itoa(int(testParams.FieldElementsPerCell)), "-blob", encodeHex(testBlob()))
+	require.Len(t, computeCells["cells"], int(testParams.CellsPerExtBlob()))
+}
+
+func TestRunMalformedInputs(t *testing.T) {
+	setup := writeTestSetup(t)
+	blob := testBlob()
+	commit := runJSON(t, exitOK, "commit", "-setup", setup, "-blob", encodeHex(blob))
+	commitment := commit["commitment"].(string)
+
+	// Inputs that cannot be decoded are reported as an error rather than as an invalid proof
+	nonCanonical := slices.Clone(blob)
+	copy(nonCanonical, goethkzg.BlsModulus[:])
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"verify-blob", "-setup", setup, "-blob", encodeHex(nonCanonical), "-commitment", commitment, "-proof", commitment}, nil, &stdout, &stderr)
+	require.Equal(t, exitError, status)
+	require.Contains(t, stderr.String(), goethkzg.ErrNonCanonicalScalar.Error())
+	require.Empty(t, stdout.String())
+
+	notAPoint := "0x" + strings.Repeat("ff", goethkzg.CompressedG1Size)
+	stderr.Reset()
+	status = run([]string{"verify-point", "-setup", setup, "-commitment", commitment, "-z", encodeHex(make([]byte, 32)), "-y", encodeHex(make([]byte, 32)), "-proof", notAPoint}, nil, &stdout, &stderr)
+	require.Equal(t, exitError, status, "stderr: %s", stderr.String())
+
+	stderr.Reset()
+	status = run([]string{"verify-cells", "-setup", setup, "-commitments", commitment, "-cell-indices", "0", "-cells", encodeHex(make([]byte, testParams.BytesPerCell())), "-proofs", notAPoint}, nil, &stdout, &stderr)
+	require.Equal(t, exitError, status, "stderr: %s", stderr.String())
+	require.Empty(t, stdout.String())
+}
@@ -341,17 +341,20 @@ func TestVerifyBlobKZGProofBatch(t *testing.T) {
 
 			err = ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
 			errPar := ctx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs)
-			require.Equal(t, err, errPar)
+			// The parallel version checks each proof on its own, so it may report a different
+			// element of the batch, but it must agree on whether and why the batch was rejected
+			require.Equal(t, err == nil, errPar == nil)
+			require.Equal(t, errors.Is(err, kzg.ErrVerifyOpeningProof), errors.Is(errPar, kzg.ErrVerifyOpeningProof))
 
 			// Test specifically distinguish between the test failing
 			// because of the pairing check and failing because of
 			// validation errors
-			if err != nil && err != kzg.ErrVerifyOpeningProof {
+			if err != nil && !errors.Is(err, kzg.ErrVerifyOpeningProof) {
 				require.False(t, testCaseValid)
 			} else {
 				// Either the error is nil or it is a verification error
 				expectedOutput := *test.ProofIsValid
-				gotOutput := err != kzg.ErrVerifyOpeningProof
+				gotOutput := !errors.Is(err, kzg.ErrVerifyOpeningProof)
 				require.Equal(t, expectedOutput, gotOutput)
 			}
 		})
@@ -0,0 +1,299 @@
+package goethkzg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"github.com/crate-crypto/go-eth-kzg/internal/codec"
+	"github.com/crate-crypto/go-eth-kzg/internal/domain"
+	"github.com/crate-crypto/go-eth-kzg/internal/erasure_code"
+	"github.com/crate-crypto/go-eth-kzg/internal/kzg"
+	kzgmulti "github.com/crate-crypto/go-eth-kzg/internal/kzg_multi"
+	"github.com/crate-crypto/go-eth-kzg/internal/kzg_multi/fk20"
+)
+
+// A serialized Context has the following layout:
+//
+//   - magic:    8 bytes, always equal to `contextMagic`
+//   - version:  8 bytes, big-endian
+//   - length:   8 bytes, big-endian length of the payload
+//   - payload:  `length` bytes holding the [Params], the setup fingerprint and the precomputed tables that are present in the Context
+//   - checksum: 32 bytes, SHA-256 of everything before it
+//
+// Group elements in the payload are stored uncompressed and without any
+// subgroup information. Loading a Context therefore skips all of the point
+// decompression, subgroup checks and FFTs that [NewContext4096] performs.
+// Since no checks are done on the points, the checksum is what protects us
+// from loading a corrupted file.
+//
+// Note: A serialized Context should be treated the same as the trusted setup
+// that it was created from. Only load snapshots that you created yourself or
+// that came from a trusted source.
+
+// contextMagic identifies a serialized Context.
+var contextMagic = [8]byte{'G', 'O', 'E', 'T', 'H', 'K', 'Z', 'G'}
+
+// contextFormatVersion is incremented whenever the layout of the payload changes.
+const contextFormatVersion = 1
+
+// The payload starts with a set of flags that say which of the optional parts of the
+// Context it holds. See [contextParts].
+const (
+	hasEIP4844Prover = 1 << iota
+	hasEIP7594Prover
+	hasEIP7594Verifier
+)
+
+// contextHeaderSize is the size of the magic, version and length fields.
+const contextHeaderSize = len(contextMagic) + 8 + 8
+
+// contextChecksumSize is the size of the SHA-256 checksum at the end of a serialized Context.
+const contextChecksumSize = sha256.Size
+
+// maxContextPayloadSize bounds the amount of memory that [Context.ReadFrom] will allocate.
+// The mainnet Context is about 3MB, so this leaves plenty of headroom.
+const maxContextPayloadSize = 1 << 30
+
+// MarshalBinary serializes the Context, including all of its precomputed tables,
+// so that it can be loaded with [UnmarshalContext] without reprocessing the trusted setup.
+func (ctx *Context) MarshalBinary() ([]byte, error) {
+	var flags uint64
+	if ctx.commitKeyLagrange != nil {
+		flags |= hasEIP4844Prover
+	}
+	if ctx.fk20 != nil {
+		flags |= hasEIP7594Prover
+	}
+	if ctx.openKey7594 != nil {
+		flags |= hasEIP7594Verifier
+	}
+
+	enc := codec.NewEncoder(0)
+	enc.PutUint64(ctx.params.FieldElementsPerBlob)
+	enc.PutUint64(ctx.params.FieldElementsPerCell)
+	enc.PutUint64(ctx.params.ExpansionFactor)
+	enc.PutUint64(flags)
+	enc.PutBytes(ctx.setupFingerprint[:])
+	ctx.domain.Serialize(enc)
+	ctx.openKey4844.Serialize(enc)
+	if flags&hasEIP4844Prover != 0 {
+		ctx.commitKeyLagrange.Serialize(enc)
+	}
+	if flags&hasEIP7594Verifier != 0 {
+		ctx.openKey7594.Serialize(enc)
+	}
+	if flags&hasEIP7594Prover != 0 {
+		ctx.domainExtended.Serialize(enc)
+		ctx.commitKeyMonomial.Serialize(enc)
+		ctx.fk20.Serialize(enc)
+		ctx.dataRecovery.Serialize(enc)
+	}
+	payload := enc.Bytes()
+
+	out := make([]byte, 0, contextHeaderSize+len(payload)+contextChecksumSize)
+	out = append(out, contextMagic[:]...)
+	out = binary.BigEndian.AppendUint64(out, contextFormatVersion)
+	out = binary.BigEndian.AppendUint64(out, uint64(len(payload)))
+	out = append(out, payload...)
+	checksum := sha256.Sum256(out)
+	out = append(out, checksum[:]...)
+
+	return out, nil
+}
+
+// UnmarshalContext creates a Context from data that was produced by [Context.MarshalBinary]
+// or [Context.WriteTo].
+func UnmarshalContext(data []byte) (*Context, error) {
+	if len(data) < contextHeaderSize+contextChecksumSize {
+		return nil, ErrContextSnapshotTruncated
+	}
+	if !bytes.Equal(data[:len(contextMagic)], contextMagic[:]) {
+		return nil, ErrContextSnapshotMagic
+	}
+	version := binary.BigEndian.Uint64(data[len(contextMagic):])
+	if version != contextFormatVersion {
+		return nil, ErrContextSnapshotVersion
+	}
+	payloadLen := binary.BigEndian.Uint64(data[len(contextMagic)+8:])
+	if payloadLen != uint64(len(data)-contextHeaderSize-contextChecksumSize) {
+		return nil, ErrContextSnapshotTruncated
+	}
+
+	checksumOffset := len(data) - contextChecksumSize
+	checksum := sha256.Sum256(data[:checksumOffset])
+	if !bytes.Equal(checksum[:], data[checksumOffset:]) {
+		return nil, ErrContextSnapshotChecksum
+	}
+
+	dec := codec.NewDecoder(data[contextHeaderSize:checksumOffset])
+	ctx, err := deserializeContext(dec)
+	if err != nil {
+		return nil, err
+	}
+	if dec.Remaining() != 0 {
+		return nil, ErrContextSnapshotTrailingData
+	}
+
+	return ctx, nil
+}
+
+func deserializeContext(dec *codec.Decoder) (*Context, error) {
+	params := Params{
+		FieldElementsPerBlob: dec.Uint64(),
+		FieldElementsPerCell: dec.Uint64(),
+		ExpansionFactor:      dec.Uint64(),
+	}
+	flags := dec.Uint64()
+	var setupFingerprint Fingerprint
+	copy(setupFingerprint[:], dec.Bytes(len(setupFingerprint)))
+	if dec.Err() != nil {
+		return nil, dec.Err()
+	}
+	if err := params.validate(); err != nil {
+		return nil, ErrContextSnapshotMalformed
+	}
+	if flags&^(hasEIP4844Prover|hasEIP7594Prover|hasEIP7594Verifier) != 0 {
+		return nil, ErrContextSnapshotMalformed
+	}
+	// The cell prover is never built without the cell verifier
+	if flags&hasEIP7594Prover != 0 && flags&hasEIP7594Verifier == 0 {
+		return nil, ErrContextSnapshotMalformed
+	}
+
+	scalarsPerExtBlob := params.FieldElementsPerExtBlob()
+
+	domainBlobLen, err := domain.DeserializeDomain(dec)
+	if err != nil {
+		return nil, err
+	}
+	if domainBlobLen.Cardinality != params.FieldElementsPerBlob {
+		return nil, ErrContextSnapshotMalformed
+	}
+	openKey4844, err := kzg.DeserializeOpeningKey(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &Context{
+		params:           params,
+		domain:           domainBlobLen,
+		openKey4844:      openKey4844,
+		setupFingerprint: setupFingerprint,
+	}
+
+	if flags&hasEIP4844Prover != 0 {
+		commitKeyLagrange, err := kzg.DeserializeCommitKey(dec)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(commitKeyLagrange.G1)) != params.FieldElementsPerBlob {
+			return nil, ErrContextSnapshotMalformed
+		}
+		ctx.commitKeyLagrange = commitKeyLagrange
+	}
+
+	if flags&hasEIP7594Verifier != 0 {
+		openKey7594, err := kzgmulti.DeserializeOpeningKey(dec)
+		if err != nil {
+			return nil, err
+		}
+		if openKey7594.PolySize != params.FieldElementsPerBlob ||
+			openKey7594.NumPointsToOpen != scalarsPerExtBlob ||
+			openKey7594.CosetSize != params.FieldElementsPerCell {
+			return nil, ErrContextSnapshotMalformed
+		}
+		ctx.openKey7594 = openKey7594
+	}
+
+	if flags&hasEIP7594Prover != 0 {
+		domainExtended, err := domain.DeserializeDomain(dec)
+		if err != nil {
+			return nil, err
+		}
+		commitKeyMonomial, err := kzg.DeserializeCommitKey(dec)
+		if err != nil {
+			return nil, err
+		}
+		fk20Instance, err := fk20.DeserializeFK20(dec)
+		if err != nil {
+			return nil, err
+		}
+		dataRecovery, err := erasure_code.DeserializeDataRecovery(dec)
+		if err != nil {
+			return nil, err
+		}
+		if domainExtended.Cardinality != scalarsPerExtBlob ||
+			uint64(len(commitKeyMonomial.G1)) != params.FieldElementsPerBlob {
+			return nil, ErrContextSnapshotMalformed
+		}
+		// The FK20 instance opens a polynomial of the blob's size on every cell, and has a Toeplitz matrix for
+		// every point in a cell
+		if uint64(fk20Instance.PolySize()) != params.FieldElementsPerBlob ||
+			uint64(fk20Instance.CosetSize()) != params.FieldElementsPerCell ||
+			uint64(fk20Instance.NumCosets()) != params.CellsPerExtBlob() ||
+			uint64(fk20Instance.NumToeplitzMatrices()) != params.FieldElementsPerCell {
+			return nil, ErrContextSnapshotMalformed
+		}
+		if uint64(dataRecovery.BlockErasureSize()) != params.FieldElementsPerCell ||
+			uint64(dataRecovery.NumScalarsInDataWord()) != params.FieldElementsPerBlob ||
+			uint64(dataRecovery.ExpansionFactor()) != params.ExpansionFactor ||
+			uint64(dataRecovery.TotalNumBlocks()) != params.CellsPerExtBlob() {
+			return nil, ErrContextSnapshotMalformed
+		}
+		ctx.domainExtended = domainExtended
+		ctx.commitKeyMonomial = commitKeyMonomial
+		ctx.fk20 = fk20Instance
+		ctx.dataRecovery = dataRecovery
+	}
+
+	return ctx, nil
+}
+
+// WriteTo writes the serialized Context to `w`. It implements [io.WriterTo].
+//
+// See [Context.MarshalBinary] for details.
+func (ctx *Context) WriteTo(w io.Writer) (int64, error) {
+	data, err := ctx.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom reads a serialized Context from `r` and replaces the contents of `ctx`
+// with it. It implements [io.ReaderFrom].
+//
+// Only the bytes belonging to the serialized Context are consumed from `r`.
+func (ctx *Context) ReadFrom(r io.Reader) (int64, error) {
+	header := make([]byte, contextHeaderSize)
+	n, err := io.ReadFull(r, header)
+	if err != nil {
+		return int64(n), ErrContextSnapshotTruncated
+	}
+	if !bytes.Equal(header[:len(contextMagic)], contextMagic[:]) {
+		return int64(n), ErrContextSnapshotMagic
+	}
+	payloadLen := binary.BigEndian.Uint64(header[len(contextMagic)+8:])
+	if payloadLen > maxContextPayloadSize {
+		return int64(n), ErrContextSnapshotMalformed
+	}
+
+	data := make([]byte, contextHeaderSize+int(payloadLen)+contextChecksumSize)
+	copy(data, header)
+	m, err := io.ReadFull(r, data[contextHeaderSize:])
+	total := int64(n + m)
+	if err != nil {
+		return total, ErrContextSnapshotTruncated
+	}
+
+	decoded, err := UnmarshalContext(data)
+	if err != nil {
+		return total, err
+	}
+	*ctx = *decoded
+
+	return total, nil
+}
@@ -0,0 +1,60 @@
+package goethkzg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/crate-crypto/go-eth-kzg/internal/erasure_code"
+	"github.com/crate-crypto/go-eth-kzg/internal/kzg_multi/fk20"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnmarshalContextChecksDimensions checks that a snapshot whose precomputed tables were built for other
+// parameters is rejected, even though each of the tables is well formed on its own.
+func TestUnmarshalContextChecksDimensions(t *testing.T) {
+	params := Params{FieldElementsPerBlob: 256, FieldElementsPerCell: 16, ExpansionFactor: 4}
+	newCtx := func() *Context {
+		ctx, err := NewInsecureContextForTesting(big.NewInt(1337), params)
+		require.NoError(t, err)
+		return ctx
+	}
+
+	serialized, err := newCtx().MarshalBinary()
+	require.NoError(t, err)
+	_, err = UnmarshalContext(serialized)
+	require.NoError(t, err)
+
+	t.Run("fk20", func(t *testing.T) {
+		ctx := newCtx()
+		fk := fk20.NewFK20(ctx.commitKeyMonomial.G1, int(params.FieldElementsPerExtBlob()), int(params.FieldElementsPerCell)/2)
+		ctx.fk20 = &fk
+		serialized, err := ctx.MarshalBinary()
+		require.NoError(t, err)
+		_, err = UnmarshalContext(serialized)
+		require.ErrorIs(t, err, ErrContextSnapshotMalformed)
+
+		ctx = newCtx()
+		fk = fk20.NewFK20(ctx.commitKeyMonomial.G1[:params.FieldElementsPerBlob/2], int(params.FieldElementsPerExtBlob()), int(params.FieldElementsPerCell))
+		ctx.fk20 = &fk
+		serialized, err = ctx.MarshalBinary()
+		require.NoError(t, err)
+		_, err = UnmarshalContext(serialized)
+		require.ErrorIs(t, err, ErrContextSnapshotMalformed)
+	})
+
+	t.Run("data recovery", func(t *testing.T) {
+		ctx := newCtx()
+		ctx.dataRecovery = erasure_code.NewDataRecovery(int(params.FieldElementsPerCell)/2, int(params.FieldElementsPerBlob), int(params.ExpansionFactor))
+		serialized, err := ctx.MarshalBinary()
+		require.NoError(t, err)
+		_, err = UnmarshalContext(serialized)
+		require.ErrorIs(t, err, ErrContextSnapshotMalformed)
+
+		ctx = newCtx()
+		ctx.dataRecovery = erasure_code.NewDataRecovery(int(params.FieldElementsPerCell), int(params.FieldElementsPerBlob)*2, int(params.ExpansionFactor)/2)
+		serialized, err = ctx.MarshalBinary()
+		require.NoError(t, err)
+		_, err = UnmarshalContext(serialized)
+		require.ErrorIs(t, err, ErrContextSnapshotMalformed)
+	})
+}
@@ -0,0 +1,115 @@
+package goethkzg_test
+
+import (
+	"bytes"
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextSerializationRoundTrip(t *testing.T) {
+	serialized, err := ctx.MarshalBinary()
+	require.NoError(t, err)
+
+	loaded, err := goethkzg.UnmarshalContext(serialized)
+	require.NoError(t, err)
+
+	// Serializing the loaded context should give back the exact same bytes
+	reserialized, err := loaded.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, serialized, reserialized)
+
+	// The loaded context should produce the same results as the original
+	blob := GetRandBlob(1)
+	expectedCommitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	gotCommitment, err := loaded.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCommitment, gotCommitment)
+
+	expectedCells, expectedProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	gotCells, gotProofs, err := loaded.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, gotCells)
+	require.Equal(t, expectedProofs, gotProofs)
+
+	blobProof, err := loaded.ComputeBlobKZGProof(blob, gotCommitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, loaded.VerifyBlobKZGProof(blob, gotCommitment, blobProof))
+
+	commitments := make([]goethkzg.KZGCommitment, goethkzg.CellsPerExtBlob)
+	cellIndices := make([]uint64, goethkzg.CellsPerExtBlob)
+	for i := range commitments {
+		commitments[i] = gotCommitment
+		cellIndices[i] = uint64(i)
+	}
+	require.NoError(t, loaded.VerifyCellKZGProofBatch(commitments, cellIndices, gotCells[:], gotProofs[:]))
+
+	cellIDs := make([]uint64, 0, goethkzg.CellsPerExtBlob/2)
+	halfCells := make([]*goethkzg.Cell, 0, goethkzg.CellsPerExtBlob/2)
+	for i := 0; i < goethkzg.CellsPerExtBlob; i += 2 {
+		cellIDs = append(cellIDs, uint64(i))
+		halfCells = append(halfCells, gotCells[i])
+	}
+	recoveredCells, recoveredProofs, err := loaded.RecoverCellsAndComputeKZGProofs(cellIDs, halfCells, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, recoveredCells)
+	require.Equal(t, expectedProofs, recoveredProofs)
+}
+
+func TestContextWriteToReadFrom(t *testing.T) {
+	var buf bytes.Buffer
+	written, err := ctx.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), written)
+
+	// Data following the serialized context should not be consumed
+	trailer := []byte("trailing data")
+	buf.Write(trailer)
+
+	var loaded goethkzg.Context
+	read, err := loaded.ReadFrom(&buf)
+	require.NoError(t, err)
+	require.Equal(t, written, read)
+	require.Equal(t, trailer, buf.Bytes())
+
+	blob := GetRandBlob(2)
+	expectedCommitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	gotCommitment, err := loaded.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCommitment, gotCommitment)
+}
+
+func TestContextSerializationRejectsCorruptData(t *testing.T) {
+	serialized, err := ctx.MarshalBinary()
+	require.NoError(t, err)
+
+	corrupt := func(offset int) []byte {
+		data := bytes.Clone(serialized)
+		data[offset] ^= 1
+		return data
+	}
+
+	_, err = goethkzg.UnmarshalContext(corrupt(0))
+	require.ErrorIs(t, err, goethkzg.ErrContextSnapshotMagic)
+
+	// The version is the last byte of the second 8 byte word
+	_, err = goethkzg.UnmarshalContext(corrupt(15))
+	require.ErrorIs(t, err, goethkzg.ErrContextSnapshotVersion)
+
+	_, err = goethkzg.UnmarshalContext(corrupt(len(serialized) / 2))
+	require.ErrorIs(t, err, goethkzg.ErrContextSnapshotChecksum)
+
+	_, err = goethkzg.UnmarshalContext(corrupt(len(serialized) - 1))
+	require.ErrorIs(t, err, goethkzg.ErrContextSnapshotChecksum)
+
+	_, err = goethkzg.UnmarshalContext(serialized[:len(serialized)-1])
+	require.ErrorIs(t, err, goethkzg.ErrContextSnapshotTruncated)
+
+	var loaded goethkzg.Context
+	_, err = loaded.ReadFrom(bytes.NewReader(serialized[:len(serialized)/2]))
+	require.ErrorIs(t, err, goethkzg.ErrContextSnapshotTruncated)
+}
@@ -0,0 +1,113 @@
+package goethkzg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DataColumn is one column of the extended blobs of a block, as carried by a [DataColumnSidecar]: the cell of every
+// blob in the column, with the commitment to the blob and the proof of the cell.
+//
+// [DataColumnSidecar]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/das-core.md#datacolumnsidecar
+type DataColumn struct {
+	// Index is the index of the column, which is the cell index of every cell in it.
+	Index       uint64
+	Commitments []KZGCommitment
+	Cells       []*Cell
+	Proofs      []KZGProof
+}
+
+// VerifyDataColumn implements [verify_data_column_sidecar_kzg_proofs]: it checks the proofs of the cells of the
+// column at `columnIndex`, where row i holds the cell of the blob with commitment `commitments[i]`.
+//
+// The column index and the number of rows are checked before anything is deserialized. Errors that are caused by the
+// inputs are returned as a [BatchError], as for [Context.VerifyCellKZGProofBatch], whose Index is the row of the
+// offending cell. An invalid column index is reported with an Index of -1 and wraps [ErrInvalidCellID].
+//
+// [verify_data_column_sidecar_kzg_proofs]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/p2p-interface.md#verify_data_column_sidecar_kzg_proofs
+func (c *Context) VerifyDataColumn(columnIndex uint64, commitments []KZGCommitment, cells []*Cell, proofs []KZGProof) error {
+	if err := c.checkFixedSizeTypes(); err != nil {
+		return err
+	}
+	column := DataColumn{Index: columnIndex, Commitments: commitments, Cells: cells, Proofs: proofs}
+	if err := c.checkDataColumn(-1, column); err != nil {
+		return err
+	}
+	return c.verifyCellKZGProofBatch(context.Background(), commitments, columnCellIndices(column), cellsBytes(cells), proofs)
+}
+
+// VerifyDataColumns is the same as [Context.VerifyDataColumn] for several columns, whose cells are all checked with
+// a single random linear combination. The columns may come from different blocks.
+//
+// The BatchError Index is the position of the offending column in `columns`. If the column has a malformed cell,
+// commitment or proof, the error also says which row it is in.
+func (c *Context) VerifyDataColumns(columns []DataColumn) error {
+	return c.VerifyDataColumnsCtx(context.Background(), columns)
+}
+
+// VerifyDataColumnsCtx is the same as [Context.VerifyDataColumns], but stops early and returns ctx.Err() if `ctx` is
+// cancelled or its deadline passes.
+func (c *Context) VerifyDataColumnsCtx(ctx context.Context, columns []DataColumn) error {
+	if err := c.checkFixedSizeTypes(); err != nil {
+		return err
+	}
+
+	numCells := 0
+	for i, column := range columns {
+		if err := c.checkDataColumn(i, column); err != nil {
+			return err
+		}
+		numCells += len(column.Cells)
+	}
+
+	commitments := make([]KZGCommitment, 0, numCells)
+	cellIndices := make([]uint64, 0, numCells)
+	cells := make([][]byte, 0, numCells)
+	proofs := make([]KZGProof, 0, numCells)
+	for _, column := range columns {
+		commitments = append(commitments, column.Commitments...)
+		cellIndices = append(cellIndices, columnCellIndices(column)...)
+		cells = append(cells, cellsBytes(column.Cells)...)
+		proofs = append(proofs, column.Proofs...)
+	}
+
+	err := c.verifyCellKZGProofBatch(ctx, commitments, cellIndices, cells, proofs)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Index < 0 {
+		return err
+	}
+
+	// Attribute the cell to its column
+	row := batchErr.Index
+	for i, column := range columns {
+		if row < len(column.Cells) {
+			return newBatchError(i, batchErr.Kind, fmt.Errorf("row %d: %w", row, batchErr.Err))
+		}
+		row -= len(column.Cells)
+	}
+	return err
+}
+
+// checkDataColumn checks that the column index refers to a cell of the extended blob and that every row has a
+// commitment, a cell and a proof. Failures are reported as a [BatchError] at `index`.
+func (c *Context) checkDataColumn(index int, column DataColumn) error {
+	if column.Index >= c.params.CellsPerExtBlob() {
+		return newBatchError(index, ErrorKindInvalidCellIndex, fmt.Errorf("%w: column %d", ErrInvalidCellID, column.Index))
+	}
+	numRows := len(column.Cells)
+	if len(column.Commitments) != numRows || len(column.Proofs) != numRows {
+		return newBatchError(index, ErrorKindBatchLength, fmt.Errorf("%w: %d commitments, %d cells and %d proofs",
+			ErrBatchLengthCheck, len(column.Commitments), numRows, len(column.Proofs)))
+	}
+	return nil
+}
+
+// columnCellIndices returns the cell index of every row of the column, which is the index of the column.
+func columnCellIndices(column DataColumn) []uint64 {
+	cellIndices := make([]uint64, len(column.Cells))
+	for i := range cellIndices {
+		cellIndices[i] = column.Index
+	}
+	return cellIndices
+}
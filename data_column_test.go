@@ -0,0 +1,107 @@
+package goethkzg_test
+
+import (
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyDataColumn(t *testing.T) {
+	const numBlobs = 3
+	commitments := make([]goethkzg.KZGCommitment, numBlobs)
+	cells := make([][goethkzg.CellsPerExtBlob]*goethkzg.Cell, numBlobs)
+	proofs := make([][goethkzg.CellsPerExtBlob]goethkzg.KZGProof, numBlobs)
+	for i := range commitments {
+		blob := GetRandBlob(int64(30 + i))
+		var err error
+		commitments[i], err = ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		cells[i], proofs[i], err = ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+		require.NoError(t, err)
+	}
+	column := func(columnIndex uint64) goethkzg.DataColumn {
+		column := goethkzg.DataColumn{Index: columnIndex, Commitments: commitments}
+		for i := range commitments {
+			column.Cells = append(column.Cells, cells[i][columnIndex])
+			column.Proofs = append(column.Proofs, proofs[i][columnIndex])
+		}
+		return column
+	}
+	verifyColumn := func(column goethkzg.DataColumn) error {
+		return ctx.VerifyDataColumn(column.Index, column.Commitments, column.Cells, column.Proofs)
+	}
+
+	for _, columnIndex := range []uint64{0, 77, goethkzg.CellsPerExtBlob - 1} {
+		require.NoError(t, verifyColumn(column(columnIndex)))
+	}
+	columns := []goethkzg.DataColumn{column(3), column(64), column(127)}
+	require.NoError(t, ctx.VerifyDataColumns(columns))
+	require.NoError(t, ctx.VerifyDataColumns(nil))
+	require.NoError(t, verifyColumn(goethkzg.DataColumn{Index: 5}))
+
+	t.Run("column index", func(t *testing.T) {
+		invalid := column(0)
+		invalid.Index = goethkzg.CellsPerExtBlob
+		err := verifyColumn(invalid)
+		requireBatchError(t, err, -1, goethkzg.ErrorKindInvalidCellIndex)
+		require.ErrorIs(t, err, goethkzg.ErrInvalidCellID)
+
+		err = ctx.VerifyDataColumns([]goethkzg.DataColumn{columns[0], invalid})
+		requireBatchError(t, err, 1, goethkzg.ErrorKindInvalidCellIndex)
+		require.ErrorIs(t, err, goethkzg.ErrInvalidCellID)
+
+		// The cells of a column do not verify at another index
+		wrongIndex := column(4)
+		wrongIndex.Index = 5
+		err = verifyColumn(wrongIndex)
+		requireBatchError(t, err, -1, goethkzg.ErrorKindInvalidProof)
+		require.ErrorIs(t, err, goethkzg.ErrVerifyOpeningProof)
+	})
+
+	t.Run("row counts", func(t *testing.T) {
+		short := column(9)
+		short.Proofs = short.Proofs[:numBlobs-1]
+		err := verifyColumn(short)
+		requireBatchError(t, err, -1, goethkzg.ErrorKindBatchLength)
+		require.ErrorIs(t, err, goethkzg.ErrBatchLengthCheck)
+
+		short = column(9)
+		short.Commitments = short.Commitments[1:]
+		err = ctx.VerifyDataColumns([]goethkzg.DataColumn{columns[0], columns[1], short})
+		requireBatchError(t, err, 2, goethkzg.ErrorKindBatchLength)
+		require.ErrorIs(t, err, goethkzg.ErrBatchLengthCheck)
+	})
+
+	t.Run("malformed row", func(t *testing.T) {
+		malformed := column(10)
+		malformed.Proofs = append([]goethkzg.KZGProof(nil), malformed.Proofs...)
+		malformed.Proofs[2] = [48]byte{0xff}
+		err := verifyColumn(malformed)
+		requireBatchError(t, err, 2, goethkzg.ErrorKindMalformedProof)
+
+		err = ctx.VerifyDataColumns([]goethkzg.DataColumn{columns[0], malformed, columns[2]})
+		requireBatchError(t, err, 1, goethkzg.ErrorKindMalformedProof)
+		require.ErrorContains(t, err, "row 2")
+
+		malformed = column(10)
+		malformed.Cells = append([]*goethkzg.Cell(nil), malformed.Cells...)
+		malformed.Cells[1] = nil
+		err = ctx.VerifyDataColumns([]goethkzg.DataColumn{columns[0], columns[1], malformed})
+		requireBatchError(t, err, 2, goethkzg.ErrorKindMalformedCell)
+		require.ErrorIs(t, err, goethkzg.ErrDeserializeNilInput)
+	})
+
+	t.Run("invalid proof", func(t *testing.T) {
+		swapped := column(11)
+		swapped.Proofs = []goethkzg.KZGProof{swapped.Proofs[1], swapped.Proofs[0], swapped.Proofs[2]}
+		err := ctx.VerifyDataColumns([]goethkzg.DataColumn{columns[0], swapped})
+		requireBatchError(t, err, -1, goethkzg.ErrorKindInvalidProof)
+		require.ErrorIs(t, err, goethkzg.ErrVerifyOpeningProof)
+
+		// The cells of another column fail among columns that verify
+		mixed := column(12)
+		mixed.Cells = column(13).Cells
+		require.Error(t, ctx.VerifyDataColumns([]goethkzg.DataColumn{columns[0], mixed, columns[2]}))
+	})
+}
@@ -1,6 +1,11 @@
 package goethkzg
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/crate-crypto/go-eth-kzg/internal/kzg"
+)
 
 var (
 	ErrBatchLengthCheck    = errors.New("all designated elements in the batch should have the same size")
@@ -8,15 +13,148 @@ var (
 	ErrInvalidCellID       = errors.New("cell ID should be less than CellsPerExtBlob")
 	ErrInvalidRowIndex     = errors.New("row index should be less than the number of row commitments")
 	ErrDeserializeNilInput = errors.New("cannot not deserialize nil input")
+	ErrInvalidBlobLength   = errors.New("blob length does not match the context parameters")
+	ErrInvalidCellLength   = errors.New("cell length does not match the context parameters")
 
 	ErrNumCellIDsNotEqualNumCells      = errors.New("number of cell IDs should be equal to the number of cells")
 	ErrCellIDsNotOrdered               = errors.New("cell IDs are not ordered (ascending)")
 	ErrFoundInvalidCellID              = errors.New("cell ID should be less than CellsPerExtBlob")
 	ErrNotEnoughCellsForReconstruction = errors.New("not enough cells to perform reconstruction")
 
+	ErrInvalidParams          = errors.New("invalid context parameters")
+	ErrInvalidTrustedSetupLen = errors.New("trusted setup does not have the number of points required by the context parameters")
+	ErrProverUnavailable      = errors.New("context was created for verification only and cannot compute commitments, proofs or cells")
+	ErrEIP7594Unavailable     = errors.New("context was created for EIP-4844 only and cannot be used for cells")
+	ErrParamsMismatch         = errors.New("the fixed-size Blob and Cell types can only be used with a context created for MainnetParams")
+	ErrReadRandomness         = errors.New("could not read randomness for batch verification")
+	ErrInvalidSecret          = errors.New("secret for an insecure trusted setup must not be nil, zero or one")
+
+	ErrMalformedTrustedSetup    = errors.New("trusted setup is malformed")
+	ErrMissingHexPrefix         = errors.New("hex string is not prefixed with 0x")
+	ErrTrustedSetupInconsistent = errors.New("trusted setup is not consistent")
+	ErrMalformedFingerprint     = errors.New("trusted setup fingerprint is malformed")
+	ErrSetupFingerprintMismatch = errors.New("trusted setup fingerprint does not match the expected value")
+
+	ErrMalformedVersionedHash          = errors.New("versioned hash is malformed")
+	ErrUnsupportedVersionedHashVersion = errors.New("versioned hash does not have the KZG version byte")
+	ErrVersionedHashMismatch           = errors.New("versioned hash does not match the commitment")
+	ErrPointEvaluationInputLength      = errors.New("point evaluation precompile input has the wrong length")
+
+	ErrContextSnapshotMagic        = errors.New("data is not a serialized context")
+	ErrContextSnapshotVersion      = errors.New("serialized context has an unsupported format version")
+	ErrContextSnapshotTruncated    = errors.New("serialized context is truncated")
+	ErrContextSnapshotChecksum     = errors.New("serialized context checksum mismatch")
+	ErrContextSnapshotTrailingData = errors.New("serialized context has trailing data")
+	ErrContextSnapshotMalformed    = errors.New("serialized context is malformed")
+
 	// The following errors indicate that the library constants have not been setup properly.
 	// These should never happen unless the library has been incorrectly modified.
 	ErrNumCosetEvaluationsCheck   = errors.New("expected number of coset evaluations to be `CellsPerExtBlob`")
 	ErrCosetEvaluationLengthCheck = errors.New("expected coset evaluations to have `ScalarsPerCell` number of field elements")
 	ErrNumProofsCheck             = errors.New("expected number of proofs to be `CellsPerExtBlob`")
 )
+
+// ErrVerifyOpeningProof is returned when the proofs are well-formed, but do not prove what they claim to.
+var ErrVerifyOpeningProof = kzg.ErrVerifyOpeningProof
+
+// ErrorKind says why an element of a batch was rejected.
+type ErrorKind int
+
+const (
+	// ErrorKindBatchLength means that the inputs of the batch do not have the same number of elements.
+	ErrorKindBatchLength ErrorKind = iota + 1
+	// ErrorKindMalformedBlob means that a blob has the wrong length or holds a non-canonical scalar.
+	ErrorKindMalformedBlob
+	// ErrorKindMalformedCell means that a cell has the wrong length or holds a non-canonical scalar.
+	ErrorKindMalformedCell
+	// ErrorKindMalformedCommitment means that a commitment is not a valid compressed G1 point in the subgroup.
+	ErrorKindMalformedCommitment
+	// ErrorKindMalformedProof means that a proof is not a valid compressed G1 point in the subgroup.
+	ErrorKindMalformedProof
+	// ErrorKindInvalidCellIndex means that a cell index is not less than the number of cells in an extended blob.
+	ErrorKindInvalidCellIndex
+	// ErrorKindInvalidProof means that the inputs are well-formed, but the proofs do not verify.
+	ErrorKindInvalidProof
+	// ErrorKindVersionedHashMismatch means that a versioned hash is not the versioned hash of its commitment.
+	ErrorKindVersionedHashMismatch
+)
+
+// String returns the name of the kind.
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindBatchLength:
+		return "batch length mismatch"
+	case ErrorKindMalformedBlob:
+		return "malformed blob"
+	case ErrorKindMalformedCell:
+		return "malformed cell"
+	case ErrorKindMalformedCommitment:
+		return "malformed commitment"
+	case ErrorKindMalformedProof:
+		return "malformed proof"
+	case ErrorKindInvalidCellIndex:
+		return "invalid cell index"
+	case ErrorKindInvalidProof:
+		return "invalid proof"
+	case ErrorKindVersionedHashMismatch:
+		return "versioned hash mismatch"
+	default:
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+}
+
+// IsMalformed returns true if the kind describes inputs that could not be decoded, rather than a well-formed proof
+// that failed to verify.
+func (k ErrorKind) IsMalformed() bool {
+	return k >= ErrorKindBatchLength && k < ErrorKindInvalidProof
+}
+
+// BatchError is returned by the methods that take a batch of inputs when one of the elements is rejected.
+//
+// It wraps the underlying error, so `errors.Is(err, ErrNonCanonicalScalar)` and similar checks keep working, and
+// `errors.As` can be used to get the index and kind of the failure.
+type BatchError struct {
+	// Index is the position of the rejected element in the batch.
+	//
+	// It is -1 when the failure cannot be attributed to a single element. This is the case when the lengths of the
+	// inputs do not match, and when a batch of more than one proof fails to verify, since the proofs are checked
+	// together.
+	Index int
+	// Kind says why the element was rejected.
+	Kind ErrorKind
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("%s in batch: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s at batch index %d: %v", e.Kind, e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// newBatchError returns a [BatchError] for the element at `index`.
+func newBatchError(index int, kind ErrorKind, err error) error {
+	return &BatchError{Index: index, Kind: kind, Err: err}
+}
+
+// verificationError returns a [BatchError] for a batch of `batchSize` proofs that were verified together.
+//
+// Only a failed pairing check is reported as a [BatchError]. Other errors, such as a cancelled context, are not caused
+// by the inputs and are returned unchanged.
+func verificationError(batchSize int, err error) error {
+	if !errors.Is(err, ErrVerifyOpeningProof) {
+		return err
+	}
+	index := -1
+	if batchSize == 1 {
+		index = 0
+	}
+	return newBatchError(index, ErrorKindInvalidProof, err)
+}
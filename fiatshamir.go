@@ -3,6 +3,10 @@ package goethkzg
 import (
 	"crypto/sha256"
 	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
@@ -14,24 +18,149 @@ import (
 // [FIAT_SHAMIR_PROTOCOL_DOMAIN]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob
 const DomSepProtocol = "FSBLOBVERIFY_V1_"
 
+// DomSepBlobBatch is a Domain Separator for the challenge that is used to batch verify blob proofs.
+//
+// It matches [RANDOM_CHALLENGE_KZG_BATCH_DOMAIN] in the spec.
+//
+// [RANDOM_CHALLENGE_KZG_BATCH_DOMAIN]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob
+const DomSepBlobBatch = "RCKZGBATCH___V1_"
+
+// DomSepCellBatch is a Domain Separator for the challenge that is used to batch verify cell proofs.
+//
+// It matches [RANDOM_CHALLENGE_KZG_CELL_BATCH_DOMAIN] in the spec.
+//
+// [RANDOM_CHALLENGE_KZG_CELL_BATCH_DOMAIN]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#constants
+const DomSepCellBatch = "RCKZGCBATCH__V1_"
+
 // computeChallenge is provided to match the spec at [compute_challenge].
 //
+// Note: The number of field elements in the blob is taken from the length of `blob`, so that this
+// also works for blobs whose size is given by the context parameters.
+//
 // [compute_challenge]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_challenge
 //
 // [hash_to_bls_field]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#hash_to_bls_field
-func computeChallenge(blob *Blob, commitment KZGCommitment) fr.Element {
+func computeChallenge(blob []byte, commitment KZGCommitment) fr.Element {
 	h := sha256.New()
 	h.Write([]byte(DomSepProtocol))
-	h.Write(u64ToByteArray16(ScalarsPerBlob))
-	h.Write(blob[:])
+	h.Write(u64ToByteArray16(uint64(len(blob) / SerializedScalarSize)))
+	h.Write(blob)
 	h.Write(commitment[:])
 
+	return hashToBlsField(h)
+}
+
+// computeBlobBatchChallenge computes the challenge that [verify_kzg_proof_batch] uses to combine the proofs
+// for a batch of blobs.
+//
+// `zs` and `ys` are the points that each blob polynomial was evaluated at and the results.
+//
+// [verify_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof_batch
+func computeBlobBatchChallenge(fieldElementsPerBlob uint64, commitments []KZGCommitment, zs, ys []fr.Element, proofs []KZGProof) fr.Element {
+	h := sha256.New()
+	h.Write([]byte(DomSepBlobBatch))
+	h.Write(u64ToByteArray16(fieldElementsPerBlob))
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(commitments))))
+	for i := range commitments {
+		z := SerializeScalar(zs[i])
+		y := SerializeScalar(ys[i])
+		h.Write(commitments[i][:])
+		h.Write(z[:])
+		h.Write(y[:])
+		h.Write(proofs[i][:])
+	}
+
+	return hashToBlsField(h)
+}
+
+// computeCellBatchChallenge is provided to match the spec at [compute_verify_cell_kzg_proof_batch_challenge].
+//
+// `commitments` are the deduplicated commitments and `commitmentIndices` says which of them each cell belongs to.
+// The cells have already been checked to hold canonical scalars, so their bytes are the same as serializing their
+// evaluations again.
+//
+// [compute_verify_cell_kzg_proof_batch_challenge]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_verify_cell_kzg_proof_batch_challenge
+func computeCellBatchChallenge(params Params, commitments []KZGCommitment, commitmentIndices, cellIndices []uint64, cells [][]byte, proofs []KZGProof) fr.Element {
+	h := sha256.New()
+	h.Write([]byte(DomSepCellBatch))
+	h.Write(binary.BigEndian.AppendUint64(nil, params.FieldElementsPerBlob))
+	h.Write(binary.BigEndian.AppendUint64(nil, params.FieldElementsPerCell))
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(commitments))))
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(cellIndices))))
+	for _, commitment := range commitments {
+		h.Write(commitment[:])
+	}
+	for k := range cells {
+		h.Write(binary.BigEndian.AppendUint64(nil, commitmentIndices[k]))
+		h.Write(binary.BigEndian.AppendUint64(nil, cellIndices[k]))
+		h.Write(cells[k])
+		h.Write(proofs[k][:])
+	}
+
+	return hashToBlsField(h)
+}
+
+// hashToBlsField reduces the digest of `h` modulo the BLS modulus.
+//
+// It matches [hash_to_bls_field] in the spec.
+//
+// [hash_to_bls_field]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#hash_to_bls_field
+func hashToBlsField(h hash.Hash) fr.Element {
 	digest := h.Sum(nil)
 	var challenge fr.Element
 	challenge.SetBytes(digest[:])
 	return challenge
 }
 
+// batchVerificationScalar returns the number whose powers are used to combine the proofs in a batch.
+//
+// In deterministic mode this is the spec's challenge, which is computed by `challenge`. Otherwise it
+// is sampled at random, from the source given to [Context.WithRandomness] if there is one.
+func (c *Context) batchVerificationScalar(challenge func() fr.Element) (fr.Element, error) {
+	if c.deterministicVerification {
+		return challenge(), nil
+	}
+
+	if c.randomness != nil {
+		return c.randomness.sampleScalar()
+	}
+
+	var r fr.Element
+	if _, err := r.SetRandom(); err != nil {
+		return fr.Element{}, err
+	}
+	return r, nil
+}
+
+// randomScalarBytes is the number of bytes that are read to sample a scalar.
+//
+// Reading twice as many bytes as the modulus needs makes the bias from reducing them negligible.
+const randomScalarBytes = 2 * fr.Bytes
+
+// randomnessSource serializes reads from the reader given to [Context.WithRandomness], so that
+// readers which are not safe for concurrent use, such as a seeded math/rand.Rand, can be used from
+// several go-routines.
+type randomnessSource struct {
+	mu     sync.Mutex
+	reader io.Reader
+}
+
+// sampleScalar reads [randomScalarBytes] bytes from the reader and reduces them modulo the BLS modulus.
+func (s *randomnessSource) sampleScalar() (fr.Element, error) {
+	var buf [randomScalarBytes]byte
+
+	s.mu.Lock()
+	_, err := io.ReadFull(s.reader, buf[:])
+	s.mu.Unlock()
+	if err != nil {
+		return fr.Element{}, fmt.Errorf("%w: %w", ErrReadRandomness, err)
+	}
+
+	var r fr.Element
+	r.SetBytes(buf[:])
+	return r, nil
+}
+
 // u64ToByteArray16 converts a uint64 to a byte slice of length 16 in big endian format. This implies that the first 8 bytes of the result are always 0.
 func u64ToByteArray16(number uint64) []byte {
 	bytes := make([]byte, 16)
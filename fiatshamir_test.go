@@ -14,7 +14,7 @@ import (
 func TestComputeChallengeInterop(t *testing.T) {
 	blob := &Blob{}
 	commitment := SerializeG1Point(bls12381.G1Affine{})
-	challenge := computeChallenge(blob, KZGCommitment(commitment))
+	challenge := computeChallenge(blob[:], KZGCommitment(commitment))
 	expected := []byte{
 		0x04, 0xb7, 0xb2, 0x2a, 0xf6, 0x3d, 0x2b, 0x2f,
 		0x1c, 0xed, 0x8d, 0x55, 0x05, 0x60, 0xe5, 0xd1,
@@ -25,6 +25,53 @@ func TestComputeChallengeInterop(t *testing.T) {
 	require.Equal(t, expected, got[:])
 }
 
+// The expected values below were generated by hashing the transcript from the spec with
+// python's hashlib and reducing the digest modulo the BLS modulus.
+func TestComputeBlobBatchChallengeInterop(t *testing.T) {
+	commitment := KZGCommitment(SerializeG1Point(bls12381.G1Affine{}))
+	proof := KZGProof(SerializeG1Point(bls12381.G1Affine{}))
+
+	zs := make([]fr.Element, 2)
+	ys := make([]fr.Element, 2)
+	zs[0].SetUint64(1)
+	ys[0].SetUint64(2)
+	zs[1].SetUint64(3)
+	ys[1].SetUint64(4)
+
+	challenge := computeBlobBatchChallenge(4096, []KZGCommitment{commitment, commitment}, zs, ys, []KZGProof{proof, proof})
+	expected := []byte{
+		0x3d, 0x2c, 0xdc, 0xab, 0xfc, 0x3e, 0xa1, 0xb6,
+		0x43, 0x8d, 0x7d, 0x06, 0x73, 0x37, 0xf3, 0x2d,
+		0x7c, 0xb6, 0xef, 0x69, 0x54, 0xea, 0xb7, 0x45,
+		0x77, 0x94, 0x47, 0x0e, 0x88, 0x2e, 0x0b, 0x0b,
+	}
+	got := SerializeScalar(challenge)
+	require.Equal(t, expected, got[:])
+}
+
+func TestComputeCellBatchChallengeInterop(t *testing.T) {
+	commitment := KZGCommitment(SerializeG1Point(bls12381.G1Affine{}))
+	proof := KZGProof(SerializeG1Point(bls12381.G1Affine{}))
+	cell := make([]byte, MainnetParams.BytesPerCell())
+
+	challenge := computeCellBatchChallenge(
+		MainnetParams,
+		[]KZGCommitment{commitment},
+		[]uint64{0, 0},
+		[]uint64{0, 5},
+		[][]byte{cell, cell},
+		[]KZGProof{proof, proof},
+	)
+	expected := []byte{
+		0x05, 0x06, 0xd3, 0xaa, 0xe0, 0xc4, 0xb5, 0x59,
+		0x87, 0x69, 0x95, 0xf2, 0x78, 0xaa, 0x37, 0x9c,
+		0xf3, 0x83, 0x70, 0x7f, 0xb7, 0xda, 0xa3, 0x85,
+		0x7a, 0x4d, 0x21, 0x22, 0xcc, 0x8e, 0x29, 0x80,
+	}
+	got := SerializeScalar(challenge)
+	require.Equal(t, expected, got[:])
+}
+
 func TestTo16Bytes(t *testing.T) {
 	number := uint64(4096)
 	// Generated using the following python snippet:
@@ -51,7 +98,7 @@ func BenchmarkComputeChallenge(b *testing.B) {
 	b.ResetTimer()
 	b.ReportAllocs()
 	for n := 0; n < b.N; n++ {
-		challenge = computeChallenge(blob, KZGCommitment(commitment))
+		challenge = computeChallenge(blob[:], KZGCommitment(commitment))
 	}
 	have := SerializeScalar(challenge)
 	require.Equal(b, want, have[:])
@@ -0,0 +1,250 @@
+package goethkzg
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-eth-kzg/internal/kzg"
+	kzgmulti "github.com/crate-crypto/go-eth-kzg/internal/kzg_multi"
+)
+
+// FindInvalidBlobProofs returns the indices of the blobs whose proofs do not verify, in ascending order. It returns
+// an empty result if every proof verifies.
+//
+// This is meant to be called after [Context.VerifyBlobKZGProofBatch] has failed, to find out which of the blobs
+// were at fault. Blobs, commitments and proofs that cannot be deserialized are reported without doing any pairings.
+// The rest are verified together and the batch is split in half for as long as it fails, so that a few invalid
+// proofs in a large batch are found with far fewer pairings than verifying each of them on its own.
+//
+// The returned error is only set if the lengths of the inputs do not match, or if the search could not be completed.
+func (c *Context) FindInvalidBlobProofs(blobs []*Blob, commitments []KZGCommitment, proofs []KZGProof) ([]int, error) {
+	serBlobs := make([][]byte, len(blobs))
+	for i, blob := range blobs {
+		serBlobs[i] = blobBytes(blob)
+	}
+	return c.FindInvalidBlobProofsBytes(serBlobs, commitments, proofs)
+}
+
+// FindInvalidBlobProofsBytes is the same as [Context.FindInvalidBlobProofs] for blobs whose size is given by the
+// context parameters. See [Params].
+func (c *Context) FindInvalidBlobProofsBytes(blobs [][]byte, commitments []KZGCommitment, proofs []KZGProof) ([]int, error) {
+	batchSize := len(blobs)
+	if batchSize != len(commitments) || batchSize != len(proofs) {
+		return nil, newBatchError(-1, ErrorKindBatchLength, ErrBatchLengthCheck)
+	}
+
+	// 1. Deserialize everything once, and put aside the blobs that are malformed
+	//
+	var malformed []int
+	candidates := make([]int, 0, batchSize)
+	commitmentsG1 := make([]bls12381.G1Affine, batchSize)
+	openingProofs := make([]kzg.OpeningProof, batchSize)
+	for i := 0; i < batchSize; i++ {
+		commitment, openingProof, err := c.blobOpeningProof(i, blobs[i], commitments[i], proofs[i])
+		var batchErr *BatchError
+		if errors.As(err, &batchErr) {
+			malformed = append(malformed, i)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		commitmentsG1[i] = commitment
+		openingProofs[i] = openingProof
+		candidates = append(candidates, i)
+	}
+
+	// 2. Search for the proofs that do not verify
+	//
+	invalid, err := bisectInvalid(candidates, func(indices []int) (bool, error) {
+		subSerComms := make([]KZGCommitment, len(indices))
+		subProofs := make([]KZGProof, len(indices))
+		subCommitments := make([]bls12381.G1Affine, len(indices))
+		subOpeningProofs := make([]kzg.OpeningProof, len(indices))
+		for k, i := range indices {
+			subSerComms[k] = commitments[i]
+			subProofs[k] = proofs[i]
+			subCommitments[k] = commitmentsG1[i]
+			subOpeningProofs[k] = openingProofs[i]
+		}
+		return verificationPassed(c.batchVerifyBlobOpeningProofs(subSerComms, subProofs, subCommitments, subOpeningProofs))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeIndices(malformed, invalid), nil
+}
+
+// FindInvalidCellProofs returns the indices of the cells whose proofs do not verify, in ascending order. It returns
+// an empty result if every proof verifies.
+//
+// This is meant to be called after [Context.VerifyCellKZGProofBatch] has failed, to find out which of the cells
+// were at fault. Cells, cell indices, commitments and proofs that cannot be deserialized are reported without doing
+// any pairings. A commitment that cannot be deserialized is reported for every cell that refers to it. The rest are
+// verified together and the batch is split in half for as long as it fails, so that a few invalid proofs in a large
+// batch are found with far fewer pairings than verifying each of them on its own.
+//
+// The returned error is only set if the lengths of the inputs do not match, or if the search could not be completed.
+func (c *Context) FindInvalidCellProofs(commitments []KZGCommitment, cellIndices []uint64, cells []*Cell, proofs []KZGProof) ([]int, error) {
+	if err := c.checkFixedSizeTypes(); err != nil {
+		return nil, err
+	}
+	if err := c.checkEIP7594Verifier(); err != nil {
+		return nil, err
+	}
+
+	batchSize := len(commitments)
+	if batchSize != len(cellIndices) || batchSize != len(cells) || batchSize != len(proofs) {
+		return nil, newBatchError(-1, ErrorKindBatchLength, ErrBatchLengthCheck)
+	}
+	serCells := cellsBytes(cells)
+
+	// 1. Deserialize everything once, and put aside the cells that are malformed
+	//
+	rowCommitments, rowIndices := deduplicateKZGCommitments(commitments)
+	rowCommitmentsG1 := make([]bls12381.G1Affine, len(rowCommitments))
+	rowIsMalformed := make([]bool, len(rowCommitments))
+	for i, commitment := range rowCommitments {
+		commitmentG1, err := DeserializeKZGCommitment(commitment)
+		rowCommitmentsG1[i] = commitmentG1
+		rowIsMalformed[i] = err != nil
+	}
+
+	var malformed []int
+	candidates := make([]int, 0, batchSize)
+	proofsG1 := make([]bls12381.G1Affine, batchSize)
+	cosetsEvals := make([][]fr.Element, batchSize)
+	for i := 0; i < batchSize; i++ {
+		if rowIsMalformed[rowIndices[i]] || cellIndices[i] >= c.params.CellsPerExtBlob() {
+			malformed = append(malformed, i)
+			continue
+		}
+		proof, err := DeserializeKZGProof(proofs[i])
+		if err != nil {
+			malformed = append(malformed, i)
+			continue
+		}
+		cosetEvals, err := c.deserializeCell(serCells[i])
+		if err != nil {
+			malformed = append(malformed, i)
+			continue
+		}
+		proofsG1[i] = proof
+		cosetsEvals[i] = cosetEvals
+		candidates = append(candidates, i)
+	}
+
+	// 2. Search for the proofs that do not verify
+	//
+	invalid, err := bisectInvalid(candidates, func(indices []int) (bool, error) {
+		// Only the commitments that the cells refer to are passed on, in the order that they are first seen, which
+		// is the order that verifying these cells on their own would deduplicate them in.
+		localRows := make(map[uint64]uint64)
+		var subRowCommitments []KZGCommitment
+		var subRowCommitmentsG1 []bls12381.G1Affine
+		subRowIndices := make([]uint64, len(indices))
+		subCellIndices := make([]uint64, len(indices))
+		subCells := make([][]byte, len(indices))
+		subProofs := make([]KZGProof, len(indices))
+		subProofsG1 := make([]bls12381.G1Affine, len(indices))
+		subCosetsEvals := make([][]fr.Element, len(indices))
+		for k, i := range indices {
+			row := rowIndices[i]
+			localRow, ok := localRows[row]
+			if !ok {
+				localRow = uint64(len(subRowCommitments))
+				localRows[row] = localRow
+				subRowCommitments = append(subRowCommitments, rowCommitments[row])
+				subRowCommitmentsG1 = append(subRowCommitmentsG1, rowCommitmentsG1[row])
+			}
+			subRowIndices[k] = localRow
+			subCellIndices[k] = cellIndices[i]
+			subCells[k] = serCells[i]
+			subProofs[k] = proofs[i]
+			subProofsG1[k] = proofsG1[i]
+			// The evaluations are modified in-place by the verifier, so it is given a copy
+			subCosetsEvals[k] = slices.Clone(cosetsEvals[i])
+		}
+
+		r, err := c.batchVerificationScalar(func() fr.Element {
+			return computeCellBatchChallenge(c.params, subRowCommitments, subRowIndices, subCellIndices, subCells, subProofs)
+		})
+		if err != nil {
+			return false, err
+		}
+		return verificationPassed(kzgmulti.VerifyMultiPointKZGProofBatch(context.Background(), subRowCommitmentsG1, subRowIndices, subCellIndices, subProofsG1, subCosetsEvals, r, c.openKey7594))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeIndices(malformed, invalid), nil
+}
+
+// bisectInvalid returns the elements of `indices` for which `verify` fails, in the order that they appear.
+//
+// `verify` checks a sub-batch of the indices and returns false if it does not pass. A failing batch is split in half
+// until the failing elements are isolated. When the first half of a failing batch passes, the second half is known to
+// fail and is split without being verified again.
+func bisectInvalid(indices []int, verify func(indices []int) (bool, error)) ([]int, error) {
+	var invalid []int
+
+	// search appends the failing elements of `indices` to `invalid` and returns true if it found any. If
+	// `knownInvalid` is set, the batch is not verified again before it is split.
+	var search func(indices []int, knownInvalid bool) (bool, error)
+	search = func(indices []int, knownInvalid bool) (bool, error) {
+		if len(indices) == 0 {
+			return false, nil
+		}
+		if !knownInvalid {
+			passed, err := verify(indices)
+			if err != nil {
+				return false, err
+			}
+			if passed {
+				return false, nil
+			}
+		}
+		if len(indices) == 1 {
+			invalid = append(invalid, indices[0])
+			return true, nil
+		}
+
+		mid := len(indices) / 2
+		foundInFirstHalf, err := search(indices[:mid], false)
+		if err != nil {
+			return false, err
+		}
+		if _, err := search(indices[mid:], !foundInFirstHalf); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if _, err := search(indices, false); err != nil {
+		return nil, err
+	}
+	return invalid, nil
+}
+
+// verificationPassed turns the result of a verifier into whether the proofs verified. Errors other than a failed
+// pairing check are returned.
+func verificationPassed(err error) (bool, error) {
+	if errors.Is(err, ErrVerifyOpeningProof) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// mergeIndices merges two sorted lists of indices into one.
+func mergeIndices(a, b []int) []int {
+	merged := make([]int, 0, len(a)+len(b))
+	merged = append(merged, a...)
+	merged = append(merged, b...)
+	slices.Sort(merged)
+	return merged
+}
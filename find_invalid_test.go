@@ -0,0 +1,58 @@
+package goethkzg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBisectInvalid(t *testing.T) {
+	const batchSize = 64
+
+	testCases := [][]int{
+		{},
+		{0},
+		{63},
+		{5, 6},
+		{0, 17, 40, 63},
+	}
+	for _, expected := range testCases {
+		isInvalid := make(map[int]bool)
+		for _, index := range expected {
+			isInvalid[index] = true
+		}
+
+		indices := make([]int, batchSize)
+		for i := range indices {
+			indices[i] = i
+		}
+
+		numCalls := 0
+		invalid, err := bisectInvalid(indices, func(indices []int) (bool, error) {
+			numCalls++
+			for _, index := range indices {
+				if isInvalid[index] {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, append([]int{}, invalid...))
+
+		// Each invalid element costs at most two checks at each level of the search, plus the check of
+		// the whole batch
+		maxCalls := 1 + 2*len(expected)*log2(batchSize)
+		require.LessOrEqual(t, numCalls, maxCalls)
+		require.Less(t, numCalls, batchSize)
+	}
+}
+
+func log2(n int) int {
+	log := 0
+	for n > 1 {
+		n >>= 1
+		log++
+	}
+	return log
+}
@@ -0,0 +1,120 @@
+package goethkzg
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// MainnetSetupFingerprint is the fingerprint of the trusted setup from the Ethereum KZG ceremony, which is the
+// setup used by [NewContext4096Secure].
+//
+// See [ComputeSetupFingerprint] for how it is computed.
+const MainnetSetupFingerprint = "0x6b620e587ace783c6729b78e52e5eff0d2e919a908c37b38e45d5cbe5ff56303"
+
+// DomSepSetupFingerprint is a Domain Separator for the hash that is used to fingerprint a trusted setup.
+const DomSepSetupFingerprint = "GOETHKZG_SETUP_FINGERPRINT_V1"
+
+// Fingerprint identifies a trusted setup. See [ComputeSetupFingerprint].
+type Fingerprint [sha256.Size]byte
+
+// String returns the fingerprint as a hex-string with the 0x prefix.
+func (f Fingerprint) String() string {
+	return "0x" + hex.EncodeToString(f[:])
+}
+
+// ParseFingerprint parses a fingerprint from a hex-string with the 0x prefix, such as [MainnetSetupFingerprint].
+func ParseFingerprint(hexString string) (Fingerprint, error) {
+	byts, err := decodeHexStr(hexString)
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("%w: %w", ErrMalformedFingerprint, err)
+	}
+	if len(byts) != len(Fingerprint{}) {
+		return Fingerprint{}, fmt.Errorf("%w: expected %d bytes, found %d", ErrMalformedFingerprint, len(Fingerprint{}), len(byts))
+	}
+	return Fingerprint(byts), nil
+}
+
+// ComputeSetupFingerprint computes a fingerprint of the trusted setup, which can be used to check that two processes
+// are using the same setup.
+//
+// The fingerprint is the SHA-256 hash of [DomSepSetupFingerprint], followed by the number of G1 and G2 points as
+// 8 byte big-endian integers and the compressed points themselves: first the G1 points in lagrange form, then the G1
+// points in monomial form and finally the G2 points. Since it only depends on the points, the fingerprint is the
+// same whichever format the setup was loaded from.
+func ComputeSetupFingerprint(trustedSetup *JSONTrustedSetup) (Fingerprint, error) {
+	h := sha256.New()
+	h.Write([]byte(DomSepSetupFingerprint))
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(trustedSetup.SetupG1Lagrange))))
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(trustedSetup.SetupG2))))
+
+	hashPoints := func(name string, points []string) error {
+		for i, point := range points {
+			byts, err := decodeHexStr(point)
+			if err != nil {
+				return fmt.Errorf("%w: %s point %d: %w", ErrMalformedTrustedSetup, name, i, err)
+			}
+			h.Write(byts)
+		}
+		return nil
+	}
+	if err := hashPoints("g1_lagrange", trustedSetup.SetupG1Lagrange); err != nil {
+		return Fingerprint{}, err
+	}
+	if err := hashPoints("g1_monomial", trustedSetup.SetupG1Monomial); err != nil {
+		return Fingerprint{}, err
+	}
+	if err := hashPoints("g2_monomial", trustedSetup.SetupG2); err != nil {
+		return Fingerprint{}, err
+	}
+
+	return Fingerprint(h.Sum(nil)), nil
+}
+
+// SetupFingerprint returns the fingerprint of the trusted setup that the context was created from.
+//
+// See [ComputeSetupFingerprint].
+func (ctx *Context) SetupFingerprint() Fingerprint {
+	return ctx.setupFingerprint
+}
+
+// ContextOption configures the creation of a [Context] by [NewContext4096].
+type ContextOption func(*contextOptions)
+
+// contextOptions holds the settings from the [ContextOption]s that were passed to a constructor.
+type contextOptions struct {
+	// expectedFingerprint is the fingerprint that the trusted setup must have, or an empty string to accept any setup.
+	expectedFingerprint string
+}
+
+// WithSetupFingerprint makes the constructor return [ErrSetupFingerprintMismatch] instead of a context if the
+// fingerprint of the trusted setup is not `expected`, which is a hex-string with the 0x prefix.
+//
+// Pass [MainnetSetupFingerprint] to make sure that a process is not accidentally started with a devnet setup.
+func WithSetupFingerprint(expected string) ContextOption {
+	return func(opts *contextOptions) {
+		opts.expectedFingerprint = expected
+	}
+}
+
+// checkSetupFingerprint computes the fingerprint of the trusted setup and checks it against the one that is expected
+// by `opts`, if any.
+func checkSetupFingerprint(trustedSetup *JSONTrustedSetup, opts contextOptions) (Fingerprint, error) {
+	fingerprint, err := ComputeSetupFingerprint(trustedSetup)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	if opts.expectedFingerprint == "" {
+		return fingerprint, nil
+	}
+
+	expected, err := ParseFingerprint(opts.expectedFingerprint)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	if fingerprint != expected {
+		return Fingerprint{}, fmt.Errorf("%w: expected %s, found %s", ErrSetupFingerprintMismatch, expected, fingerprint)
+	}
+	return fingerprint, nil
+}
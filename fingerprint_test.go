@@ -0,0 +1,82 @@
+package goethkzg_test
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/stretchr/testify/require"
+)
+
+func loadMainnetSetup(t *testing.T) *goethkzg.JSONTrustedSetup {
+	t.Helper()
+	file, err := os.Open("trusted_setup.json")
+	require.NoError(t, err)
+	defer file.Close()
+	trustedSetup, err := goethkzg.LoadTrustedSetupJSON(file)
+	require.NoError(t, err)
+	return trustedSetup
+}
+
+func TestSetupFingerprint(t *testing.T) {
+	require.Equal(t, goethkzg.MainnetSetupFingerprint, ctx.SetupFingerprint().String())
+
+	trustedSetup := loadMainnetSetup(t)
+	fingerprint, err := goethkzg.ComputeSetupFingerprint(trustedSetup)
+	require.NoError(t, err)
+	require.Equal(t, ctx.SetupFingerprint(), fingerprint)
+
+	parsed, err := goethkzg.ParseFingerprint(goethkzg.MainnetSetupFingerprint)
+	require.NoError(t, err)
+	require.Equal(t, fingerprint, parsed)
+
+	// The fingerprint only depends on the points, not on the format that they were loaded from
+	var text bytes.Buffer
+	require.NoError(t, goethkzg.WriteTrustedSetupText(&text, trustedSetup))
+	fromText, err := goethkzg.LoadTrustedSetupText(&text)
+	require.NoError(t, err)
+	fingerprintFromText, err := goethkzg.ComputeSetupFingerprint(fromText)
+	require.NoError(t, err)
+	require.Equal(t, fingerprint, fingerprintFromText)
+
+	// Any change to the points changes the fingerprint
+	swapped := *trustedSetup
+	swapped.SetupG1Lagrange = slices.Clone(trustedSetup.SetupG1Lagrange)
+	swapped.SetupG1Lagrange[0], swapped.SetupG1Lagrange[1] = swapped.SetupG1Lagrange[1], swapped.SetupG1Lagrange[0]
+	swappedFingerprint, err := goethkzg.ComputeSetupFingerprint(&swapped)
+	require.NoError(t, err)
+	require.NotEqual(t, fingerprint, swappedFingerprint)
+
+	devnetSetup := newInsecureSetup(1337, int(smallParams.FieldElementsPerBlob), int(smallParams.FieldElementsPerCell)+1)
+	devnetCtx, err := goethkzg.NewContextWithParams(devnetSetup, smallParams)
+	require.NoError(t, err)
+	require.NotEqual(t, fingerprint, devnetCtx.SetupFingerprint())
+
+	// The fingerprint is kept when the context is serialized
+	serialized, err := devnetCtx.MarshalBinary()
+	require.NoError(t, err)
+	loaded, err := goethkzg.UnmarshalContext(serialized)
+	require.NoError(t, err)
+	require.Equal(t, devnetCtx.SetupFingerprint(), loaded.SetupFingerprint())
+}
+
+func TestNewContext4096WithSetupFingerprint(t *testing.T) {
+	trustedSetup := loadMainnetSetup(t)
+
+	checkedCtx, err := goethkzg.NewContext4096(trustedSetup, goethkzg.WithSetupFingerprint(goethkzg.MainnetSetupFingerprint))
+	require.NoError(t, err)
+	require.Equal(t, goethkzg.MainnetSetupFingerprint, checkedCtx.SetupFingerprint().String())
+
+	swapped := *trustedSetup
+	swapped.SetupG1Lagrange = slices.Clone(trustedSetup.SetupG1Lagrange)
+	swapped.SetupG1Lagrange[0], swapped.SetupG1Lagrange[1] = swapped.SetupG1Lagrange[1], swapped.SetupG1Lagrange[0]
+	_, err = goethkzg.NewContext4096(&swapped, goethkzg.WithSetupFingerprint(goethkzg.MainnetSetupFingerprint))
+	require.ErrorIs(t, err, goethkzg.ErrSetupFingerprintMismatch)
+
+	_, err = goethkzg.NewContext4096(trustedSetup, goethkzg.WithSetupFingerprint("0x1234"))
+	require.ErrorIs(t, err, goethkzg.ErrMalformedFingerprint)
+	_, err = goethkzg.NewContext4096(trustedSetup, goethkzg.WithSetupFingerprint(goethkzg.MainnetSetupFingerprint[2:]))
+	require.ErrorIs(t, err, goethkzg.ErrMalformedFingerprint)
+}
@@ -0,0 +1,33 @@
+package goethkzg
+
+import (
+	"testing"
+)
+
+func FuzzDeserializeCell(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(make([]byte, BytesPerCell))
+	f.Add(append(make([]byte, BytesPerCell-SerializedScalarSize), BlsModulus[:]...))
+
+	// Only the parameters are used to deserialize a cell
+	ctx := &Context{params: MainnetParams}
+	f.Fuzz(func(t *testing.T, cell []byte) {
+		evals, err := ctx.deserializeCell(cell)
+		if err != nil {
+			return
+		}
+		if len(cell) != BytesPerCell {
+			t.Fatalf("a cell of %d bytes was accepted", len(cell))
+		}
+
+		// A cell is valid exactly when all of its field elements are, and it can be serialized back
+		serCell := make([]byte, 0, len(cell))
+		for i := range evals {
+			serScalar := SerializeScalar(evals[i])
+			serCell = append(serCell, serScalar[:]...)
+		}
+		if string(serCell) != string(cell) {
+			t.Fatalf("cell does not round trip")
+		}
+	})
+}
@@ -0,0 +1,370 @@
+package goethkzg_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// The fuzz targets check that none of the methods that handle untrusted input panic, since a panic after startup is
+// a DoS vector, along with invariants such as "a proof that was just computed verifies".
+//
+// Running `go test` runs every target on its seed corpus, which is drawn from the consensus-specs vectors under
+// tests/. To fuzz a target, run for example:
+//
+//	go test -run=^$ -fuzz=FuzzVerifyBlobKZGProof -fuzztime=10m
+
+// addSpecSeeds calls `add` with the input of each consensus-specs test vector that matches `pattern`.
+func addSpecSeeds(f *testing.F, pattern string, add func(input map[string]any)) {
+	f.Helper()
+	paths, err := filepath.Glob(pattern)
+	require.NoError(f, err)
+	require.NotEmpty(f, paths)
+
+	for _, path := range paths {
+		testFile, err := os.Open(path)
+		require.NoError(f, err)
+		var test struct {
+			Input map[string]any `yaml:"input"`
+		}
+		err = yaml.NewDecoder(testFile).Decode(&test)
+		testFile.Close()
+		require.NoError(f, err)
+		add(test.Input)
+	}
+}
+
+// specBytes decodes a hex-string from a test vector.
+func specBytes(f *testing.F, value any) []byte {
+	f.Helper()
+	byts, err := hex.DecodeString(strings.TrimPrefix(value.(string), "0x"))
+	require.NoError(f, err)
+	return byts
+}
+
+// specBytesList decodes a list of hex-strings from a test vector.
+func specBytesList(f *testing.F, value any) [][]byte {
+	f.Helper()
+	var list [][]byte
+	for _, item := range value.([]any) {
+		list = append(list, specBytes(f, item))
+	}
+	return list
+}
+
+// blobFromSeed expands `seed` into a valid blob, so that the fuzzer can explore blobs without producing
+// non-canonical field elements.
+func blobFromSeed(seed []byte) *goethkzg.Blob {
+	var blob goethkzg.Blob
+	for i := 0; i < goethkzg.ScalarsPerBlob; i++ {
+		h := sha256.Sum256(binary.BigEndian.AppendUint32(seed, uint32(i)))
+		var element fr.Element
+		element.SetBytes(h[:])
+		serScalar := goethkzg.SerializeScalar(element)
+		copy(blob[i*goethkzg.SerializedScalarSize:], serScalar[:])
+	}
+	return &blob
+}
+
+// scalarFromSeed reduces `seed` to a canonical field element.
+func scalarFromSeed(seed []byte) goethkzg.Scalar {
+	var element fr.Element
+	element.SetBigInt(new(big.Int).SetBytes(seed))
+	return goethkzg.SerializeScalar(element)
+}
+
+func FuzzDeserializeBlob(f *testing.F) {
+	addSpecSeeds(f, blobToKZGCommitmentTests, func(input map[string]any) {
+		f.Add(specBytes(f, input["blob"]))
+	})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) != goethkzg.ScalarsPerBlob*goethkzg.SerializedScalarSize {
+			return
+		}
+		blob := goethkzg.Blob(data)
+		poly, err := goethkzg.DeserializeBlob(&blob)
+
+		// A blob is valid exactly when all of its field elements are
+		canonical := true
+		for i := 0; i < goethkzg.ScalarsPerBlob; i++ {
+			scalar := goethkzg.Scalar(data[i*goethkzg.SerializedScalarSize:])
+			if _, err := goethkzg.DeserializeScalar(scalar); err != nil {
+				canonical = false
+				break
+			}
+		}
+		require.Equal(t, canonical, err == nil)
+
+		if err == nil {
+			require.Equal(t, blob, *goethkzg.SerializePoly(poly))
+		}
+	})
+}
+
+func FuzzDeserializeKZGCommitment(f *testing.F) {
+	addSpecSeeds(f, verifyKZGProofTests, func(input map[string]any) {
+		f.Add(specBytes(f, input["commitment"]))
+		f.Add(specBytes(f, input["proof"]))
+	})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) != goethkzg.CompressedG1Size {
+			return
+		}
+		point, err := goethkzg.DeserializeKZGCommitment(goethkzg.KZGCommitment(data))
+		if err != nil {
+			return
+		}
+
+		// The compressed encoding of a point is unique
+		require.True(t, point.IsInSubGroup())
+		serPoint := goethkzg.SerializeG1Point(point)
+		require.Equal(t, data, serPoint[:])
+	})
+}
+
+func FuzzDeserializeScalar(f *testing.F) {
+	addSpecSeeds(f, verifyKZGProofTests, func(input map[string]any) {
+		f.Add(specBytes(f, input["z"]))
+		f.Add(specBytes(f, input["y"]))
+	})
+
+	modulus := new(big.Int).SetBytes(goethkzg.BlsModulus[:])
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) != goethkzg.SerializedScalarSize {
+			return
+		}
+		element, err := goethkzg.DeserializeScalar(goethkzg.Scalar(data))
+
+		// A scalar is canonical exactly when it is less than the modulus, as a big-endian integer
+		require.Equal(t, new(big.Int).SetBytes(data).Cmp(modulus) < 0, err == nil)
+		if err == nil {
+			serScalar := goethkzg.SerializeScalar(element)
+			require.Equal(t, data, serScalar[:])
+		}
+	})
+}
+
+func FuzzVerifyKZGProof(f *testing.F) {
+	addSpecSeeds(f, verifyKZGProofTests, func(input map[string]any) {
+		f.Add(specBytes(f, input["commitment"]), specBytes(f, input["z"]), specBytes(f, input["y"]), specBytes(f, input["proof"]))
+	})
+
+	f.Fuzz(func(t *testing.T, commitment, z, y, proof []byte) {
+		if len(commitment) != goethkzg.CompressedG1Size || len(z) != goethkzg.SerializedScalarSize ||
+			len(y) != goethkzg.SerializedScalarSize || len(proof) != goethkzg.CompressedG1Size {
+			return
+		}
+		_ = ctx.VerifyKZGProof(goethkzg.KZGCommitment(commitment), goethkzg.Scalar(z), goethkzg.Scalar(y), goethkzg.KZGProof(proof))
+	})
+}
+
+func FuzzVerifyBlobKZGProof(f *testing.F) {
+	addSpecSeeds(f, verifyBlobKZGProofTests, func(input map[string]any) {
+		f.Add(specBytes(f, input["blob"]), specBytes(f, input["commitment"]), specBytes(f, input["proof"]))
+	})
+
+	f.Fuzz(func(t *testing.T, blob, commitment, proof []byte) {
+		if len(commitment) != goethkzg.CompressedG1Size || len(proof) != goethkzg.CompressedG1Size {
+			return
+		}
+		_ = ctx.VerifyBlobKZGProofBytes(blob, goethkzg.KZGCommitment(commitment), goethkzg.KZGProof(proof))
+	})
+}
+
+func FuzzVerifyBlobKZGProofBatch(f *testing.F) {
+	addSpecSeeds(f, verifyBlobKZGProofBatchTests, func(input map[string]any) {
+		blobs := specBytesList(f, input["blobs"])
+		commitments := specBytesList(f, input["commitments"])
+		proofs := specBytesList(f, input["proofs"])
+		f.Add(bytes.Join(blobs, nil), bytes.Join(commitments, nil), bytes.Join(proofs, nil))
+	})
+
+	f.Fuzz(func(t *testing.T, blobsData, commitmentsData, proofsData []byte) {
+		blobs := splitBytes(blobsData, goethkzg.ScalarsPerBlob*goethkzg.SerializedScalarSize)
+		commitments := splitBytes(commitmentsData, goethkzg.CompressedG1Size)
+		proofs := splitBytes(proofsData, goethkzg.CompressedG1Size)
+		serCommitments := make([]goethkzg.KZGCommitment, len(commitments))
+		for i := range commitments {
+			serCommitments[i] = goethkzg.KZGCommitment(commitments[i])
+		}
+		serProofs := make([]goethkzg.KZGProof, len(proofs))
+		for i := range proofs {
+			serProofs[i] = goethkzg.KZGProof(proofs[i])
+		}
+
+		// The batch verifies exactly when each of its proofs does
+		batchErr := ctx.VerifyBlobKZGProofBatchBytes(blobs, serCommitments, serProofs)
+		if len(blobs) != len(commitments) || len(blobs) != len(proofs) {
+			require.ErrorIs(t, batchErr, goethkzg.ErrBatchLengthCheck)
+			return
+		}
+		allValid := true
+		for i := range blobs {
+			if ctx.VerifyBlobKZGProofBytes(blobs[i], serCommitments[i], serProofs[i]) != nil {
+				allValid = false
+			}
+		}
+		require.Equal(t, allValid, batchErr == nil)
+	})
+}
+
+func FuzzComputeAndVerifyKZGProofs(f *testing.F) {
+	f.Add([]byte{}, []byte{})
+	f.Add([]byte("blob"), []byte{0xff, 0xff})
+
+	f.Fuzz(func(t *testing.T, blobSeed, zSeed []byte) {
+		blob := blobFromSeed(blobSeed)
+		z := scalarFromSeed(zSeed)
+
+		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+
+		blobProof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+		require.NoError(t, err)
+		require.NoError(t, ctx.VerifyBlobKZGProof(blob, commitment, blobProof))
+
+		proof, y, err := ctx.ComputeKZGProof(blob, z, NumGoRoutines)
+		require.NoError(t, err)
+		require.NoError(t, ctx.VerifyKZGProof(commitment, z, y, proof))
+	})
+}
+
+func FuzzVerifyCellKZGProofBatch(f *testing.F) {
+	addSpecSeeds(f, verifyCellKZGProofBatchTests, func(input map[string]any) {
+		commitments := specBytesList(f, input["commitments"])
+		cells := specBytesList(f, input["cells"])
+		proofs := specBytesList(f, input["proofs"])
+		var cellIndices []byte
+		for _, index := range input["cell_indices"].([]any) {
+			cellIndices = binary.BigEndian.AppendUint64(cellIndices, uint64(index.(int)))
+		}
+		f.Add(bytes.Join(commitments, nil), cellIndices, bytes.Join(cells, nil), bytes.Join(proofs, nil))
+	})
+
+	f.Fuzz(func(t *testing.T, commitmentsData, cellIndicesData, cellsData, proofsData []byte) {
+		commitments := make([]goethkzg.KZGCommitment, 0, len(commitmentsData)/goethkzg.CompressedG1Size)
+		for _, commitment := range splitBytes(commitmentsData, goethkzg.CompressedG1Size) {
+			commitments = append(commitments, goethkzg.KZGCommitment(commitment))
+		}
+		var cellIndices []uint64
+		for _, index := range splitBytes(cellIndicesData, 8) {
+			cellIndices = append(cellIndices, binary.BigEndian.Uint64(index))
+		}
+		var cells []*goethkzg.Cell
+		for _, cell := range splitBytes(cellsData, goethkzg.BytesPerCell) {
+			cells = append(cells, (*goethkzg.Cell)(cell))
+		}
+		var proofs []goethkzg.KZGProof
+		for _, proof := range splitBytes(proofsData, goethkzg.CompressedG1Size) {
+			proofs = append(proofs, goethkzg.KZGProof(proof))
+		}
+
+		err := ctx.VerifyCellKZGProofBatch(commitments, cellIndices, cells, proofs)
+		if len(cells) == 0 && len(commitments) == 0 && len(cellIndices) == 0 && len(proofs) == 0 {
+			require.NoError(t, err)
+		}
+	})
+}
+
+func FuzzRecoverCells(f *testing.F) {
+	addSpecSeeds(f, recoverCellsAndKZGProofsTests, func(input map[string]any) {
+		var cellIndices []byte
+		for _, index := range input["cell_indices"].([]any) {
+			cellIndices = binary.BigEndian.AppendUint64(cellIndices, uint64(index.(int)))
+		}
+		f.Add(cellIndices, bytes.Join(specBytesList(f, input["cells"]), nil))
+	})
+
+	f.Fuzz(func(t *testing.T, cellIndicesData, cellsData []byte) {
+		var cellIndices []uint64
+		for _, index := range splitBytes(cellIndicesData, 8) {
+			cellIndices = append(cellIndices, binary.BigEndian.Uint64(index))
+		}
+		var cells []*goethkzg.Cell
+		for _, cell := range splitBytes(cellsData, goethkzg.BytesPerCell) {
+			cells = append(cells, (*goethkzg.Cell)(cell))
+		}
+
+		_, errRecover := ctx.RecoverCells(cellIndices, cells, NumGoRoutines)
+		_, _, errRecoverAndProve := ctx.RecoverCellsAndComputeKZGProofs(cellIndices, cells, NumGoRoutines)
+		require.Equal(t, errRecover == nil, errRecoverAndProve == nil)
+	})
+}
+
+func FuzzRecoverCellsAndComputeKZGProofs(f *testing.F) {
+	f.Add([]byte{}, int64(0))
+	f.Add([]byte("blob"), int64(1))
+
+	f.Fuzz(func(t *testing.T, blobSeed []byte, subsetSeed int64) {
+		blob := blobFromSeed(blobSeed)
+		cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+		require.NoError(t, err)
+
+		// Keep any half of the cells, which is the minimum that recovery needs
+		subset := rand.New(rand.NewSource(subsetSeed)).Perm(goethkzg.CellsPerExtBlob)[:goethkzg.CellsPerExtBlob/2]
+		keptIndices, keptCells, keptProofs := keepCells(subset, cells[:], proofs[:])
+
+		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		commitments := make([]goethkzg.KZGCommitment, len(keptCells))
+		for i := range commitments {
+			commitments[i] = commitment
+		}
+		require.NoError(t, ctx.VerifyCellKZGProofBatch(commitments, keptIndices, keptCells, keptProofs))
+
+		recoveredCells, err := ctx.RecoverCells(keptIndices, keptCells, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, cells, recoveredCells)
+
+		recoveredCells, recoveredProofs, err := ctx.RecoverCellsAndComputeKZGProofs(keptIndices, keptCells, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, cells, recoveredCells)
+		require.Equal(t, proofs, recoveredProofs)
+
+		// One cell fewer is not enough
+		_, err = ctx.RecoverCells(keptIndices[1:], keptCells[1:], NumGoRoutines)
+		require.ErrorIs(t, err, goethkzg.ErrNotEnoughCellsForReconstruction)
+	})
+}
+
+// keepCells returns the cells at the given indices and their proofs, in ascending order of their indices.
+func keepCells(indices []int, cells []*goethkzg.Cell, proofs []goethkzg.KZGProof) ([]uint64, []*goethkzg.Cell, []goethkzg.KZGProof) {
+	isKept := make([]bool, len(cells))
+	for _, i := range indices {
+		isKept[i] = true
+	}
+	var keptIndices []uint64
+	var keptCells []*goethkzg.Cell
+	var keptProofs []goethkzg.KZGProof
+	for i, kept := range isKept {
+		if kept {
+			keptIndices = append(keptIndices, uint64(i))
+			keptCells = append(keptCells, cells[i])
+			keptProofs = append(keptProofs, proofs[i])
+		}
+	}
+	return keptIndices, keptCells, keptProofs
+}
+
+// splitBytes splits `data` into chunks of `size` bytes. A shorter last chunk is dropped.
+func splitBytes(data []byte, size int) [][]byte {
+	var chunks [][]byte
+	for start := 0; start+size <= len(data); start += size {
+		chunks = append(chunks, data[start:start+size])
+	}
+	return chunks
+}
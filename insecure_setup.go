@@ -0,0 +1,88 @@
+// Methods in this file should not be used in production.
+// They create trusted setups from a secret that is known to the caller,
+// which can then be used to forge proofs for any blob.
+
+package goethkzg
+
+import (
+	"encoding/hex"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-eth-kzg/internal/domain"
+	"github.com/crate-crypto/go-eth-kzg/internal/kzg"
+)
+
+// NewInsecureContextForTesting creates a context for blobs and cells whose sizes are given by `params`, from a
+// trusted setup that is generated with `secret`. The context can be used with every method, just like one created by
+// [NewContextWithParams].
+//
+// This is meant for unit tests and local devnets, which would otherwise need a trusted setup file for their
+// parameters. Anyone who knows the secret can create proofs that verify for any value, so this must never be used in
+// production.
+func NewInsecureContextForTesting(secret *big.Int, params Params) (*Context, error) {
+	trustedSetup, err := NewInsecureTrustedSetupForTesting(secret, params)
+	if err != nil {
+		return nil, err
+	}
+	return NewContextWithParams(trustedSetup, params)
+}
+
+// NewInsecureTrustedSetupForTesting generates a trusted setup with `secret` that has the points needed to create a
+// context for `params`.
+//
+// The setup has `FieldElementsPerBlob` G1 points in monomial form and in lagrange form, and `FieldElementsPerCell + 1`
+// G2 points, which is what the cell verifier needs. Like `trusted_setup.json`, the lagrange points are in natural
+// order and are bit-reversed when a context is created from them.
+//
+// See [NewInsecureContextForTesting] for why this must never be used in production.
+func NewInsecureTrustedSetupForTesting(secret *big.Int, params Params) (*JSONTrustedSetup, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, ErrInvalidSecret
+	}
+
+	// A secret of zero or one would make every point the same
+	var alpha fr.Element
+	alpha.SetBigInt(secret)
+	if alpha.IsZero() || alpha.IsOne() {
+		return nil, ErrInvalidSecret
+	}
+
+	numG1 := int(params.FieldElementsPerBlob)
+	numG2 := int(params.FieldElementsPerCell) + 1
+
+	// 1. Compute the monomial points {alpha^i * G} and {alpha^i * H}
+	//
+	monomialG1, monomialG2 := kzg.InsecurePowersOfSecret(uint64(numG1), uint64(numG2), secret)
+
+	// 2. Convert the monomial points to lagrange form
+	//
+	// The points are left in natural order, which is the order that the trusted setup is stored in.
+	lagrangeG1 := make([]bls12381.G1Affine, numG1)
+	copy(lagrangeG1, monomialG1)
+	domain.NewDomain(params.FieldElementsPerBlob).IfftG1(lagrangeG1)
+
+	// 3. Encode the points in the same way as the trusted setup file
+	//
+	trustedSetup := &JSONTrustedSetup{
+		SetupG2:         make([]G2CompressedHexStr, numG2),
+		SetupG1Lagrange: make([]G1CompressedHexStr, numG1),
+		SetupG1Monomial: make([]G1CompressedHexStr, numG1),
+	}
+	for i := 0; i < numG1; i++ {
+		monomial := monomialG1[i].Bytes()
+		lagrange := lagrangeG1[i].Bytes()
+		trustedSetup.SetupG1Monomial[i] = "0x" + hex.EncodeToString(monomial[:])
+		trustedSetup.SetupG1Lagrange[i] = "0x" + hex.EncodeToString(lagrange[:])
+	}
+	for i := 0; i < numG2; i++ {
+		point := monomialG2[i].Bytes()
+		trustedSetup.SetupG2[i] = "0x" + hex.EncodeToString(point[:])
+	}
+
+	return trustedSetup, nil
+}
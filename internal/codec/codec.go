@@ -0,0 +1,260 @@
+package codec
+
+import (
+	"encoding/binary"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// This package implements a simple binary encoding for the precomputed
+// tables that the library builds on startup.
+//
+// Group elements are written as their uncompressed affine coordinates, so
+// decoding them does not need a square root or a subgroup check. This is what makes
+// loading a snapshot much cheaper than processing the trusted setup. The flip side is
+// that the decoder trusts its input; callers are expected to authenticate
+// the data, for example with a checksum, before decoding it.
+//
+// All integers and field elements are encoded in big-endian.
+
+// G1Size is the number of bytes used to encode a G1 point.
+const G1Size = 2 * fp.Bytes
+
+// G2Size is the number of bytes used to encode a G2 point.
+const G2Size = 4 * fp.Bytes
+
+// Encoder appends encoded values to an in-memory buffer.
+type Encoder struct {
+	buf []byte
+}
+
+// NewEncoder creates an Encoder whose buffer has an initial capacity of `sizeHint` bytes.
+func NewEncoder(sizeHint int) *Encoder {
+	return &Encoder{buf: make([]byte, 0, sizeHint)}
+}
+
+// Bytes returns the encoded data.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// PutBytes appends raw bytes, without a length prefix.
+func (e *Encoder) PutBytes(b []byte) {
+	e.buf = append(e.buf, b...)
+}
+
+// PutUint64 appends a uint64.
+func (e *Encoder) PutUint64(v uint64) {
+	e.buf = binary.BigEndian.AppendUint64(e.buf, v)
+}
+
+// PutFr appends a scalar field element.
+func (e *Encoder) PutFr(v *fr.Element) {
+	b := v.Bytes()
+	e.buf = append(e.buf, b[:]...)
+}
+
+// PutFrs appends a length-prefixed slice of scalar field elements.
+func (e *Encoder) PutFrs(v []fr.Element) {
+	e.PutUint64(uint64(len(v)))
+	for i := range v {
+		e.PutFr(&v[i])
+	}
+}
+
+// PutG1 appends a G1 point.
+func (e *Encoder) PutG1(p *bls12381.G1Affine) {
+	e.putFp(&p.X)
+	e.putFp(&p.Y)
+}
+
+// PutG1s appends a length-prefixed slice of G1 points.
+func (e *Encoder) PutG1s(points []bls12381.G1Affine) {
+	e.PutUint64(uint64(len(points)))
+	for i := range points {
+		e.PutG1(&points[i])
+	}
+}
+
+// PutG2 appends a G2 point.
+func (e *Encoder) PutG2(p *bls12381.G2Affine) {
+	e.putFp(&p.X.A0)
+	e.putFp(&p.X.A1)
+	e.putFp(&p.Y.A0)
+	e.putFp(&p.Y.A1)
+}
+
+// PutG2s appends a length-prefixed slice of G2 points.
+func (e *Encoder) PutG2s(points []bls12381.G2Affine) {
+	e.PutUint64(uint64(len(points)))
+	for i := range points {
+		e.PutG2(&points[i])
+	}
+}
+
+func (e *Encoder) putFp(v *fp.Element) {
+	var b [fp.Bytes]byte
+	fp.BigEndian.PutElement(&b, *v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+// Decoder reads values that were written by an [Encoder].
+//
+// The first error that is encountered is sticky: once it is set, all of the
+// following reads return zero values and [Decoder.Err] reports the error.
+type Decoder struct {
+	buf []byte
+	err error
+}
+
+// NewDecoder creates a Decoder reading from `buf`.
+func NewDecoder(buf []byte) *Decoder {
+	return &Decoder{buf: buf}
+}
+
+// Err returns the first error that was encountered while decoding.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Remaining returns the number of bytes which have not been read yet.
+func (d *Decoder) Remaining() int {
+	return len(d.buf)
+}
+
+// Bytes reads `n` raw bytes.
+//
+// The returned slice aliases the decoder's buffer.
+func (d *Decoder) Bytes(n int) []byte {
+	return d.take(n)
+}
+
+// Uint64 reads a uint64.
+func (d *Decoder) Uint64() uint64 {
+	b := d.take(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// Fr reads a scalar field element.
+func (d *Decoder) Fr() fr.Element {
+	b := d.take(fr.Bytes)
+	if b == nil {
+		return fr.Element{}
+	}
+	v, err := fr.BigEndian.Element((*[fr.Bytes]byte)(b))
+	if err != nil {
+		d.fail(ErrInvalidEncoding)
+		return fr.Element{}
+	}
+	return v
+}
+
+// Frs reads a length-prefixed slice of scalar field elements.
+func (d *Decoder) Frs() []fr.Element {
+	n := d.length(fr.Bytes)
+	v := make([]fr.Element, n)
+	for i := range v {
+		v[i] = d.Fr()
+	}
+	return v
+}
+
+// G1 reads a G1 point.
+//
+// Note: No curve or subgroup checks are performed.
+func (d *Decoder) G1() bls12381.G1Affine {
+	var p bls12381.G1Affine
+	p.X = d.fp()
+	p.Y = d.fp()
+	return p
+}
+
+// G1s reads a length-prefixed slice of G1 points.
+//
+// Note: No curve or subgroup checks are performed.
+func (d *Decoder) G1s() []bls12381.G1Affine {
+	n := d.length(G1Size)
+	points := make([]bls12381.G1Affine, n)
+	for i := range points {
+		points[i] = d.G1()
+	}
+	return points
+}
+
+// G2 reads a G2 point.
+//
+// Note: No curve or subgroup checks are performed.
+func (d *Decoder) G2() bls12381.G2Affine {
+	var p bls12381.G2Affine
+	p.X.A0 = d.fp()
+	p.X.A1 = d.fp()
+	p.Y.A0 = d.fp()
+	p.Y.A1 = d.fp()
+	return p
+}
+
+// G2s reads a length-prefixed slice of G2 points.
+//
+// Note: No curve or subgroup checks are performed.
+func (d *Decoder) G2s() []bls12381.G2Affine {
+	n := d.length(G2Size)
+	points := make([]bls12381.G2Affine, n)
+	for i := range points {
+		points[i] = d.G2()
+	}
+	return points
+}
+
+func (d *Decoder) fp() fp.Element {
+	b := d.take(fp.Bytes)
+	if b == nil {
+		return fp.Element{}
+	}
+	v, err := fp.BigEndian.Element((*[fp.Bytes]byte)(b))
+	if err != nil {
+		d.fail(ErrInvalidEncoding)
+		return fp.Element{}
+	}
+	return v
+}
+
+// length reads a length prefix and checks that there is enough data left
+// for that many elements of size `elemSize`.
+//
+// This ensures that we never allocate more memory than the size of the input.
+func (d *Decoder) length(elemSize int) int {
+	n := d.Uint64()
+	if d.err != nil {
+		return 0
+	}
+	if n > uint64(len(d.buf)/elemSize) {
+		d.fail(ErrLengthTooLarge)
+		return 0
+	}
+	return int(n)
+}
+
+func (d *Decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > len(d.buf) {
+		d.fail(ErrUnexpectedEnd)
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *Decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+	d.buf = nil
+}
@@ -0,0 +1,72 @@
+package codec
+
+import (
+	"errors"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	_, _, g1Gen, g2Gen := bls12381.Generators()
+
+	var scalar fr.Element
+	_, err := scalar.SetRandom()
+	require.NoError(t, err)
+
+	scalars := []fr.Element{fr.NewElement(1), scalar, {}}
+	// The point at infinity is included since the FK20 tables are padded with it.
+	g1s := []bls12381.G1Affine{g1Gen, {}}
+	g2s := []bls12381.G2Affine{g2Gen, {}}
+
+	enc := NewEncoder(0)
+	enc.PutUint64(42)
+	enc.PutFrs(scalars)
+	enc.PutG1s(g1s)
+	enc.PutG2s(g2s)
+	enc.PutBytes([]byte("tail"))
+
+	dec := NewDecoder(enc.Bytes())
+	require.Equal(t, uint64(42), dec.Uint64())
+	require.Equal(t, scalars, dec.Frs())
+	require.Equal(t, g1s, dec.G1s())
+	require.Equal(t, g2s, dec.G2s())
+	require.Equal(t, []byte("tail"), dec.Bytes(4))
+	require.NoError(t, dec.Err())
+	require.Equal(t, 0, dec.Remaining())
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	_, _, g1Gen, _ := bls12381.Generators()
+
+	enc := NewEncoder(0)
+	enc.PutG1s([]bls12381.G1Affine{g1Gen, g1Gen})
+	encoded := enc.Bytes()
+
+	dec := NewDecoder(encoded[:len(encoded)-1])
+	_ = dec.G1s()
+	if !errors.Is(dec.Err(), ErrLengthTooLarge) {
+		t.Fatalf("expected %v but got %v", ErrLengthTooLarge, dec.Err())
+	}
+
+	// Once an error has been encountered, it is sticky
+	require.Equal(t, uint64(0), dec.Uint64())
+	require.ErrorIs(t, dec.Err(), ErrLengthTooLarge)
+
+	dec = NewDecoder(encoded[:4])
+	_ = dec.Uint64()
+	require.ErrorIs(t, dec.Err(), ErrUnexpectedEnd)
+}
+
+func TestDecodeNonCanonical(t *testing.T) {
+	encoded := make([]byte, fr.Bytes)
+	for i := range encoded {
+		encoded[i] = 0xff
+	}
+
+	dec := NewDecoder(encoded)
+	_ = dec.Fr()
+	require.ErrorIs(t, dec.Err(), ErrInvalidEncoding)
+}
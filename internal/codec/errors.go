@@ -0,0 +1,9 @@
+package codec
+
+import "errors"
+
+var (
+	ErrUnexpectedEnd   = errors.New("unexpected end of encoded data")
+	ErrLengthTooLarge  = errors.New("encoded length exceeds the remaining data")
+	ErrInvalidEncoding = errors.New("encoded element is not a canonical field element")
+)
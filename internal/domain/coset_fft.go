@@ -34,6 +34,11 @@ func NewCosetDomain(domain *Domain, fft_coset FFTCoset) *CosetDomain {
 	}
 }
 
+// Coset returns the coset generator and its inverse for this domain.
+func (d *CosetDomain) Coset() FFTCoset {
+	return d.coset
+}
+
 // CosetFFtFr performs a forward coset FFT on the input values.
 //
 // It first scales the input values by powers of the coset generator,
@@ -45,7 +50,7 @@ func (d *CosetDomain) CosetFFtFr(values []fr.Element) {
 		values[i].Mul(&values[i], &cosetScale)
 		cosetScale.Mul(&cosetScale, &d.coset.CosetGen)
 	}
-	fftFrInPlace(values, d.domain.Generator)
+	d.domain.FftFr(values)
 }
 
 // CosetIFFtFr performs an inverse coset FFT on the input values.
@@ -56,10 +61,7 @@ func (d *CosetDomain) CosetIFFtFr(values []fr.Element) {
 	n := len(values)
 
 	// In-place inverse FFT (DIF with inverse generator) and 1/n scaling
-	fftFrInPlace(values, d.domain.GeneratorInv)
-	for i := 0; i < n; i++ {
-		values[i].Mul(&values[i], &d.domain.CardinalityInv)
-	}
+	d.domain.IfftFr(values)
 
 	// Scale by inverse coset generator powers
 	cosetScale := fr.One()
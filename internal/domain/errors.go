@@ -2,4 +2,7 @@ package domain
 
 import "errors"
 
-var ErrPolynomialMismatchedSizeDomain = errors.New("domain size does not equal the number of evaluations in the polynomial")
+var (
+	ErrPolynomialMismatchedSizeDomain = errors.New("domain size does not equal the number of evaluations in the polynomial")
+	ErrInvalidSerializedDomain        = errors.New("serialized domain is malformed")
+)
@@ -1,10 +1,13 @@
 package domain
 
 import (
+	"context"
 	"math/big"
+	"sync"
 
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-eth-kzg/internal/utils"
 )
 
 // In this file we implement a simple version of the fft algorithm
@@ -21,8 +24,23 @@ import (
 // The elements are returned in order as opposed to being returned in
 // bit-reversed order.
 func (domain *Domain) FftG1(values []bls12381.G1Affine) {
-	out := fftG1(values, domain.Generator)
+	// This cannot fail, since the background context is never cancelled.
+	_ = domain.FftG1Ctx(context.Background(), values, 1)
+}
+
+// FftG1Ctx is the same as [Domain.FftG1], but stops and returns ctx.Err() if `ctx` is cancelled.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+//
+// Note: `values` is left in an unspecified state if an error is returned.
+func (domain *Domain) FftG1Ctx(ctx context.Context, values []bls12381.G1Affine, numGoRoutines int) error {
+	out, err := fftG1(ctx, values, domain.Generator, utils.ResolveNumGoRoutines(numGoRoutines))
+	if err != nil {
+		return err
+	}
 	copy(values, out)
+	return nil
 }
 
 // Computes an IFFT(Inverse Fast Fourier Transform) of the G1 elements in-place.
@@ -30,15 +48,37 @@ func (domain *Domain) FftG1(values []bls12381.G1Affine) {
 // The elements are returned in order as opposed to being returned in
 // bit-reversed order.
 func (domain *Domain) IfftG1(values []bls12381.G1Affine) {
+	// This cannot fail, since the background context is never cancelled.
+	_ = domain.IfftG1Ctx(context.Background(), values, 1)
+}
+
+// IfftG1Ctx is the same as [Domain.IfftG1], but stops and returns ctx.Err() if `ctx` is cancelled.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+//
+// Note: `values` is left in an unspecified state if an error is returned.
+func (domain *Domain) IfftG1Ctx(ctx context.Context, values []bls12381.G1Affine, numGoRoutines int) error {
+	numGoRoutines = utils.ResolveNumGoRoutines(numGoRoutines)
+
 	var invDomainBI big.Int
 	domain.CardinalityInv.BigInt(&invDomainBI)
 
-	inverseFFT := fftG1(values, domain.GeneratorInv)
-	// scale by the inverse of the domain size
-	for i := 0; i < len(inverseFFT); i++ {
-		inverseFFT[i].ScalarMultiplication(&inverseFFT[i], &invDomainBI)
+	inverseFFT, err := fftG1(ctx, values, domain.GeneratorInv, numGoRoutines)
+	if err != nil {
+		return err
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// scale by the inverse of the domain size
+	utils.ParallelFor(len(inverseFFT), numGoRoutines, func(start, end int) {
+		for i := start; i < end; i++ {
+			inverseFFT[i].ScalarMultiplication(&inverseFFT[i], &invDomainBI)
+		}
+	})
 	copy(values, inverseFFT)
+	return nil
 }
 
 // fftG1 computes an FFT (Fast Fourier Transform) of the G1 elements.
@@ -46,10 +86,18 @@ func (domain *Domain) IfftG1(values []bls12381.G1Affine) {
 // This is the actual implementation of [FftG1] with the same convention.
 // That is, the returned slice is in "normal", rather than bit-reversed order.
 // We assert that values is a slice of length n==2^i and nthRootOfUnity is a primitive n'th root of unity.
-func fftG1(values []bls12381.G1Affine, nthRootOfUnity fr.Element) []bls12381.G1Affine {
+//
+// Since every level of the recursion does scalar multiplications, `ctx` is checked once per call.
+//
+// `numGoRoutines` must be at least one. The two halves of the recursion are split between
+// the go-routines, and each level combines its halves using all of the go-routines it was given.
+func fftG1(ctx context.Context, values []bls12381.G1Affine, nthRootOfUnity fr.Element, numGoRoutines int) ([]bls12381.G1Affine, error) {
 	n := len(values)
 	if n == 1 {
-		return values
+		return values, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	var generatorSquared fr.Element
@@ -59,60 +107,123 @@ func fftG1(values []bls12381.G1Affine, nthRootOfUnity fr.Element) []bls12381.G1A
 	even, odd := takeEvenOdd(values)
 
 	// perform FFT recursively on those parts.
-	fftEven := fftG1(even, generatorSquared)
-	fftOdd := fftG1(odd, generatorSquared)
+	var fftEven, fftOdd []bls12381.G1Affine
+	var errEven, errOdd error
+	if numGoRoutines > 1 {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fftEven, errEven = fftG1(ctx, even, generatorSquared, numGoRoutines/2)
+		}()
+		fftOdd, errOdd = fftG1(ctx, odd, generatorSquared, numGoRoutines-numGoRoutines/2)
+		wg.Wait()
+	} else {
+		fftEven, errEven = fftG1(ctx, even, generatorSquared, 1)
+		if errEven == nil {
+			fftOdd, errOdd = fftG1(ctx, odd, generatorSquared, 1)
+		}
+	}
+	if errEven != nil {
+		return nil, errEven
+	}
+	if errOdd != nil {
+		return nil, errOdd
+	}
 
 	// combine them to get the result
 	// - evaluations[k] = fftEven[k] + w^k * fftOdd[k]
 	// - evaluations[k] = fftEven[k] - w^k * fftOdd[k]
 	// where w is a n'th primitive root of unity.
-	inputPoint := fr.One()
 	evaluations := make([]bls12381.G1Affine, n)
-	for k := 0; k < n/2; k++ {
-		var tmp bls12381.G1Affine
+	utils.ParallelFor(n/2, numGoRoutines, func(start, end int) {
+		var inputPoint fr.Element
+		inputPoint.Exp(nthRootOfUnity, big.NewInt(int64(start)))
 
-		var inputPointBI big.Int
-		inputPoint.BigInt(&inputPointBI)
+		for k := start; k < end; k++ {
+			var tmp bls12381.G1Affine
 
-		if inputPoint.IsOne() {
-			tmp.Set(&fftOdd[k])
-		} else {
-			tmp.ScalarMultiplication(&fftOdd[k], &inputPointBI)
-		}
+			var inputPointBI big.Int
+			inputPoint.BigInt(&inputPointBI)
 
-		evaluations[k].Add(&fftEven[k], &tmp)
-		evaluations[k+n/2].Sub(&fftEven[k], &tmp)
+			if inputPoint.IsOne() {
+				tmp.Set(&fftOdd[k])
+			} else {
+				tmp.ScalarMultiplication(&fftOdd[k], &inputPointBI)
+			}
 
-		// we could take this from precomputed values in Domain (as domain.roots[n*k]), but then we would need to pass the domain.
-		// At any rate, we don't really need to optimize here.
-		inputPoint.Mul(&inputPoint, &nthRootOfUnity)
-	}
+			evaluations[k].Add(&fftEven[k], &tmp)
+			evaluations[k+n/2].Sub(&fftEven[k], &tmp)
+
+			// we could take this from precomputed values in Domain (as domain.roots[n*k]), but then we would need to pass the domain.
+			// At any rate, we don't really need to optimize here.
+			inputPoint.Mul(&inputPoint, &nthRootOfUnity)
+		}
+	})
 
-	return evaluations
+	return evaluations, nil
 }
 
 // FftFr computes the FFT of the input values in-place
 func (d *Domain) FftFr(values []fr.Element) {
-	fftFrInPlace(values, d.Generator)
+	// This cannot fail, since the background context is never cancelled.
+	_ = d.FftFrCtx(context.Background(), values, 1)
+}
+
+// FftFrCtx is the same as [Domain.FftFr], but splits the work between go-routines and stops
+// and returns ctx.Err() if `ctx` is cancelled. This is checked between the levels of butterflies.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+//
+// Note: `values` is left in an unspecified state if an error is returned.
+func (d *Domain) FftFrCtx(ctx context.Context, values []fr.Element, numGoRoutines int) error {
+	return fftFrInPlace(ctx, values, d.Generator, numGoRoutines)
 }
 
 // IfftFr computes the inverse FFT of the input values in-place
 func (d *Domain) IfftFr(values []fr.Element) {
+	// This cannot fail, since the background context is never cancelled.
+	_ = d.IfftFrCtx(context.Background(), values, 1)
+}
+
+// IfftFrCtx is the same as [Domain.IfftFr], but splits the work between go-routines and stops
+// and returns ctx.Err() if `ctx` is cancelled. This is checked between the levels of butterflies.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+//
+// Note: `values` is left in an unspecified state if an error is returned.
+func (d *Domain) IfftFrCtx(ctx context.Context, values []fr.Element, numGoRoutines int) error {
 	// In-place DIF using inverse generator
-	fftFrInPlace(values, d.GeneratorInv)
+	if err := fftFrInPlace(ctx, values, d.GeneratorInv, numGoRoutines); err != nil {
+		return err
+	}
 	// scale by the inverse of the domain size
 	for i := 0; i < len(values); i++ {
 		values[i].Mul(&values[i], &d.CardinalityInv)
 	}
+	return nil
 }
 
-func fftFrInPlace(values []fr.Element, nthRootOfUnity fr.Element) {
+// minButterfliesPerGoRoutine is the smallest amount of work that [fftFrInPlace] gives to a
+// go-routine. A butterfly over the scalar field is cheap, so smaller domains are not worth
+// splitting up.
+const minButterfliesPerGoRoutine = 512
+
+// fftFrInPlace computes an FFT of the scalars in-place, checking `ctx` before each level of butterflies.
+func fftFrInPlace(ctx context.Context, values []fr.Element, nthRootOfUnity fr.Element, numGoRoutines int) error {
 	n := len(values)
 	if n <= 1 {
-		return
+		return nil
 	}
 
+	numGoRoutines = min(utils.ResolveNumGoRoutines(numGoRoutines), max(1, (n/2)/minButterfliesPerGoRoutine))
+
 	for size := n; size >= 2; size >>= 1 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		half := size >> 1
 
 		// Compute wStep = root^(n/size)
@@ -122,10 +233,20 @@ func fftFrInPlace(values []fr.Element, nthRootOfUnity fr.Element) {
 			wStep.Mul(&wStep, &nthRootOfUnity)
 		}
 
-		for start := 0; start < n; start += size {
-			w := fr.One()
-			for k := 0; k < half; k++ {
-				i0 := start + k
+		// Each level has n/2 butterflies, where butterfly j works on the k'th pair of
+		// the block that starts at (j/half)*size, for k = j%half.
+		// Since half is a power of two, these are computed with a mask.
+		mask := half - 1
+		utils.ParallelFor(n/2, numGoRoutines, func(startJ, endJ int) {
+			var w fr.Element
+			w.Exp(wStep, big.NewInt(int64(startJ&mask)))
+
+			for j := startJ; j < endJ; j++ {
+				k := j & mask
+				if k == 0 {
+					w.SetOne()
+				}
+				i0 := (j&^mask)<<1 + k
 				i1 := i0 + half
 
 				// Gentleman–Sande butterfly
@@ -136,11 +257,12 @@ func fftFrInPlace(values []fr.Element, nthRootOfUnity fr.Element) {
 
 				w.Mul(&w, &wStep)
 			}
-		}
+		})
 	}
 
 	// Bit-reverse permutation to restore natural order
 	BitReverse(values)
+	return nil
 }
 
 // takeEvenOdd Takes a slice and return two slices
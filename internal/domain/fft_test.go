@@ -1,8 +1,12 @@
 package domain
 
 import (
+	"context"
+	"errors"
+	"math/big"
 	"testing"
 
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
@@ -100,3 +104,96 @@ func fftFrRef(values []fr.Element, nthRootOfUnity fr.Element) []fr.Element {
 	}
 	return evaluations
 }
+
+func TestFftG1Ctx(t *testing.T) {
+	d := NewDomain(8)
+	_, _, genG1, _ := bls12381.Generators()
+
+	values := make([]bls12381.G1Affine, 8)
+	for i := range values {
+		var scalar big.Int
+		scalar.SetInt64(int64(i + 1))
+		values[i].ScalarMultiplication(&genG1, &scalar)
+	}
+
+	expected := make([]bls12381.G1Affine, len(values))
+	copy(expected, values)
+	d.FftG1(expected)
+
+	for _, numGoRoutines := range []int{1, 3, 0} {
+		got := make([]bls12381.G1Affine, len(values))
+		copy(got, values)
+		if err := d.FftG1Ctx(context.Background(), got, numGoRoutines); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := range expected {
+			if !expected[i].Equal(&got[i]) {
+				t.Fatalf("fft on g1 with %d go-routines is incorrect", numGoRoutines)
+			}
+		}
+
+		if err := d.IfftG1Ctx(context.Background(), got, numGoRoutines); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := range values {
+			if !values[i].Equal(&got[i]) {
+				t.Fatalf("ifft on g1 with %d go-routines is incorrect", numGoRoutines)
+			}
+		}
+	}
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.FftG1Ctx(cancelledCtx, values, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if err := d.IfftG1Ctx(cancelledCtx, values, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestFftFrCtx(t *testing.T) {
+	// The domain needs to be large enough for the work to be split up
+	n := uint64(1 << 13)
+	d := NewDomain(n)
+
+	values := make([]fr.Element, n)
+	for i := range values {
+		values[i].SetRandom()
+	}
+
+	expected := make([]fr.Element, n)
+	copy(expected, values)
+	d.FftFr(expected)
+
+	for _, numGoRoutines := range []int{1, 3, 8, 0} {
+		got := make([]fr.Element, n)
+		copy(got, values)
+		if err := d.FftFrCtx(context.Background(), got, numGoRoutines); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := range expected {
+			if !expected[i].Equal(&got[i]) {
+				t.Fatalf("fft on fr with %d go-routines is incorrect", numGoRoutines)
+			}
+		}
+
+		if err := d.IfftFrCtx(context.Background(), got, numGoRoutines); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := range values {
+			if !values[i].Equal(&got[i]) {
+				t.Fatalf("ifft on fr with %d go-routines is incorrect", numGoRoutines)
+			}
+		}
+	}
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.FftFrCtx(cancelledCtx, values, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if err := d.IfftFrCtx(cancelledCtx, values, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"github.com/crate-crypto/go-eth-kzg/internal/codec"
+	"github.com/crate-crypto/go-eth-kzg/internal/utils"
+)
+
+// Serialize writes the domain, including all of its precomputed values, to `enc`.
+//
+// The roots are written in the order that they are currently in, so a domain
+// whose roots have been bit-reversed is decoded with bit-reversed roots.
+func (domain *Domain) Serialize(enc *codec.Encoder) {
+	enc.PutUint64(domain.Cardinality)
+	enc.PutFr(&domain.CardinalityInv)
+	enc.PutFr(&domain.Generator)
+	enc.PutFr(&domain.GeneratorInv)
+	enc.PutFrs(domain.Roots)
+	enc.PutFrs(domain.PreComputedInverses)
+}
+
+// DeserializeDomain reads a domain that was written using [Domain.Serialize].
+func DeserializeDomain(dec *codec.Decoder) (*Domain, error) {
+	domain := &Domain{}
+	domain.Cardinality = dec.Uint64()
+	domain.CardinalityInv = dec.Fr()
+	domain.Generator = dec.Fr()
+	domain.GeneratorInv = dec.Fr()
+	domain.Roots = dec.Frs()
+	domain.PreComputedInverses = dec.Frs()
+	if err := dec.Err(); err != nil {
+		return nil, err
+	}
+
+	if !utils.IsPowerOfTwo(domain.Cardinality) ||
+		uint64(len(domain.Roots)) != domain.Cardinality ||
+		uint64(len(domain.PreComputedInverses)) != domain.Cardinality {
+		return nil, ErrInvalidSerializedDomain
+	}
+
+	return domain, nil
+}
+
+// SerializeCosetDomains writes a list of coset domains which all share the same
+// underlying domain. The shared domain is only written once.
+//
+// Panics if the coset domains do not share the same underlying domain.
+func SerializeCosetDomains(enc *codec.Encoder, cosetDomains []*CosetDomain) {
+	enc.PutUint64(uint64(len(cosetDomains)))
+	if len(cosetDomains) == 0 {
+		return
+	}
+
+	shared := cosetDomains[0].domain
+	shared.Serialize(enc)
+	for _, cosetDomain := range cosetDomains {
+		if cosetDomain.domain != shared {
+			panic("coset domains do not share the same underlying domain")
+		}
+		cosetDomain.coset.Serialize(enc)
+	}
+}
+
+// DeserializeCosetDomains reads a list of coset domains that was written using [SerializeCosetDomains].
+func DeserializeCosetDomains(dec *codec.Decoder) ([]*CosetDomain, error) {
+	numCosetDomains := dec.Uint64()
+	if err := dec.Err(); err != nil {
+		return nil, err
+	}
+	if numCosetDomains == 0 {
+		return []*CosetDomain{}, nil
+	}
+
+	shared, err := DeserializeDomain(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each coset is serialized using two field elements
+	if numCosetDomains > uint64(dec.Remaining()/(2*32)) {
+		return nil, codec.ErrLengthTooLarge
+	}
+
+	cosetDomains := make([]*CosetDomain, numCosetDomains)
+	for i := range cosetDomains {
+		coset, err := DeserializeFFTCoset(dec)
+		if err != nil {
+			return nil, err
+		}
+		cosetDomains[i] = NewCosetDomain(shared, coset)
+	}
+
+	return cosetDomains, nil
+}
+
+// Serialize writes the coset generator and its inverse to `enc`.
+func (c *FFTCoset) Serialize(enc *codec.Encoder) {
+	enc.PutFr(&c.CosetGen)
+	enc.PutFr(&c.InvCosetGen)
+}
+
+// DeserializeFFTCoset reads a coset that was written using [FFTCoset.Serialize].
+func DeserializeFFTCoset(dec *codec.Decoder) (FFTCoset, error) {
+	coset := FFTCoset{
+		CosetGen:    dec.Fr(),
+		InvCosetGen: dec.Fr(),
+	}
+	return coset, dec.Err()
+}
@@ -1,6 +1,7 @@
 package erasure_code
 
 import (
+	"context"
 	"errors"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -107,39 +108,110 @@ func (dr *DataRecovery) NumBlocksNeededToReconstruct() int {
 	return dr.numScalarsInDataWord / dr.blockErasureSize
 }
 
-func (dr *DataRecovery) RecoverPolynomialCoefficients(data []fr.Element, missingIndices []BlockErasureIndex) ([]fr.Element, error) {
+// BlockErasureSize returns the number of evaluations in each block of the codeword.
+func (dr *DataRecovery) BlockErasureSize() int {
+	return dr.blockErasureSize
+}
+
+// NumScalarsInDataWord returns the number of scalars in the data word that is encoded.
+func (dr *DataRecovery) NumScalarsInDataWord() int {
+	return dr.numScalarsInDataWord
+}
+
+// ExpansionFactor returns the factor by which the data word is expanded.
+func (dr *DataRecovery) ExpansionFactor() int {
+	return dr.expansionFactor
+}
+
+// TotalNumBlocks returns the number of blocks in the codeword.
+func (dr *DataRecovery) TotalNumBlocks() int {
+	return dr.totalNumBlocks
+}
+
+// VanishingPoly is the polynomial Z(x) that vanishes on the missing blocks of a codeword, in the forms that are
+// needed to recover the codeword.
+//
+// It only depends on which blocks are missing, so it can be computed once and used to recover every codeword that
+// is missing the same blocks.
+type VanishingPoly struct {
+	// eval holds the evaluations of Z(x) over the extended domain.
+	eval []fr.Element
+	// cosetEvalInv holds the inverses of the evaluations of Z(x) over the coset of the extended domain.
+	cosetEvalInv []fr.Element
+}
+
+// NewVanishingPoly computes the vanishing polynomial for the blocks at `missingIndices`.
+//
+// It stops and returns ctx.Err() if `ctx` is cancelled. This is checked between each of the FFTs, and during the
+// FFTs over the extended domain.
+func (dr *DataRecovery) NewVanishingPoly(ctx context.Context, missingIndices []BlockErasureIndex) (*VanishingPoly, error) {
 	zX := dr.constructVanishingPolyOnIndices(missingIndices)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Compute zX evaluations without mutating zX since we need zX later for a coset FFT
-	zXForEval := make([]fr.Element, len(zX))
-	copy(zXForEval, zX)
-	dr.domainExtended.FftFr(zXForEval)
-	zXEval := zXForEval
+	zXEval := make([]fr.Element, len(zX))
+	copy(zXEval, zX)
+	if err := dr.domainExtended.FftFrCtx(ctx, zXEval, 1); err != nil {
+		return nil, err
+	}
 
-	if len(zXEval) != len(data) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	dr.domainExtendedCoset.CosetFFtFr(zX)
+	cosetZxEval := zX
+
+	return &VanishingPoly{eval: zXEval, cosetEvalInv: fr.BatchInvert(cosetZxEval)}, nil
+}
+
+// RecoverPolynomialCoefficients recovers the coefficients of the polynomial whose evaluations are `data`, where
+// the blocks at `missingIndices` are missing.
+//
+// It stops and returns ctx.Err() if `ctx` is cancelled. This is checked between each of the FFTs, and during the
+// FFTs over the extended domain.
+func (dr *DataRecovery) RecoverPolynomialCoefficients(ctx context.Context, data []fr.Element, missingIndices []BlockErasureIndex) ([]fr.Element, error) {
+	zX, err := dr.NewVanishingPoly(ctx, missingIndices)
+	if err != nil {
+		return nil, err
+	}
+	return dr.RecoverPolynomialCoefficientsWithVanishingPoly(ctx, data, zX)
+}
+
+// RecoverPolynomialCoefficientsWithVanishingPoly is the same as [DataRecovery.RecoverPolynomialCoefficients], with
+// the vanishing polynomial of the missing blocks computed beforehand by [DataRecovery.NewVanishingPoly].
+//
+// `zX` is not modified, so it can be shared by several go-routines.
+func (dr *DataRecovery) RecoverPolynomialCoefficientsWithVanishingPoly(ctx context.Context, data []fr.Element, zX *VanishingPoly) ([]fr.Element, error) {
+	if len(zX.eval) != len(data) {
 		return nil, errors.New("length of data and zXEval should be equal")
 	}
 
 	eZEval := make([]fr.Element, len(data))
 	for i := 0; i < len(data); i++ {
-		eZEval[i].Mul(&data[i], &zXEval[i])
+		eZEval[i].Mul(&data[i], &zX.eval[i])
 	}
 
-	dr.domainExtended.IfftFr(eZEval)
+	if err := dr.domainExtended.IfftFrCtx(ctx, eZEval, 1); err != nil {
+		return nil, err
+	}
 	dzPoly := eZEval
 
-	dr.domainExtendedCoset.CosetFFtFr(zX)
-	cosetZxEval := zX
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	dr.domainExtendedCoset.CosetFFtFr(dzPoly)
 	cosetDzEVal := dzPoly
 
-	cosetQuotientEval := make([]fr.Element, len(cosetZxEval))
-	cosetZxEval = fr.BatchInvert(cosetZxEval)
-
-	for i := 0; i < len(cosetZxEval); i++ {
-		cosetQuotientEval[i].Mul(&cosetDzEVal[i], &cosetZxEval[i])
+	cosetQuotientEval := make([]fr.Element, len(zX.cosetEvalInv))
+	for i := 0; i < len(zX.cosetEvalInv); i++ {
+		cosetQuotientEval[i].Mul(&cosetDzEVal[i], &zX.cosetEvalInv[i])
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	dr.domainExtendedCoset.CosetIFFtFr(cosetQuotientEval)
 
 	// Truncate the polynomial coefficients to the number of scalars in the data word
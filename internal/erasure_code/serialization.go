@@ -0,0 +1,63 @@
+package erasure_code
+
+import (
+	"errors"
+
+	"github.com/crate-crypto/go-eth-kzg/internal/codec"
+	"github.com/crate-crypto/go-eth-kzg/internal/domain"
+)
+
+var ErrInvalidSerializedDataRecovery = errors.New("serialized data recovery instance is malformed")
+
+// Serialize writes the data recovery instance, including its domains, to `enc`.
+func (dr *DataRecovery) Serialize(enc *codec.Encoder) {
+	dr.rootsOfUnityBlockErasureIndex.Serialize(enc)
+	dr.domainExtended.Serialize(enc)
+	coset := dr.domainExtendedCoset.Coset()
+	coset.Serialize(enc)
+	enc.PutUint64(uint64(dr.blockErasureSize))
+	enc.PutUint64(uint64(dr.numScalarsInCodeword))
+	enc.PutUint64(uint64(dr.numScalarsInDataWord))
+	enc.PutUint64(uint64(dr.expansionFactor))
+	enc.PutUint64(uint64(dr.totalNumBlocks))
+}
+
+// DeserializeDataRecovery reads a data recovery instance that was written using [DataRecovery.Serialize].
+func DeserializeDataRecovery(dec *codec.Decoder) (*DataRecovery, error) {
+	rootsOfUnityBlockErasureIndex, err := domain.DeserializeDomain(dec)
+	if err != nil {
+		return nil, err
+	}
+	domainExtended, err := domain.DeserializeDomain(dec)
+	if err != nil {
+		return nil, err
+	}
+	coset, err := domain.DeserializeFFTCoset(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	dr := &DataRecovery{
+		rootsOfUnityBlockErasureIndex: rootsOfUnityBlockErasureIndex,
+		domainExtended:                domainExtended,
+		domainExtendedCoset:           domain.NewCosetDomain(domainExtended, coset),
+		blockErasureSize:              int(dec.Uint64()),
+		numScalarsInCodeword:          int(dec.Uint64()),
+		numScalarsInDataWord:          int(dec.Uint64()),
+		expansionFactor:               int(dec.Uint64()),
+		totalNumBlocks:                int(dec.Uint64()),
+	}
+	if err := dec.Err(); err != nil {
+		return nil, err
+	}
+
+	if dr.blockErasureSize <= 0 ||
+		uint64(dr.numScalarsInCodeword) != domainExtended.Cardinality ||
+		uint64(dr.totalNumBlocks) != rootsOfUnityBlockErasureIndex.Cardinality ||
+		dr.totalNumBlocks*dr.blockErasureSize != dr.numScalarsInCodeword ||
+		dr.numScalarsInDataWord*dr.expansionFactor != dr.numScalarsInCodeword {
+		return nil, ErrInvalidSerializedDataRecovery
+	}
+
+	return dr, nil
+}
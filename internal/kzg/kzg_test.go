@@ -40,15 +40,19 @@ func TestBatchVerifySmoke(t *testing.T) {
 		proofs = append(proofs, proof)
 	}
 
+	var randomNumber fr.Element
+	_, err := randomNumber.SetRandom()
+	require.NoError(t, err)
+
 	// Check that these verify successfully.
-	err := BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey)
+	err = BatchVerifyMultiPoints(commitments, proofs, randomNumber, &srs.OpeningKey)
 	require.NoError(t, err)
 
 	// Add an invalid proof, to ensure that it fails
 	proof, _ := randValidOpeningProof(t, *domain, *srs)
 	commitments = append(commitments, bls12381.G1Affine{})
 	proofs = append(proofs, proof)
-	err = BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey)
+	err = BatchVerifyMultiPoints(commitments, proofs, randomNumber, &srs.OpeningKey)
 	require.Error(t, err, "An invalid proof was added to the list, however verification returned true")
 }
 
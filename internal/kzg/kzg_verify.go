@@ -102,13 +102,14 @@ func Verify(commitment *Commitment, proof *OpeningProof, openKey *OpeningKey) er
 // BatchVerifyMultiPoints verifies multiple KZG proofs in a batch. See [verify_kzg_proof_batch].
 //
 //   - This method is more efficient than calling [Verify] multiple times.
-//   - Randomness is used to combine multiple proofs into one.
+//   - Powers of `randomNumber` are used to combine multiple proofs into one. It should either be sampled at random,
+//     or be derived from all of the inputs using Fiat-Shamir, as is done in the spec.
 //
 // Modified from [gnark-crypto].
 //
 // [verify_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof_batch
 // [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/kzg/kzg.go#L367)
-func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey) error {
+func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, randomNumber fr.Element, openKey *OpeningKey) error {
 	// Check consistency number of proofs is equal to the number of commitments.
 	if len(commitments) != len(proofs) {
 		return ErrInvalidNumDigests
@@ -127,17 +128,10 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 		return Verify(&commitments[0], &proofs[0], openKey)
 	}
 
-	// Sample random numbers for sampling.
-	//
-	// We only need to sample one random number and
-	// compute powers of that random number. This works
+	// We only need one random number and compute
+	// powers of that random number. This works
 	// since powers will produce a vandermonde matrix
 	// which is linearly independent.
-	var randomNumber fr.Element
-	_, err := randomNumber.SetRandom()
-	if err != nil {
-		return err
-	}
 	randomNumbers := utils.ComputePowers(randomNumber, uint(batchSize))
 
 	// Combine random_i*quotient_i
@@ -147,7 +141,7 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 		quotients[i].Set(&proofs[i].QuotientCommitment)
 	}
 	config := ecc.MultiExpConfig{}
-	_, err = foldedQuotients.MultiExp(quotients, randomNumbers, config)
+	_, err := foldedQuotients.MultiExp(quotients, randomNumbers, config)
 	if err != nil {
 		return err
 	}
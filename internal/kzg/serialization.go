@@ -0,0 +1,35 @@
+package kzg
+
+import (
+	"github.com/crate-crypto/go-eth-kzg/internal/codec"
+)
+
+// Serialize writes the commit key to `enc`.
+func (c *CommitKey) Serialize(enc *codec.Encoder) {
+	enc.PutG1s(c.G1)
+}
+
+// DeserializeCommitKey reads a commit key that was written using [CommitKey.Serialize].
+func DeserializeCommitKey(dec *codec.Decoder) (*CommitKey, error) {
+	commitKey := &CommitKey{
+		G1: dec.G1s(),
+	}
+	return commitKey, dec.Err()
+}
+
+// Serialize writes the opening key to `enc`.
+func (o *OpeningKey) Serialize(enc *codec.Encoder) {
+	enc.PutG1(&o.GenG1)
+	enc.PutG2(&o.GenG2)
+	enc.PutG2(&o.AlphaG2)
+}
+
+// DeserializeOpeningKey reads an opening key that was written using [OpeningKey.Serialize].
+func DeserializeOpeningKey(dec *codec.Decoder) (*OpeningKey, error) {
+	openingKey := &OpeningKey{
+		GenG1:   dec.G1(),
+		GenG2:   dec.G2(),
+		AlphaG2: dec.G2(),
+	}
+	return openingKey, dec.Err()
+}
@@ -53,38 +53,50 @@ func newSRSInsecure(domain domain.Domain, bAlpha *big.Int, convertToLagrange boo
 // to match the other functions is defined in the testing code.
 //
 // This method should not be used in production because as the secret is supplied as input.
-//
-// Copied from [gnark-crypto].
-//
-// [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/kzg/kzg.go#L65
 func newMonomialSRSInsecureUint64(size uint64, bAlpha *big.Int) (*SRS, error) {
 	if size < 2 {
 		return nil, ErrMinSRSSize
 	}
 
+	g1s, g2s := InsecurePowersOfSecret(size, 2, bAlpha)
+
 	var commitKey CommitKey
 	var openKey OpeningKey
-	commitKey.G1 = make([]bls12381.G1Affine, size)
+	commitKey.G1 = g1s
+	openKey.GenG1 = g1s[0]
+	openKey.GenG2 = g2s[0]
+	openKey.AlphaG2 = g2s[1]
+
+	return &SRS{
+		CommitKey:  commitKey,
+		OpeningKey: openKey,
+	}, nil
+}
 
+// InsecurePowersOfSecret returns the first `numG1` powers of the secret `bAlpha` in G1, {alpha^i * G}, and the first
+// `numG2` powers in G2, {alpha^i * H}, where G and H are the generators of the groups.
+//
+// This is the monomial form of a trusted setup, and every insecure setup is generated with it.
+//
+// This method should not be used in production because as the secret is supplied as input.
+//
+// Adapted from [gnark-crypto].
+//
+// [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/kzg/kzg.go#L65
+func InsecurePowersOfSecret(numG1, numG2 uint64, bAlpha *big.Int) ([]bls12381.G1Affine, []bls12381.G2Affine) {
 	var alpha fr.Element
 	alpha.SetBigInt(bAlpha)
 
-	_, _, gen1Aff, gen2Aff := bls12381.Generators()
-	commitKey.G1[0] = gen1Aff
-	openKey.GenG1 = gen1Aff
-	openKey.GenG2 = gen2Aff
-	openKey.AlphaG2.ScalarMultiplication(&gen2Aff, bAlpha)
-
-	alphas := make([]fr.Element, size-1)
-	alphas[0] = alpha
-	for i := 1; i < len(alphas); i++ {
-		alphas[i].Mul(&alphas[i-1], &alpha)
+	powers := make([]fr.Element, max(numG1, numG2))
+	if len(powers) > 0 {
+		powers[0].SetOne()
+	}
+	for i := 1; i < len(powers); i++ {
+		powers[i].Mul(&powers[i-1], &alpha)
 	}
-	g1s := bls12381.BatchScalarMultiplicationG1(&gen1Aff, alphas)
-	copy(commitKey.G1[1:], g1s)
 
-	return &SRS{
-		CommitKey:  commitKey,
-		OpeningKey: openKey,
-	}, nil
+	_, _, gen1Aff, gen2Aff := bls12381.Generators()
+	g1s := bls12381.BatchScalarMultiplicationG1(&gen1Aff, powers[:numG1])
+	g2s := bls12381.BatchScalarMultiplicationG2(&gen2Aff, powers[:numG2])
+	return g1s, g2s
 }
@@ -2,4 +2,7 @@ package kzgmulti
 
 import "errors"
 
-var ErrMinSRSSize = errors.New("minimum srs size is 2")
+var (
+	ErrMinSRSSize                  = errors.New("minimum srs size is 2")
+	ErrInvalidSerializedOpeningKey = errors.New("serialized opening key is malformed")
+)
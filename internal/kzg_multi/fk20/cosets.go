@@ -0,0 +1,245 @@
+package fk20
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-eth-kzg/internal/domain"
+	"github.com/crate-crypto/go-eth-kzg/internal/multiexp"
+	"github.com/crate-crypto/go-eth-kzg/internal/utils"
+)
+
+// In this file we compute the evaluations and proofs for a subset of the cosets.
+//
+// FK20 computes the proofs for all of the cosets at once, and the extension FFT computes
+// the evaluations on all of them at once. When only a few cosets are needed, it is cheaper
+// to work on each coset on its own:
+//
+//   - Coset `i` is the subgroup `H` of order `evalSetSize`, shifted by `h_i`. Evaluating a
+//     polynomial `f` on it is the same as evaluating `f(h_i * X) mod (X^evalSetSize - 1)`
+//     on `H`, which only needs a single small coset FFT.
+//   - The proof for coset `i` is a commitment to the quotient of `f` by the vanishing
+//     polynomial of the coset, `X^evalSetSize - h_i^evalSetSize`. This needs a single MSM.
+
+// ComputeExtendedPolynomialForCosets returns the same evaluations as [FK20.ComputeExtendedPolynomial],
+// but only for the cosets at `cosetIndices`.
+//
+// Depending on the number of cosets, it either evaluates on each coset separately or evaluates
+// on all of them and picks out the ones that are needed, whichever is cheaper.
+//
+// It stops and returns ctx.Err() if `ctx` is cancelled. This is checked during the FFT over the
+// extended domain, when it is used.
+func (fk *FK20) ComputeExtendedPolynomialForCosets(ctx context.Context, polyCoeff []fr.Element, cosetIndices []uint64, numGoRoutines int) ([][]fr.Element, error) {
+	if fk.cosetEvaluationsAreCheaper(len(cosetIndices)) {
+		return fk.computeCosetEvaluations(polyCoeff, cosetIndices, numGoRoutines)
+	}
+
+	if err := fk.checkCosetIndices(polyCoeff, cosetIndices); err != nil {
+		return nil, err
+	}
+	allEvaluations, err := fk.ComputeExtendedPolynomial(ctx, polyCoeff, numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+	evaluations := make([][]fr.Element, len(cosetIndices))
+	for i, index := range cosetIndices {
+		evaluations[i] = allEvaluations[index]
+	}
+	return evaluations, nil
+}
+
+// ComputeMultiOpenProofForCosets returns the same proofs as [FK20.ComputeMultiOpenProof], but only
+// for the cosets at `cosetIndices`. `srs` holds the G1 points of the trusted setup in monomial form.
+//
+// Depending on the number of cosets, it either commits to the quotient for each coset separately
+// or computes all of the proofs with FK20 and picks out the ones that are needed, whichever is cheaper.
+func (fk *FK20) ComputeMultiOpenProofForCosets(ctx context.Context, polyCoeff []fr.Element, srs []bls12381.G1Affine, cosetIndices []uint64, numGoRoutines int) ([]bls12381.G1Affine, error) {
+	if fk.cosetProofsAreCheaper(len(cosetIndices)) {
+		return fk.computeCosetProofs(ctx, polyCoeff, srs, cosetIndices, numGoRoutines)
+	}
+
+	if err := fk.checkCosetIndices(polyCoeff, cosetIndices); err != nil {
+		return nil, err
+	}
+	allProofs, err := fk.ComputeMultiOpenProof(ctx, polyCoeff, numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+	proofs := make([]bls12381.G1Affine, len(cosetIndices))
+	for i, index := range cosetIndices {
+		proofs[i] = allProofs[index]
+	}
+	return proofs, nil
+}
+
+// cosetEvaluationsAreCheaper returns true if evaluating the polynomial on `numCosets` cosets
+// one at a time, with [FK20.computeCosetEvaluations], is cheaper than evaluating it on all of
+// them with [FK20.ComputeExtendedPolynomial].
+//
+// The cost of both approaches is counted in field multiplications.
+func (fk *FK20) cosetEvaluationsAreCheaper(numCosets int) bool {
+	polySize := fk.polySize
+	// Folding the polynomial, scaling by the coset generator and a small FFT
+	perCoset := polySize + fk.evalSetSize + fk.evalSetSize/2*log2(fk.evalSetSize)
+	extensionFFT := fk.numPointsToOpen / 2 * log2(fk.numPointsToOpen)
+
+	return numCosets*perCoset < extensionFFT
+}
+
+// cosetProofsAreCheaper returns true if computing the proofs for `numCosets` cosets one at a
+// time, with [FK20.computeCosetProofs], is cheaper than computing all of them with
+// [FK20.ComputeMultiOpenProof].
+//
+// The cost of both approaches is estimated in G1 scalar multiplications, taking an MSM over `n`
+// points to cost about `2n/log2(n)` of them. This is a rough model of a bucket MSM rather than a
+// measurement, so the crossover that it gives is only approximate. For the sizes that are used on
+// mainnet, it estimates FK20 to cost about as much as five quotient commitments.
+func (fk *FK20) cosetProofsAreCheaper(numCosets int) bool {
+	polySize := fk.polySize
+	numProofs := len(fk.proofDomain.Roots)
+	circulantSize := len(fk.batchMulAgg.transposedFFTFixedVectors)
+
+	perCoset := msmCost(polySize - fk.evalSetSize)
+	// The Toeplitz MSMs, the inverse FFT over the circulant domain and the FFT over the proof domain
+	fk20 := circulantSize*msmCost(fk.evalSetSize) +
+		circulantSize/2*log2(circulantSize) + circulantSize +
+		numProofs/2*log2(numProofs)
+
+	return numCosets*perCoset < fk20
+}
+
+// computeCosetEvaluations evaluates `polyCoeff` on the cosets at `cosetIndices`.
+//
+// The evaluations for each coset are the same as the corresponding ones returned by
+// [FK20.ComputeExtendedPolynomial], and are in the same bit-reversed order.
+//
+// Note: `polyCoeff` is not mutated in-place, ie it should be treated as a immutable reference.
+func (fk *FK20) computeCosetEvaluations(polyCoeff []fr.Element, cosetIndices []uint64, numGoRoutines int) ([][]fr.Element, error) {
+	if err := fk.checkCosetIndices(polyCoeff, cosetIndices); err != nil {
+		return nil, err
+	}
+
+	evaluations := make([][]fr.Element, len(cosetIndices))
+	utils.ParallelForEach(len(cosetIndices), numGoRoutines, func(i int) {
+		cosetGen := fk.cosetGenerator(cosetIndices[i])
+
+		// Fold f(h * X) modulo X^evalSetSize - 1. The coset FFT multiplies the j'th
+		// coefficient by h^j, so here we only need to account for the powers of h^evalSetSize.
+		var shift fr.Element
+		shift.Exp(cosetGen, big.NewInt(int64(fk.evalSetSize)))
+		folded := make([]fr.Element, fk.evalSetSize)
+		for start := len(polyCoeff) - fk.evalSetSize; start >= 0; start -= fk.evalSetSize {
+			for j := range folded {
+				folded[j].Mul(&folded[j], &shift)
+				folded[j].Add(&folded[j], &polyCoeff[start+j])
+			}
+		}
+
+		var fftCoset domain.FFTCoset
+		fftCoset.CosetGen = cosetGen
+		fftCoset.InvCosetGen.Inverse(&cosetGen)
+		domain.NewCosetDomain(&fk.cosetDomain, fftCoset).CosetFFtFr(folded)
+
+		domain.BitReverse(folded)
+		evaluations[i] = folded
+	})
+
+	return evaluations, nil
+}
+
+// computeCosetProofs computes the opening proofs for the cosets at `cosetIndices`, by committing
+// to each quotient with `srs`, which holds the G1 points of the trusted setup in monomial form.
+//
+// The proofs are the same as the corresponding ones returned by [FK20.ComputeMultiOpenProof].
+//
+// It stops and returns ctx.Err() if `ctx` is cancelled. This is checked before every MSM.
+//
+// Note: `polyCoeff` is not mutated in-place, ie it should be treated as a immutable reference.
+func (fk *FK20) computeCosetProofs(ctx context.Context, polyCoeff []fr.Element, srs []bls12381.G1Affine, cosetIndices []uint64, numGoRoutines int) ([]bls12381.G1Affine, error) {
+	if err := fk.checkCosetIndices(polyCoeff, cosetIndices); err != nil {
+		return nil, err
+	}
+	quotientLen := len(polyCoeff) - fk.evalSetSize
+	if len(srs) < quotientLen {
+		return nil, errors.New("the srs is too small to commit to the quotient polynomials")
+	}
+
+	// When there are more go-routines than cosets, the rest are used within each MSM
+	numGoRoutines = utils.ResolveNumGoRoutines(numGoRoutines)
+	numWorkers := max(1, min(numGoRoutines, len(cosetIndices)))
+	goRoutinesPerMSM := max(1, numGoRoutines/numWorkers)
+
+	proofs := make([]bls12381.G1Affine, len(cosetIndices))
+	errs := make([]error, len(cosetIndices))
+	utils.ParallelForEach(len(cosetIndices), numWorkers, func(i int) {
+		var vanishingConst fr.Element
+		cosetGen := fk.cosetGenerator(cosetIndices[i])
+		vanishingConst.Exp(cosetGen, big.NewInt(int64(fk.evalSetSize)))
+
+		// Divide by X^evalSetSize - c, starting from the highest coefficient:
+		// q_k = f_{k+evalSetSize} + c * q_{k+evalSetSize}
+		quotient := make([]fr.Element, quotientLen)
+		for k := quotientLen - 1; k >= 0; k-- {
+			quotient[k] = polyCoeff[k+fk.evalSetSize]
+			if k+fk.evalSetSize < quotientLen {
+				var tmp fr.Element
+				tmp.Mul(&vanishingConst, &quotient[k+fk.evalSetSize])
+				quotient[k].Add(&quotient[k], &tmp)
+			}
+		}
+
+		proof, err := multiexp.MultiExpG1Ctx(ctx, quotient, srs[:quotientLen], goRoutinesPerMSM)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		proofs[i] = *proof
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return proofs, nil
+}
+
+// checkCosetIndices checks that the polynomial has the size that this FK20 instance was created for,
+// and that every coset index refers to one of its cosets.
+func (fk *FK20) checkCosetIndices(polyCoeff []fr.Element, cosetIndices []uint64) error {
+	if len(polyCoeff) != fk.polySize {
+		return errors.New("the polynomial does not have the expected number of coefficients")
+	}
+	numCosets := uint64(len(fk.proofDomain.Roots))
+	for _, index := range cosetIndices {
+		if index >= numCosets {
+			return errors.New("coset index is out of range")
+		}
+	}
+	return nil
+}
+
+// cosetGenerator returns the element that the subgroup of order `evalSetSize` is shifted by to
+// give the coset at `cosetIndex`.
+//
+// The evaluations over the extension domain are in bit-reversed order, so coset `i` starts at
+// the root of unity with index `bitreverse(i * evalSetSize)`, which is the same as `bitreverse(i)`
+// in the smaller domain of coset generators.
+func (fk *FK20) cosetGenerator(cosetIndex uint64) fr.Element {
+	numCosets := uint64(len(fk.proofDomain.Roots))
+	return fk.extDomain.Roots[domain.BitReverseInt(cosetIndex, numCosets)]
+}
+
+// msmCost estimates the cost of an MSM over `n` points, in G1 scalar multiplications.
+func msmCost(n int) int {
+	return 2 * n / max(1, log2(n))
+}
+
+// log2 returns the base two logarithm of `n`, rounded down.
+func log2(n int) int {
+	return bits.Len(uint(n)) - 1
+}
@@ -1,6 +1,7 @@
 package fk20
 
 import (
+	"context"
 	"errors"
 	"slices"
 
@@ -18,6 +19,11 @@ type FK20 struct {
 
 	numPointsToOpen int
 	evalSetSize     int
+
+	// polySize is the number of coefficients in the polynomials that are opened.
+	polySize int
+	// cosetDomain is the subgroup whose cosets are opened. It has `evalSetSize` elements.
+	cosetDomain domain.Domain
 }
 
 func NewFK20(srs []bls12381.G1Affine, numPointsToOpen, evalSetSize int) FK20 {
@@ -25,6 +31,7 @@ func NewFK20(srs []bls12381.G1Affine, numPointsToOpen, evalSetSize int) FK20 {
 		panic("the evaluation set size should be a power of two. It is the size of each coset")
 	}
 
+	polySize := len(srs)
 	srs = slices.Clone(srs)
 
 	slices.Reverse(srs)
@@ -48,33 +55,70 @@ func NewFK20(srs []bls12381.G1Affine, numPointsToOpen, evalSetSize int) FK20 {
 		extDomain:       *extDomain,
 		numPointsToOpen: numPointsToOpen,
 		evalSetSize:     evalSetSize,
+		polySize:        polySize,
+		cosetDomain:     *domain.NewDomain(uint64(evalSetSize)),
 	}
 }
 
+// PolySize returns the number of coefficients in the polynomials that are opened.
+func (fk *FK20) PolySize() int {
+	return fk.polySize
+}
+
+// CosetSize returns the number of points in each coset that is opened.
+func (fk *FK20) CosetSize() int {
+	return fk.evalSetSize
+}
+
+// NumCosets returns the number of cosets that are opened, which is also the number of proofs.
+func (fk *FK20) NumCosets() int {
+	return int(fk.proofDomain.Cardinality)
+}
+
+// NumToeplitzMatrices returns the number of Toeplitz matrices whose fixed vectors have been precomputed.
+func (fk *FK20) NumToeplitzMatrices() int {
+	return fk.batchMulAgg.numMatrices()
+}
+
 // computeEvaluationSet evaluates `polyCoeff` on all of the cosets
 // that `ComputeMultiOpenProof` has created proofs for.
 //
 // Note: `polyCoeff` is not mutated in-place, ie it should be treated as a immutable reference.
-func (fk *FK20) computeEvaluationSet(polyCoeff []fr.Element) [][]fr.Element {
+func (fk *FK20) computeEvaluationSet(ctx context.Context, polyCoeff []fr.Element, numGoRoutines int) ([][]fr.Element, error) {
 	polyCoeff = slices.Clone(polyCoeff)
 	// Pad to the correct length
 	for i := len(polyCoeff); i < len(fk.extDomain.Roots); i++ {
 		polyCoeff = append(polyCoeff, fr.Element{})
 	}
 
-	fk.extDomain.FftFr(polyCoeff)
+	if err := fk.extDomain.FftFrCtx(ctx, polyCoeff, numGoRoutines); err != nil {
+		return nil, err
+	}
 	evaluations := polyCoeff
 
 	domain.BitReverse(evaluations)
-	return partition(evaluations, fk.evalSetSize)
+	return partition(evaluations, fk.evalSetSize), nil
 }
 
-func (fk *FK20) ComputeExtendedPolynomial(poly []fr.Element) [][]fr.Element {
-	return fk.computeEvaluationSet(poly)
+// ComputeExtendedPolynomial evaluates `poly` over the extended domain and groups the evaluations into cosets.
+//
+// It stops and returns ctx.Err() if `ctx` is cancelled. This is checked during the FFT.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (fk *FK20) ComputeExtendedPolynomial(ctx context.Context, poly []fr.Element, numGoRoutines int) ([][]fr.Element, error) {
+	return fk.computeEvaluationSet(ctx, poly, numGoRoutines)
 }
 
-func (fk *FK20) ComputeMultiOpenProof(poly []fr.Element) ([]bls12381.G1Affine, error) {
-	hComms, err := fk.computeHPolysComm(poly)
+// ComputeMultiOpenProof computes the opening proofs for every coset.
+//
+// It stops and returns ctx.Err() if `ctx` is cancelled. This is checked between the
+// steps of the algorithm, including before every MSM and during the G1 FFTs.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (fk *FK20) ComputeMultiOpenProof(ctx context.Context, poly []fr.Element, numGoRoutines int) ([]bls12381.G1Affine, error) {
+	hComms, err := fk.computeHPolysComm(ctx, poly, numGoRoutines)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +129,9 @@ func (fk *FK20) ComputeMultiOpenProof(poly []fr.Element) ([]bls12381.G1Affine, e
 		hComms = append(hComms, bls12381.G1Affine{})
 	}
 
-	fk.proofDomain.FftG1(hComms)
+	if err := fk.proofDomain.FftG1Ctx(ctx, hComms, numGoRoutines); err != nil {
+		return nil, err
+	}
 	proofs := hComms
 	domain.BitReverse(proofs)
 
@@ -97,7 +143,7 @@ func (fk *FK20) ComputeMultiOpenProof(poly []fr.Element) ([]bls12381.G1Affine, e
 // follows the FK20 paper.
 //
 // Note: `polyCoeff` is not mutated in-place, ie it should be treated as a immutable reference.
-func (fk *FK20) computeHPolysComm(polyCoeff []fr.Element) ([]bls12381.G1Affine, error) {
+func (fk *FK20) computeHPolysComm(ctx context.Context, polyCoeff []fr.Element, numGoRoutines int) ([]bls12381.G1Affine, error) {
 	if !utils.IsPowerOfTwo(uint64(len(polyCoeff))) {
 		return nil, errors.New("expected the polynomial to have power of two number of coefficients")
 	}
@@ -120,7 +166,7 @@ func (fk *FK20) computeHPolysComm(polyCoeff []fr.Element) ([]bls12381.G1Affine,
 		toeplitzMatrices[i] = newToeplitz(row, column)
 	}
 
-	return fk.batchMulAgg.BatchMulAggregation(toeplitzMatrices)
+	return fk.batchMulAgg.BatchMulAggregation(ctx, toeplitzMatrices, numGoRoutines)
 }
 
 func takeEveryNth[T any](list []T, n int) [][]T {
@@ -0,0 +1,111 @@
+package fk20
+
+import (
+	"errors"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+
+	"github.com/crate-crypto/go-eth-kzg/internal/codec"
+	"github.com/crate-crypto/go-eth-kzg/internal/domain"
+	"github.com/crate-crypto/go-eth-kzg/internal/utils"
+)
+
+var ErrInvalidSerializedFK20 = errors.New("serialized FK20 instance is malformed")
+
+// Serialize writes the FK20 instance, including the FFT'd SRS vectors, to `enc`.
+//
+// Computing these vectors requires G1 FFTs and is the most expensive part of
+// creating an FK20 instance.
+func (fk *FK20) Serialize(enc *codec.Encoder) {
+	fk.batchMulAgg.serialize(enc)
+	fk.proofDomain.Serialize(enc)
+	fk.extDomain.Serialize(enc)
+	enc.PutUint64(uint64(fk.numPointsToOpen))
+	enc.PutUint64(uint64(fk.evalSetSize))
+}
+
+// DeserializeFK20 reads an FK20 instance that was written using [FK20.Serialize].
+func DeserializeFK20(dec *codec.Decoder) (*FK20, error) {
+	batchMulAgg, err := deserializeBatchToeplitzMatrixVecMul(dec)
+	if err != nil {
+		return nil, err
+	}
+	proofDomain, err := domain.DeserializeDomain(dec)
+	if err != nil {
+		return nil, err
+	}
+	extDomain, err := domain.DeserializeDomain(dec)
+	if err != nil {
+		return nil, err
+	}
+	numPointsToOpen := dec.Uint64()
+	evalSetSize := dec.Uint64()
+	if err := dec.Err(); err != nil {
+		return nil, err
+	}
+
+	if !utils.IsPowerOfTwo(evalSetSize) || extDomain.Cardinality != numPointsToOpen ||
+		proofDomain.Cardinality != numPointsToOpen/evalSetSize {
+		return nil, ErrInvalidSerializedFK20
+	}
+
+	return &FK20{
+		batchMulAgg:     *batchMulAgg,
+		proofDomain:     *proofDomain,
+		extDomain:       *extDomain,
+		numPointsToOpen: int(numPointsToOpen),
+		evalSetSize:     int(evalSetSize),
+		// The Toeplitz matrices are embedded into circulant matrices of twice their size,
+		// and each of them has polySize/evalSetSize rows.
+		polySize:    len(batchMulAgg.transposedFFTFixedVectors) / 2 * int(evalSetSize),
+		cosetDomain: *domain.NewDomain(evalSetSize),
+	}, nil
+}
+
+func (bt *BatchToeplitzMatrixVecMul) serialize(enc *codec.Encoder) {
+	enc.PutUint64(uint64(len(bt.transposedFFTFixedVectors)))
+	for _, vector := range bt.transposedFFTFixedVectors {
+		enc.PutG1s(vector)
+	}
+	bt.circulantDomain.Serialize(enc)
+}
+
+func deserializeBatchToeplitzMatrixVecMul(dec *codec.Decoder) (*BatchToeplitzMatrixVecMul, error) {
+	numVectors := dec.Uint64()
+	if err := dec.Err(); err != nil {
+		return nil, err
+	}
+	// Each vector has at least a length prefix
+	if numVectors > uint64(dec.Remaining()/8) {
+		return nil, codec.ErrLengthTooLarge
+	}
+
+	transposedFFTFixedVectors := make([][]bls12381.G1Affine, numVectors)
+	for i := range transposedFFTFixedVectors {
+		transposedFFTFixedVectors[i] = dec.G1s()
+	}
+	if err := dec.Err(); err != nil {
+		return nil, err
+	}
+
+	circulantDomain, err := domain.DeserializeDomain(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	// BatchMulAggregation performs one MSM for every evaluation in the circulant domain,
+	// and each MSM has one point for every Toeplitz matrix
+	if uint64(len(transposedFFTFixedVectors)) != circulantDomain.Cardinality {
+		return nil, ErrInvalidSerializedFK20
+	}
+	for _, vector := range transposedFFTFixedVectors {
+		if len(vector) != len(transposedFFTFixedVectors[0]) {
+			return nil, ErrInvalidSerializedFK20
+		}
+	}
+
+	return &BatchToeplitzMatrixVecMul{
+		transposedFFTFixedVectors: transposedFFTFixedVectors,
+		circulantDomain:           *circulantDomain,
+	}, nil
+}
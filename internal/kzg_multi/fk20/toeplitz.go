@@ -1,6 +1,8 @@
 package fk20
 
 import (
+	"context"
+
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/crate-crypto/go-eth-kzg/internal/domain"
@@ -92,7 +94,19 @@ func newBatchToeplitzMatrixVecMul(fixedVectors [][]bls12381.G1Affine) BatchToepl
 	}
 }
 
-func (bt *BatchToeplitzMatrixVecMul) BatchMulAggregation(matrices []toeplitzMatrix) ([]bls12381.G1Affine, error) {
+// numMatrices returns the number of Toeplitz matrices that the fixed vectors are for.
+func (bt *BatchToeplitzMatrixVecMul) numMatrices() int {
+	return len(bt.transposedFFTFixedVectors[0])
+}
+
+// BatchMulAggregation multiplies each of the toeplitz matrices by its fixed vector and returns the sum of the results.
+//
+// It stops and returns ctx.Err() if `ctx` is cancelled. This is checked before every MSM and during the G1 FFT.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+// The MSMs are small, so rather than splitting up each MSM, they are spread over the go-routines.
+func (bt *BatchToeplitzMatrixVecMul) BatchMulAggregation(ctx context.Context, matrices []toeplitzMatrix, numGoRoutines int) ([]bls12381.G1Affine, error) {
 	// Convert toeplitz matrices into circulant matrices
 	circulantMatrices := make([]circulantMatrix, len(matrices))
 	for i := 0; i < len(matrices); i++ {
@@ -101,24 +115,37 @@ func (bt *BatchToeplitzMatrixVecMul) BatchMulAggregation(matrices []toeplitzMatr
 
 	// Compute FFT of circulant matrices rows
 	fftCirculantRows := make([][]fr.Element, len(matrices))
-	for i := 0; i < len(matrices); i++ {
-		bt.circulantDomain.FftFr(circulantMatrices[i].row)
-		fftCirculantRows[i] = circulantMatrices[i].row
-	}
+	utils.ParallelFor(len(matrices), numGoRoutines, func(start, end int) {
+		for i := start; i < end; i++ {
+			bt.circulantDomain.FftFr(circulantMatrices[i].row)
+			fftCirculantRows[i] = circulantMatrices[i].row
+		}
+	})
 
 	// Transpose rows converting the hadamard product(scalar multiplications) due to the Diagnol matrix
 	// into an inner product (MSM)
 	transposedFFTRows := transposeVectors(fftCirculantRows)
 	results := make([]bls12381.G1Affine, len(transposedFFTRows))
-	for i := 0; i < len(transposedFFTRows); i++ {
-		result, err := multiexp.MultiExpG1(transposedFFTRows[i], bt.transposedFFTFixedVectors[i], 0)
+	errs := make([]error, len(transposedFFTRows))
+	utils.ParallelFor(len(transposedFFTRows), numGoRoutines, func(start, end int) {
+		for i := start; i < end; i++ {
+			result, err := multiexp.MultiExpG1Ctx(ctx, transposedFFTRows[i], bt.transposedFFTFixedVectors[i], 1)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			results[i] = *result
+		}
+	})
+	for _, err := range errs {
 		if err != nil {
 			return nil, err
 		}
-		results[i] = *result
 	}
 
-	bt.circulantDomain.IfftG1(results)
+	if err := bt.circulantDomain.IfftG1Ctx(ctx, results, numGoRoutines); err != nil {
+		return nil, err
+	}
 	circulantSum := results
 
 	return circulantSum[:len(circulantSum)/2], nil
@@ -1,11 +1,19 @@
 package kzgmulti
 
 import (
+	"context"
+
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/crate-crypto/go-eth-kzg/internal/kzg_multi/fk20"
 	"github.com/crate-crypto/go-eth-kzg/internal/poly"
 )
 
-func ComputeMultiPointKZGProofs(fk20 *fk20.FK20, poly poly.PolynomialCoeff) ([]bls12381.G1Affine, error) {
-	return fk20.ComputeMultiOpenProof(poly)
+func ComputeMultiPointKZGProofs(ctx context.Context, fk20 *fk20.FK20, poly poly.PolynomialCoeff, numGoRoutines int) ([]bls12381.G1Affine, error) {
+	return fk20.ComputeMultiOpenProof(ctx, poly, numGoRoutines)
+}
+
+// ComputeMultiPointKZGProofsForCosets is the same as [ComputeMultiPointKZGProofs], but only computes the
+// proofs for the cosets at `cosetIndices`.
+func ComputeMultiPointKZGProofsForCosets(ctx context.Context, fk20 *fk20.FK20, commitKey *CommitKey, poly poly.PolynomialCoeff, cosetIndices []uint64, numGoRoutines int) ([]bls12381.G1Affine, error) {
+	return fk20.ComputeMultiOpenProofForCosets(ctx, poly, commitKey.G1, cosetIndices, numGoRoutines)
 }
@@ -1,6 +1,7 @@
 package kzgmulti
 
 import (
+	"context"
 	"math/big"
 	"slices"
 	"testing"
@@ -46,8 +47,9 @@ func TestNaiveVsOptimizedMultiPointKZGProofs(t *testing.T) {
 	require.NoError(t, err)
 
 	// Compute proofs using the optimized method
-	optimizedCosetsEvals := fk20Instance.ComputeExtendedPolynomial(slices.Clone(poly))
-	optimizedProofs, err := fk20Instance.ComputeMultiOpenProof(slices.Clone(poly))
+	optimizedCosetsEvals, err := fk20Instance.ComputeExtendedPolynomial(context.Background(), slices.Clone(poly), 0)
+	require.NoError(t, err)
+	optimizedProofs, err := fk20Instance.ComputeMultiOpenProof(context.Background(), slices.Clone(poly), 0)
 	require.NoError(t, err)
 
 	// Compare results
@@ -69,7 +71,10 @@ func TestNaiveVsOptimizedMultiPointKZGProofs(t *testing.T) {
 	}
 
 	commitmentIndices := make([]uint64, NUM_COSETS) // There is only one polynomial, so set the commitmentIndex to 0
-	err = VerifyMultiPointKZGProofBatch([]bls12381.G1Affine{*commitment}, commitmentIndices, cosetIndices, optimizedProofs, optimizedCosetsEvals, &srs.OpeningKey)
+	var r fr.Element
+	_, err = r.SetRandom()
+	require.NoError(t, err)
+	err = VerifyMultiPointKZGProofBatch(context.Background(), []bls12381.G1Affine{*commitment}, commitmentIndices, cosetIndices, optimizedProofs, optimizedCosetsEvals, r, &srs.OpeningKey)
 	assert.NoError(t, err, "Optimized proofs should verify correctly")
 }
 
@@ -136,3 +141,57 @@ func partition(slice []fr.Element, k int) [][]fr.Element {
 
 	return result
 }
+
+func TestMultiPointKZGProofsForCosets(t *testing.T) {
+	const EXTENSION_FACTOR = 2
+	const NUM_COEFFS_IN_POLY = 256
+	const COSET_SIZE = 16
+	const NUM_COSETS = NUM_COEFFS_IN_POLY * EXTENSION_FACTOR / COSET_SIZE
+
+	secret := big.NewInt(1234)
+	srs, err := newMonomialSRSInsecureUint64(NUM_COEFFS_IN_POLY, NUM_COEFFS_IN_POLY*EXTENSION_FACTOR, COSET_SIZE, secret)
+	require.NoError(t, err)
+	fk20Instance := fk20.NewFK20(srs.CommitKey.G1, NUM_COEFFS_IN_POLY*EXTENSION_FACTOR, COSET_SIZE)
+
+	poly := make([]fr.Element, NUM_COEFFS_IN_POLY)
+	for i := 0; i < NUM_COEFFS_IN_POLY; i++ {
+		poly[i].SetRandom()
+	}
+
+	allEvals, err := fk20Instance.ComputeExtendedPolynomial(context.Background(), poly, 0)
+	require.NoError(t, err)
+	allProofs, err := ComputeMultiPointKZGProofs(context.Background(), &fk20Instance, poly, 0)
+	require.NoError(t, err)
+
+	// Small sets are computed one coset at a time, while large sets fall back to computing everything
+	cosetIndexSets := [][]uint64{
+		{},
+		{0},
+		{NUM_COSETS - 1, 3, 3},
+		{1, 2, 5, 7, 11, 13, 17, 19, 23, 29},
+	}
+	everyCoset := make([]uint64, NUM_COSETS)
+	for i := range everyCoset {
+		everyCoset[i] = uint64(i)
+	}
+	cosetIndexSets = append(cosetIndexSets, everyCoset)
+
+	for _, cosetIndices := range cosetIndexSets {
+		evals, err := fk20Instance.ComputeExtendedPolynomialForCosets(context.Background(), poly, cosetIndices, 0)
+		require.NoError(t, err)
+		proofs, err := ComputeMultiPointKZGProofsForCosets(context.Background(), &fk20Instance, &srs.CommitKey, poly, cosetIndices, 0)
+		require.NoError(t, err)
+
+		require.Len(t, evals, len(cosetIndices))
+		require.Len(t, proofs, len(cosetIndices))
+		for i, index := range cosetIndices {
+			require.Equal(t, allEvals[index], evals[i], "coset evaluation %d of %v", index, cosetIndices)
+			require.True(t, allProofs[index].Equal(&proofs[i]), "proof %d of %v", index, cosetIndices)
+		}
+	}
+
+	_, err = fk20Instance.ComputeExtendedPolynomialForCosets(context.Background(), poly, []uint64{NUM_COSETS}, 0)
+	require.Error(t, err)
+	_, err = ComputeMultiPointKZGProofsForCosets(context.Background(), &fk20Instance, &srs.CommitKey, poly, []uint64{NUM_COSETS}, 0)
+	require.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package kzgmulti
 
 import (
+	"context"
+
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/crate-crypto/go-eth-kzg/internal/domain"
@@ -12,24 +14,23 @@ import (
 
 // Verifies Multiple KZGProofs
 //
+// Powers of `r` are used to combine the proofs into one. It should either be sampled at random,
+// or be derived from all of the inputs using Fiat-Shamir, as is done in the spec.
+//
+// It stops and returns ctx.Err() if `ctx` is cancelled. This is checked before every MSM,
+// during the coset IFFTs and before the final pairing check.
+//
 // Note: `cosetEvals` is mutated in-place, ie it should be treated as a mutable reference
-func VerifyMultiPointKZGProofBatch(deduplicatedCommitments []bls12381.G1Affine, commitmentIndices, cosetIndices []uint64, proofs []bls12381.G1Affine, cosetEvals [][]fr.Element, openKey *OpeningKey) error {
-	// Sample random numbers for sampling.
-	//
-	// We only need to sample one random number and
-	// compute powers of that random number. This works
+func VerifyMultiPointKZGProofBatch(ctx context.Context, deduplicatedCommitments []bls12381.G1Affine, commitmentIndices, cosetIndices []uint64, proofs []bls12381.G1Affine, cosetEvals [][]fr.Element, r fr.Element, openKey *OpeningKey) error {
+	// We only need one random number and compute
+	// powers of that random number. This works
 	// since powers will produce a vandermonde matrix
 	// which is linearly independent.
-	var r fr.Element
-	_, err := r.SetRandom()
-	if err != nil {
-		return err
-	}
 	rPowers := utils.ComputePowers(r, uint(len(commitmentIndices)))
 
 	numCosets := len(cosetIndices)
 	numUniqueCommitments := len(deduplicatedCommitments)
-	commRandomSumProofs, err := multiexp.MultiExpG1(rPowers, proofs, 0)
+	commRandomSumProofs, err := multiexp.MultiExpG1Ctx(ctx, rPowers, proofs, 0)
 	if err != nil {
 		return err
 	}
@@ -39,7 +40,7 @@ func VerifyMultiPointKZGProofBatch(deduplicatedCommitments []bls12381.G1Affine,
 		commitmentIndex := commitmentIndices[k]
 		weights[commitmentIndex].Add(&weights[commitmentIndex], &rPowers[k])
 	}
-	commRandomSumComms, err := multiexp.MultiExpG1(weights, deduplicatedCommitments, 0)
+	commRandomSumComms, err := multiexp.MultiExpG1Ctx(ctx, weights, deduplicatedCommitments, 0)
 	if err != nil {
 		return err
 	}
@@ -49,6 +50,10 @@ func VerifyMultiPointKZGProofBatch(deduplicatedCommitments []bls12381.G1Affine,
 	// Compute random linear sum of interpolation polynomials
 	interpolationPoly := []fr.Element{}
 	for k, cosetEval := range cosetEvals {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		domain.BitReverse(cosetEval)
 
 		// Coset IFFT
@@ -65,7 +70,7 @@ func VerifyMultiPointKZGProofBatch(deduplicatedCommitments []bls12381.G1Affine,
 		interpolationPoly = poly.PolyAdd(interpolationPoly, cosetMonomial)
 	}
 
-	commRandomSumInterPoly, err := openKey.CommitG1(interpolationPoly)
+	commRandomSumInterPoly, err := openKey.CommitG1(ctx, interpolationPoly)
 	if err != nil {
 		return err
 	}
@@ -77,7 +82,7 @@ func VerifyMultiPointKZGProofBatch(deduplicatedCommitments []bls12381.G1Affine,
 		cosetShiftPowN := openKey.CosetShiftsPowCosetSize[cosetIndex]
 		weightedRPowers[k].Mul(&cosetShiftPowN, &rPower)
 	}
-	randomWeightedSumProofs, err := multiexp.MultiExpG1(weightedRPowers, proofs, 0)
+	randomWeightedSumProofs, err := multiexp.MultiExpG1Ctx(ctx, weightedRPowers, proofs, 0)
 	if err != nil {
 		return err
 	}
@@ -91,6 +96,10 @@ func VerifyMultiPointKZGProofBatch(deduplicatedCommitments []bls12381.G1Affine,
 
 	sPowCosetSize := openKey.G2[cosetSize]
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	check, err := bls12381.PairingCheck(
 		[]bls12381.G1Affine{*commRandomSumProofs, rl},
 		[]bls12381.G2Affine{sPowCosetSize, negG2Gen},
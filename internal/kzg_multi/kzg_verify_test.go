@@ -1,6 +1,7 @@
 package kzgmulti
 
 import (
+	"context"
 	"math/big"
 	"testing"
 
@@ -26,8 +27,9 @@ func TestProveVerify(t *testing.T) {
 	for i := 0; i < NUM_COEFFS_IN_POLY; i++ {
 		poly[i].SetBigInt(big.NewInt(int64(i)))
 	}
-	cosetsEvals := fk20Instance.ComputeExtendedPolynomial(poly)
-	proofs, err := fk20Instance.ComputeMultiOpenProof(poly)
+	cosetsEvals, err := fk20Instance.ComputeExtendedPolynomial(context.Background(), poly, 0)
+	assert.NoError(t, err)
+	proofs, err := fk20Instance.ComputeMultiOpenProof(context.Background(), poly, 0)
 	assert.True(t, len(cosetsEvals[0]) == COSET_SIZE)
 	assert.NoError(t, err)
 	commitment, err := srs.CommitKey.Commit(poly, 0)
@@ -38,6 +40,9 @@ func TestProveVerify(t *testing.T) {
 		cosetIndices[k] = uint64(k)
 	}
 	commitmentIndices := make([]uint64, 128)
-	err = VerifyMultiPointKZGProofBatch([]bls12381.G1Affine{*commitment}, commitmentIndices, cosetIndices, proofs, cosetsEvals, &srs.OpeningKey)
+	var r fr.Element
+	_, err = r.SetRandom()
+	assert.NoError(t, err)
+	err = VerifyMultiPointKZGProofBatch(context.Background(), []bls12381.G1Affine{*commitment}, commitmentIndices, cosetIndices, proofs, cosetsEvals, r, &srs.OpeningKey)
 	assert.NoError(t, err)
 }
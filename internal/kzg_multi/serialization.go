@@ -0,0 +1,49 @@
+package kzgmulti
+
+import (
+	"github.com/crate-crypto/go-eth-kzg/internal/codec"
+	"github.com/crate-crypto/go-eth-kzg/internal/domain"
+)
+
+// Serialize writes the opening key, including the precomputed coset domains, to `enc`.
+func (ok *OpeningKey) Serialize(enc *codec.Encoder) {
+	enc.PutG1s(ok.G1)
+	enc.PutG2s(ok.G2)
+	enc.PutUint64(ok.CosetSize)
+	enc.PutUint64(ok.PolySize)
+	enc.PutUint64(ok.NumPointsToOpen)
+	enc.PutFrs(ok.CosetShiftsPowCosetSize)
+	domain.SerializeCosetDomains(enc, ok.cosetDomains)
+}
+
+// DeserializeOpeningKey reads an opening key that was written using [OpeningKey.Serialize].
+func DeserializeOpeningKey(dec *codec.Decoder) (*OpeningKey, error) {
+	ok := &OpeningKey{
+		G1:                      dec.G1s(),
+		G2:                      dec.G2s(),
+		CosetSize:               dec.Uint64(),
+		PolySize:                dec.Uint64(),
+		NumPointsToOpen:         dec.Uint64(),
+		CosetShiftsPowCosetSize: dec.Frs(),
+	}
+	if err := dec.Err(); err != nil {
+		return nil, err
+	}
+
+	cosetDomains, err := domain.DeserializeCosetDomains(dec)
+	if err != nil {
+		return nil, err
+	}
+	ok.cosetDomains = cosetDomains
+
+	// The verifier indexes into these using the coset index and
+	// uses G2[CosetSize], so we check that they are consistent.
+	if ok.CosetSize == 0 || ok.NumPointsToOpen%ok.CosetSize != 0 ||
+		uint64(len(ok.cosetDomains)) != ok.NumPointsToOpen/ok.CosetSize ||
+		len(ok.CosetShiftsPowCosetSize) != len(ok.cosetDomains) ||
+		uint64(len(ok.G2)) <= ok.CosetSize {
+		return nil, ErrInvalidSerializedOpeningKey
+	}
+
+	return ok, nil
+}
@@ -1,6 +1,7 @@
 package kzgmulti
 
 import (
+	"context"
 	"errors"
 	"math/big"
 
@@ -108,30 +109,18 @@ func (o *OpeningKey) genG2() *bls12381.G2Affine {
 	return &o.G2[0]
 }
 
-// This method has been copied and modified from kzg/srs.go
+// newMonomialSRSInsecureUint64 creates a new SRS object with the secret `bAlpha`, that can open polynomials of
+// `polySize` coefficients on cosets of `cosetSize` points.
 // It is only used for testing, so this is okay.
 func newMonomialSRSInsecureUint64(polySize, numPointsToOpen, cosetSize uint64, bAlpha *big.Int) (*SRS, error) {
 	if polySize < 2 {
 		return nil, ErrMinSRSSize
 	}
 
+	g1s, g2s := kzg.InsecurePowersOfSecret(polySize, polySize, bAlpha)
+
 	var commitKey CommitKey
 	commitKey.G1 = make([]bls12381.G1Affine, polySize)
-
-	var alpha fr.Element
-	alpha.SetBigInt(bAlpha)
-
-	_, _, gen1Aff, gen2Aff := bls12381.Generators()
-
-	alphas := make([]fr.Element, polySize)
-	alphas[0] = fr.NewElement(1)
-	alphas[1] = alpha
-
-	for i := 2; i < len(alphas); i++ {
-		alphas[i].Mul(&alphas[i-1], &alpha)
-	}
-	g1s := bls12381.BatchScalarMultiplicationG1(&gen1Aff, alphas)
-	g2s := bls12381.BatchScalarMultiplicationG2(&gen2Aff, alphas)
 	copy(commitKey.G1, g1s)
 
 	return &SRS{
@@ -140,12 +129,12 @@ func newMonomialSRSInsecureUint64(polySize, numPointsToOpen, cosetSize uint64, b
 	}, nil
 }
 
-func (ok *OpeningKey) CommitG1(scalars []fr.Element) (*bls12381.G1Affine, error) {
+func (ok *OpeningKey) CommitG1(ctx context.Context, scalars []fr.Element) (*bls12381.G1Affine, error) {
 	if len(scalars) == 0 || len(scalars) > len(ok.G1) {
 		return nil, errors.New("invalid vector size for G1 commitment")
 	}
 
-	return multiexp.MultiExpG1(scalars, ok.G1[:len(scalars)], 0)
+	return multiexp.MultiExpG1Ctx(ctx, scalars, ok.G1[:len(scalars)], 0)
 }
 
 func (ok *OpeningKey) CommitG2(scalars []fr.Element) (*bls12381.G2Affine, error) {
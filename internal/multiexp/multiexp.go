@@ -1,6 +1,8 @@
 package multiexp
 
 import (
+	"context"
+
 	"github.com/consensys/gnark-crypto/ecc"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -25,6 +27,18 @@ func MultiExpG1(scalars []fr.Element, points []bls12381.G1Affine, numGoRoutines
 	return new(bls12381.G1Affine).MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: numGoRoutines})
 }
 
+// MultiExpG1Ctx is the same as [MultiExpG1], but returns ctx.Err() instead of computing the
+// multi exponentiation if `ctx` has been cancelled.
+//
+// Note: A multi exponentiation that has already started cannot be interrupted. Callers that perform
+// many of them should use this method for each one, so that they stop at the next one.
+func MultiExpG1Ctx(ctx context.Context, scalars []fr.Element, points []bls12381.G1Affine, numGoRoutines int) (*bls12381.G1Affine, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return MultiExpG1(scalars, points, numGoRoutines)
+}
+
 func MultiExpG2(scalars []fr.Element, points []bls12381.G2Affine, numGoRoutines int) (*bls12381.G2Affine, error) {
 	err := isValidNumGoRoutines(numGoRoutines)
 	if err != nil {
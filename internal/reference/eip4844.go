@@ -0,0 +1,290 @@
+package reference
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// fiatShamirProtocolDomain matches FIAT_SHAMIR_PROTOCOL_DOMAIN in the spec.
+const fiatShamirProtocolDomain = "FSBLOBVERIFY_V1_"
+
+// blobToPolynomial matches [blob_to_polynomial] in the spec.
+//
+// [blob_to_polynomial]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob_to_polynomial
+func (c *Context) blobToPolynomial(blob []byte) ([]fr.Element, error) {
+	if len(blob) != c.fieldElementsPerBlob*bytesPerFieldElement {
+		return nil, ErrInvalidLength
+	}
+	polynomial := make([]fr.Element, c.fieldElementsPerBlob)
+	for i := range polynomial {
+		element, err := bytesToBLSField(blob[i*bytesPerFieldElement : (i+1)*bytesPerFieldElement])
+		if err != nil {
+			return nil, err
+		}
+		polynomial[i] = element
+	}
+	return polynomial, nil
+}
+
+// polynomialEvalToCoeff matches [polynomial_eval_to_coeff] in the spec. The spec uses an inverse FFT, whereas this
+// computes the inverse discrete Fourier transform directly from its definition, in quadratic time.
+//
+// [polynomial_eval_to_coeff]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#polynomial_eval_to_coeff
+func (c *Context) polynomialEvalToCoeff(polynomial []fr.Element) []fr.Element {
+	n := len(polynomial)
+	roots := bitReversalPermutation(c.blobRootsBRP)
+	evals := bitReversalPermutation(polynomial)
+
+	var inverseN fr.Element
+	inverseN.SetUint64(uint64(n))
+	inverseN.Inverse(&inverseN)
+
+	// coeffs[j] = 1/n * sum_k evals[k] * roots[k]^-j, and roots[k]^-j = roots[(-j*k) mod n]
+	coeffs := make([]fr.Element, n)
+	for j := range coeffs {
+		for k := range evals {
+			var term fr.Element
+			term.Mul(&evals[k], &roots[(n-j*k%n)%n])
+			coeffs[j].Add(&coeffs[j], &term)
+		}
+		coeffs[j].Mul(&coeffs[j], &inverseN)
+	}
+	return coeffs
+}
+
+// computeChallenge matches [compute_challenge] in the spec.
+//
+// [compute_challenge]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_challenge
+func (c *Context) computeChallenge(blob, commitment []byte) fr.Element {
+	// Append the degree of the polynomial as a domain separator, as a 16 byte big-endian integer
+	degreePoly := make([]byte, 16)
+	binary.BigEndian.PutUint64(degreePoly[8:], uint64(c.fieldElementsPerBlob))
+
+	data := []byte(fiatShamirProtocolDomain)
+	data = append(data, degreePoly...)
+	data = append(data, blob...)
+	data = append(data, commitment...)
+
+	return hashToBLSField(data)
+}
+
+// hashToBLSField matches [hash_to_bls_field] in the spec.
+//
+// [hash_to_bls_field]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#hash_to_bls_field
+func hashToBLSField(data []byte) fr.Element {
+	hashedData := sha256.Sum256(data)
+	value := new(big.Int).SetBytes(hashedData[:])
+	value.Mod(value, fr.Modulus())
+
+	var element fr.Element
+	element.SetBigInt(value)
+	return element
+}
+
+// evaluatePolynomialInEvaluationForm matches [evaluate_polynomial_in_evaluation_form] in the spec, which uses the
+// barycentric formula.
+//
+// [evaluate_polynomial_in_evaluation_form]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#evaluate_polynomial_in_evaluation_form
+func (c *Context) evaluatePolynomialInEvaluationForm(polynomial []fr.Element, z fr.Element) fr.Element {
+	width := len(polynomial)
+	var inverseWidth fr.Element
+	inverseWidth.SetUint64(uint64(width))
+	inverseWidth.Inverse(&inverseWidth)
+
+	// If we are asked to evaluate within the domain, we already know the answer
+	for i := range c.blobRootsBRP {
+		if c.blobRootsBRP[i].Equal(&z) {
+			return polynomial[i]
+		}
+	}
+
+	var result fr.Element
+	for i := 0; i < width; i++ {
+		var a, b fr.Element
+		a.Mul(&polynomial[i], &c.blobRootsBRP[i])
+		b.Sub(&z, &c.blobRootsBRP[i])
+		b.Inverse(&b)
+		a.Mul(&a, &b)
+		result.Add(&result, &a)
+	}
+
+	var r, one fr.Element
+	one.SetOne()
+	r.Exp(z, big.NewInt(int64(width)))
+	r.Sub(&r, &one)
+	result.Mul(&result, &r)
+	result.Mul(&result, &inverseWidth)
+	return result
+}
+
+// BlobToKZGCommitment matches [blob_to_kzg_commitment] in the spec.
+//
+// [blob_to_kzg_commitment]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob_to_kzg_commitment
+func (c *Context) BlobToKZGCommitment(blob []byte) ([]byte, error) {
+	polynomial, err := c.blobToPolynomial(blob)
+	if err != nil {
+		return nil, err
+	}
+	commitment := g1Lincomb(bitReversalPermutation(c.setup.G1Lagrange), polynomial)
+	return g1ToBytes(&commitment), nil
+}
+
+// ComputeKZGProof matches [compute_kzg_proof] in the spec. It returns the proof and the value of the polynomial at
+// `z`.
+//
+// [compute_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_kzg_proof
+func (c *Context) ComputeKZGProof(blob, zBytes []byte) ([]byte, []byte, error) {
+	polynomial, err := c.blobToPolynomial(blob)
+	if err != nil {
+		return nil, nil, err
+	}
+	z, err := bytesToBLSField(zBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proof, y := c.computeKZGProofImpl(polynomial, z)
+	return g1ToBytes(&proof), fieldToBytes(&y), nil
+}
+
+// computeKZGProofImpl matches [compute_kzg_proof_impl] in the spec.
+//
+// The spec computes the quotient in evaluation form, with a special case for when `z` is one of the roots of unity.
+// Here the quotient is computed with long division in coefficient form, which needs no special case, and committed
+// to with the monomial setup.
+//
+// [compute_kzg_proof_impl]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_kzg_proof_impl
+func (c *Context) computeKZGProofImpl(polynomial []fr.Element, z fr.Element) (bls12381.G1Affine, fr.Element) {
+	polynomialCoeff := c.polynomialEvalToCoeff(polynomial)
+	y := evaluatePolynomialCoeff(polynomialCoeff, z)
+
+	// (p(X) - y) / (X - z)
+	polynomialShifted := append([]fr.Element(nil), polynomialCoeff...)
+	polynomialShifted[0].Sub(&polynomialShifted[0], &y)
+	var negZ fr.Element
+	negZ.Neg(&z)
+	quotientPolynomial := dividePolynomialCoeff(polynomialShifted, linearPolynomialCoeff(negZ))
+
+	return g1Lincomb(c.setup.G1Monomial, quotientPolynomial), y
+}
+
+// ComputeBlobKZGProof matches [compute_blob_kzg_proof] in the spec.
+//
+// [compute_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_blob_kzg_proof
+func (c *Context) ComputeBlobKZGProof(blob, commitmentBytes []byte) ([]byte, error) {
+	if _, err := bytesToG1(commitmentBytes); err != nil {
+		return nil, err
+	}
+	polynomial, err := c.blobToPolynomial(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluationChallenge := c.computeChallenge(blob, commitmentBytes)
+	proof, _ := c.computeKZGProofImpl(polynomial, evaluationChallenge)
+	return g1ToBytes(&proof), nil
+}
+
+// VerifyKZGProof matches [verify_kzg_proof] in the spec.
+//
+// [verify_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof
+func (c *Context) VerifyKZGProof(commitmentBytes, zBytes, yBytes, proofBytes []byte) (bool, error) {
+	commitment, err := bytesToG1(commitmentBytes)
+	if err != nil {
+		return false, err
+	}
+	z, err := bytesToBLSField(zBytes)
+	if err != nil {
+		return false, err
+	}
+	y, err := bytesToBLSField(yBytes)
+	if err != nil {
+		return false, err
+	}
+	proof, err := bytesToG1(proofBytes)
+	if err != nil {
+		return false, err
+	}
+
+	return c.verifyKZGProofImpl(commitment, z, y, proof), nil
+}
+
+// verifyKZGProofImpl matches [verify_kzg_proof_impl] in the spec.
+//
+// [verify_kzg_proof_impl]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof_impl
+func (c *Context) verifyKZGProofImpl(commitment bls12381.G1Affine, z, y fr.Element, proof bls12381.G1Affine) bool {
+	_, _, genG1, genG2 := bls12381.Generators()
+
+	// Verify: P - y = Q * (X - z)
+	var xMinusZ, zG2 bls12381.G2Affine
+	zG2.ScalarMultiplication(&genG2, z.BigInt(new(big.Int)))
+	xMinusZ.Sub(&c.setup.G2Monomial[1], &zG2)
+
+	var pMinusY, yG1 bls12381.G1Affine
+	yG1.ScalarMultiplication(&genG1, y.BigInt(new(big.Int)))
+	pMinusY.Sub(&commitment, &yG1)
+
+	var negG2 bls12381.G2Affine
+	negG2.Neg(&genG2)
+	return pairingCheck([]bls12381.G1Affine{pMinusY, proof}, []bls12381.G2Affine{negG2, xMinusZ})
+}
+
+// VerifyBlobKZGProof matches [verify_blob_kzg_proof] in the spec.
+//
+// [verify_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof
+func (c *Context) VerifyBlobKZGProof(blob, commitmentBytes, proofBytes []byte) (bool, error) {
+	commitment, err := bytesToG1(commitmentBytes)
+	if err != nil {
+		return false, err
+	}
+	polynomial, err := c.blobToPolynomial(blob)
+	if err != nil {
+		return false, err
+	}
+	proof, err := bytesToG1(proofBytes)
+	if err != nil {
+		return false, err
+	}
+
+	evaluationChallenge := c.computeChallenge(blob, commitmentBytes)
+	y := c.evaluatePolynomialInEvaluationForm(polynomial, evaluationChallenge)
+	return c.verifyKZGProofImpl(commitment, evaluationChallenge, y, proof), nil
+}
+
+// VerifyBlobKZGProofBatch matches [verify_blob_kzg_proof_batch] in the spec.
+//
+// The spec combines the proofs with a random linear combination. Here each proof is verified on its own, after all
+// of the inputs have been checked.
+//
+// [verify_blob_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
+func (c *Context) VerifyBlobKZGProofBatch(blobs, commitmentsBytes, proofsBytes [][]byte) (bool, error) {
+	if len(blobs) != len(commitmentsBytes) || len(blobs) != len(proofsBytes) {
+		return false, ErrBatchLength
+	}
+
+	for i := range blobs {
+		if _, err := c.blobToPolynomial(blobs[i]); err != nil {
+			return false, err
+		}
+		if _, err := bytesToG1(commitmentsBytes[i]); err != nil {
+			return false, err
+		}
+		if _, err := bytesToG1(proofsBytes[i]); err != nil {
+			return false, err
+		}
+	}
+
+	valid := true
+	for i := range blobs {
+		ok, err := c.VerifyBlobKZGProof(blobs[i], commitmentsBytes[i], proofsBytes[i])
+		if err != nil {
+			return false, err
+		}
+		valid = valid && ok
+	}
+	return valid, nil
+}
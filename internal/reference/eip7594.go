@@ -0,0 +1,212 @@
+package reference
+
+import (
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// cellToCosetEvals matches [cell_to_coset_evals] in the spec.
+//
+// [cell_to_coset_evals]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#cell_to_coset_evals
+func (c *Context) cellToCosetEvals(cell []byte) ([]fr.Element, error) {
+	if len(cell) != c.fieldElementsPerCell*bytesPerFieldElement {
+		return nil, ErrInvalidLength
+	}
+	evals := make([]fr.Element, c.fieldElementsPerCell)
+	for i := range evals {
+		element, err := bytesToBLSField(cell[i*bytesPerFieldElement : (i+1)*bytesPerFieldElement])
+		if err != nil {
+			return nil, err
+		}
+		evals[i] = element
+	}
+	return evals, nil
+}
+
+// cosetEvalsToCell matches [coset_evals_to_cell] in the spec.
+//
+// [coset_evals_to_cell]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#coset_evals_to_cell
+func cosetEvalsToCell(cosetEvals []fr.Element) []byte {
+	cell := make([]byte, 0, len(cosetEvals)*bytesPerFieldElement)
+	for i := range cosetEvals {
+		cell = append(cell, fieldToBytes(&cosetEvals[i])...)
+	}
+	return cell
+}
+
+// cosetForCell matches [coset_for_cell] in the spec: it returns the points that the cell at `cellIndex` holds the
+// evaluations of the polynomial at.
+//
+// [coset_for_cell]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#coset_for_cell
+func (c *Context) cosetForCell(cellIndex uint64) []fr.Element {
+	return c.extRootsBRP[int(cellIndex)*c.fieldElementsPerCell : (int(cellIndex)+1)*c.fieldElementsPerCell]
+}
+
+// computeKZGProofMultiImpl matches [compute_kzg_proof_multi_impl] in the spec. It returns the proof for the values of
+// the polynomial at `zs`, and those values.
+//
+// [compute_kzg_proof_multi_impl]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_kzg_proof_multi_impl
+func (c *Context) computeKZGProofMultiImpl(polynomialCoeff, zs []fr.Element) (bls12381.G1Affine, []fr.Element) {
+	// For all points, compute the evaluation of those points
+	ys := make([]fr.Element, len(zs))
+	for i := range zs {
+		ys[i] = evaluatePolynomialCoeff(polynomialCoeff, zs[i])
+	}
+
+	// Compute the quotient polynomial directly in monomial form
+	denominatorPoly := vanishingPolynomialCoeff(zs)
+	quotientPolynomial := dividePolynomialCoeff(polynomialCoeff, denominatorPoly)
+
+	return g1Lincomb(c.setup.G1Monomial, quotientPolynomial), ys
+}
+
+// verifyKZGProofMultiImpl checks a proof that the polynomial committed to by `commitment` takes the values `ys` at
+// the points `zs`, by checking that
+//
+//	e(proof, [Z(s)]) = e(commitment - [I(s)], [1])
+//
+// where Z is the vanishing polynomial of `zs` and I is the polynomial that interpolates `ys`. The batched check in
+// [verify_cell_kzg_proof_batch_impl] is a random linear combination of this check for each cell.
+//
+// [verify_cell_kzg_proof_batch_impl]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#verify_cell_kzg_proof_batch_impl
+func (c *Context) verifyKZGProofMultiImpl(commitment bls12381.G1Affine, zs, ys []fr.Element, proof bls12381.G1Affine) bool {
+	// Compute [Z(X)]_2
+	zeroPoly := g2Lincomb(c.setup.G2Monomial, vanishingPolynomialCoeff(zs))
+	// Compute [I(X)]_1
+	interpolatedPoly := g1Lincomb(c.setup.G1Monomial, interpolatePolynomialCoeff(zs, ys))
+
+	var commitmentMinusInterpolation bls12381.G1Affine
+	commitmentMinusInterpolation.Sub(&commitment, &interpolatedPoly)
+	var negG2 bls12381.G2Affine
+	negG2.Neg(&c.setup.G2Monomial[0])
+
+	return pairingCheck(
+		[]bls12381.G1Affine{proof, commitmentMinusInterpolation},
+		[]bls12381.G2Affine{zeroPoly, negG2},
+	)
+}
+
+// computeCellsAndKZGProofsPolynomialCoeff matches [compute_cells_and_kzg_proofs_polynomialcoeff] in the spec.
+//
+// [compute_cells_and_kzg_proofs_polynomialcoeff]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_cells_and_kzg_proofs_polynomialcoeff
+func (c *Context) computeCellsAndKZGProofsPolynomialCoeff(polynomialCoeff []fr.Element) ([][]byte, [][]byte) {
+	cells := make([][]byte, c.cellsPerExtBlob)
+	proofs := make([][]byte, c.cellsPerExtBlob)
+	for i := range cells {
+		coset := c.cosetForCell(uint64(i))
+		proof, ys := c.computeKZGProofMultiImpl(polynomialCoeff, coset)
+		cells[i] = cosetEvalsToCell(ys)
+		proofs[i] = g1ToBytes(&proof)
+	}
+	return cells, proofs
+}
+
+// ComputeCellsAndKZGProofs matches [compute_cells_and_kzg_proofs] in the spec.
+//
+// [compute_cells_and_kzg_proofs]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_cells_and_kzg_proofs
+func (c *Context) ComputeCellsAndKZGProofs(blob []byte) ([][]byte, [][]byte, error) {
+	polynomial, err := c.blobToPolynomial(blob)
+	if err != nil {
+		return nil, nil, err
+	}
+	polynomialCoeff := c.polynomialEvalToCoeff(polynomial)
+
+	cells, proofs := c.computeCellsAndKZGProofsPolynomialCoeff(polynomialCoeff)
+	return cells, proofs, nil
+}
+
+// VerifyCellKZGProofBatch matches [verify_cell_kzg_proof_batch] in the spec.
+//
+// The spec combines the proofs with a random linear combination. Here each proof is verified on its own, after all
+// of the inputs have been checked.
+//
+// [verify_cell_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#verify_cell_kzg_proof_batch
+func (c *Context) VerifyCellKZGProofBatch(commitmentsBytes [][]byte, cellIndices []uint64, cells, proofsBytes [][]byte) (bool, error) {
+	if len(commitmentsBytes) != len(cells) || len(cells) != len(proofsBytes) || len(proofsBytes) != len(cellIndices) {
+		return false, ErrBatchLength
+	}
+	for _, cellIndex := range cellIndices {
+		if cellIndex >= uint64(c.cellsPerExtBlob) {
+			return false, ErrInvalidCellIndex
+		}
+	}
+
+	commitments := make([]bls12381.G1Affine, len(commitmentsBytes))
+	for i := range commitmentsBytes {
+		commitment, err := bytesToG1(commitmentsBytes[i])
+		if err != nil {
+			return false, err
+		}
+		commitments[i] = commitment
+	}
+	cosetsEvals := make([][]fr.Element, len(cells))
+	for i := range cells {
+		cosetEvals, err := c.cellToCosetEvals(cells[i])
+		if err != nil {
+			return false, err
+		}
+		cosetsEvals[i] = cosetEvals
+	}
+	proofs := make([]bls12381.G1Affine, len(proofsBytes))
+	for i := range proofsBytes {
+		proof, err := bytesToG1(proofsBytes[i])
+		if err != nil {
+			return false, err
+		}
+		proofs[i] = proof
+	}
+
+	valid := true
+	for i := range cells {
+		coset := c.cosetForCell(cellIndices[i])
+		valid = valid && c.verifyKZGProofMultiImpl(commitments[i], coset, cosetsEvals[i], proofs[i])
+	}
+	return valid, nil
+}
+
+// RecoverCellsAndKZGProofs matches [recover_cells_and_kzg_proofs] in the spec.
+//
+// The spec recovers the polynomial with FFTs and the vanishing polynomial of the missing cells. Here it is
+// interpolated from the first FIELD_ELEMENTS_PER_BLOB of the evaluations that were given. Both give the same result
+// when the cells are evaluations of a single blob polynomial, which is the only case in which the result is
+// meaningful.
+//
+// [recover_cells_and_kzg_proofs]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#recover_cells_and_kzg_proofs
+func (c *Context) RecoverCellsAndKZGProofs(cellIndices []uint64, cells [][]byte) ([][]byte, [][]byte, error) {
+	// Check we have the same number of cells and indices
+	if len(cellIndices) != len(cells) {
+		return nil, nil, ErrBatchLength
+	}
+	// Check we have enough cells to be able to perform the reconstruction
+	if len(cellIndices)*c.fieldElementsPerCell < c.fieldElementsPerBlob || len(cellIndices) > c.cellsPerExtBlob {
+		return nil, nil, ErrInvalidNumCellsRecover
+	}
+	// Check that the indices are unique and in ascending order
+	for i := 1; i < len(cellIndices); i++ {
+		if cellIndices[i] <= cellIndices[i-1] {
+			return nil, nil, ErrCellIndicesNotOrdered
+		}
+	}
+	// Check that the cell indices are within bounds
+	for _, cellIndex := range cellIndices {
+		if cellIndex >= uint64(c.cellsPerExtBlob) {
+			return nil, nil, ErrInvalidCellIndex
+		}
+	}
+
+	// Convert cells to coset evaluations, along with the points they are evaluations at
+	var xs, ys []fr.Element
+	for i := range cells {
+		cosetEvals, err := c.cellToCosetEvals(cells[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		xs = append(xs, c.cosetForCell(cellIndices[i])...)
+		ys = append(ys, cosetEvals...)
+	}
+
+	polynomialCoeff := interpolatePolynomialCoeff(xs[:c.fieldElementsPerBlob], ys[:c.fieldElementsPerBlob])
+
+	cells, proofs := c.computeCellsAndKZGProofsPolynomialCoeff(polynomialCoeff)
+	return cells, proofs, nil
+}
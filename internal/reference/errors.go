@@ -0,0 +1,14 @@
+package reference
+
+import "errors"
+
+var (
+	ErrInvalidSetup           = errors.New("trusted setup does not match the parameters")
+	ErrInvalidLength          = errors.New("input does not have the expected length")
+	ErrInvalidFieldElement    = errors.New("field element is not canonical")
+	ErrInvalidG1Point         = errors.New("bytes are not a valid G1 point")
+	ErrBatchLength            = errors.New("batch inputs do not have the same length")
+	ErrInvalidCellIndex       = errors.New("cell index is out of range")
+	ErrCellIndicesNotOrdered  = errors.New("cell indices are not unique and in ascending order")
+	ErrInvalidNumCellsRecover = errors.New("number of cells is not enough to recover the blob, or too many")
+)
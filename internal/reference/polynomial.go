@@ -0,0 +1,141 @@
+package reference
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The polynomials in this file are in coefficient form, with the coefficient of the lowest degree first. This
+// matches PolynomialCoeff in the spec.
+
+// addPolynomialCoeff matches [add_polynomialcoeff] in the spec.
+//
+// [add_polynomialcoeff]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#add_polynomialcoeff
+func addPolynomialCoeff(a, b []fr.Element) []fr.Element {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	result := make([]fr.Element, len(a))
+	for i := range a {
+		result[i] = a[i]
+		if i < len(b) {
+			result[i].Add(&result[i], &b[i])
+		}
+	}
+	return result
+}
+
+// multiplyPolynomialCoeff matches [multiply_polynomialcoeff] in the spec.
+//
+// [multiply_polynomialcoeff]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#multiply_polynomialcoeff
+func multiplyPolynomialCoeff(a, b []fr.Element) []fr.Element {
+	result := make([]fr.Element, len(a)+len(b)-1)
+	for i := range a {
+		for j := range b {
+			var term fr.Element
+			term.Mul(&a[i], &b[j])
+			result[i+j].Add(&result[i+j], &term)
+		}
+	}
+	return result
+}
+
+// dividePolynomialCoeff matches [divide_polynomialcoeff] in the spec: it is long division, and the remainder is
+// dropped.
+//
+// [divide_polynomialcoeff]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#divide_polynomialcoeff
+func dividePolynomialCoeff(a, b []fr.Element) []fr.Element {
+	a = append([]fr.Element(nil), a...)
+	apos := len(a) - 1
+	bpos := len(b) - 1
+	diff := apos - bpos
+	if diff < 0 {
+		return nil
+	}
+
+	var leadInv fr.Element
+	leadInv.Inverse(&b[bpos])
+	o := make([]fr.Element, diff+1)
+	for ; diff >= 0; diff-- {
+		var quot fr.Element
+		quot.Mul(&a[apos], &leadInv)
+		o[diff] = quot
+		for i := bpos; i >= 0; i-- {
+			var term fr.Element
+			term.Mul(&b[i], &quot)
+			a[diff+i].Sub(&a[diff+i], &term)
+		}
+		apos--
+	}
+	return o
+}
+
+// evaluatePolynomialCoeff matches [evaluate_polynomialcoeff] in the spec, which uses Horner's method.
+//
+// [evaluate_polynomialcoeff]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#evaluate_polynomialcoeff
+func evaluatePolynomialCoeff(polynomialCoeff []fr.Element, z fr.Element) fr.Element {
+	var y fr.Element
+	for i := len(polynomialCoeff) - 1; i >= 0; i-- {
+		y.Mul(&y, &z)
+		y.Add(&y, &polynomialCoeff[i])
+	}
+	return y
+}
+
+// vanishingPolynomialCoeff matches [vanishing_polynomialcoeff] in the spec: it is the product of (X - x) for every x
+// in `xs`.
+//
+// [vanishing_polynomialcoeff]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#vanishing_polynomialcoeff
+func vanishingPolynomialCoeff(xs []fr.Element) []fr.Element {
+	p := make([]fr.Element, len(xs)+1)
+	p[0].SetOne()
+
+	// Multiply by (X - x) in place
+	for degree, x := range xs {
+		for i := degree + 1; i > 0; i-- {
+			var term fr.Element
+			term.Mul(&p[i], &x)
+			p[i].Sub(&p[i-1], &term)
+		}
+		p[0].Mul(&p[0], &x)
+		p[0].Neg(&p[0])
+	}
+	return p
+}
+
+// linearPolynomialCoeff returns X + constant.
+func linearPolynomialCoeff(constant fr.Element) []fr.Element {
+	p := []fr.Element{constant, {}}
+	p[1].SetOne()
+	return p
+}
+
+// interpolatePolynomialCoeff matches [interpolate_polynomialcoeff] in the spec: it returns the polynomial of degree
+// less than len(xs) that takes the values `ys` at the points `xs`.
+//
+// The spec multiplies out each Lagrange basis polynomial, which takes cubic time. Here each basis polynomial is
+// instead the vanishing polynomial of `xs` divided by (X - xs[i]) and scaled, which takes quadratic time and is fast
+// enough to interpolate a mainnet blob.
+//
+// [interpolate_polynomialcoeff]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#interpolate_polynomialcoeff
+func interpolatePolynomialCoeff(xs, ys []fr.Element) []fr.Element {
+	vanishing := vanishingPolynomialCoeff(xs)
+
+	r := make([]fr.Element, len(xs))
+	for i := range xs {
+		var negX fr.Element
+		negX.Neg(&xs[i])
+		basis := dividePolynomialCoeff(vanishing, linearPolynomialCoeff(negX))
+
+		// basis(xs[i]) is the product of (xs[i] - xs[j]) for j != i
+		weight := evaluatePolynomialCoeff(basis, xs[i])
+		weight.Inverse(&weight)
+		weight.Mul(&weight, &ys[i])
+
+		for k := range basis {
+			var term fr.Element
+			term.Mul(&basis[k], &weight)
+			r[k].Add(&r[k], &term)
+		}
+	}
+	return r
+}
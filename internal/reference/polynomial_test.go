@@ -0,0 +1,71 @@
+package reference
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func randPoly(t *testing.T, n int) []fr.Element {
+	t.Helper()
+	p := make([]fr.Element, n)
+	for i := range p {
+		_, err := p[i].SetRandom()
+		require.NoError(t, err)
+	}
+	return p
+}
+
+func TestDividePolynomialCoeff(t *testing.T) {
+	quotient := randPoly(t, 10)
+	divisor := randPoly(t, 4)
+
+	// The remainder is dropped
+	dividend := addPolynomialCoeff(multiplyPolynomialCoeff(quotient, divisor), randPoly(t, 3))
+	require.Equal(t, quotient, dividePolynomialCoeff(dividend, divisor))
+
+	require.Empty(t, dividePolynomialCoeff(randPoly(t, 2), divisor))
+}
+
+func TestVanishingPolynomialCoeff(t *testing.T) {
+	xs := randPoly(t, 8)
+	vanishing := vanishingPolynomialCoeff(xs)
+	require.Len(t, vanishing, len(xs)+1)
+
+	for _, x := range xs {
+		y := evaluatePolynomialCoeff(vanishing, x)
+		require.True(t, y.IsZero())
+	}
+	x := randPoly(t, 1)[0]
+	y := evaluatePolynomialCoeff(vanishing, x)
+	require.False(t, y.IsZero())
+}
+
+func TestInterpolatePolynomialCoeff(t *testing.T) {
+	p := randPoly(t, 16)
+	xs := randPoly(t, len(p))
+	ys := make([]fr.Element, len(xs))
+	for i := range xs {
+		ys[i] = evaluatePolynomialCoeff(p, xs[i])
+	}
+	require.Equal(t, p, interpolatePolynomialCoeff(xs, ys))
+}
+
+func TestRootsOfUnity(t *testing.T) {
+	const order = 16
+	roots := computeRootsOfUnity(order)
+	require.True(t, roots[0].IsOne())
+
+	// The next power wraps around, and no smaller power does
+	var power fr.Element
+	power.Mul(&roots[order-1], &roots[1])
+	require.True(t, power.IsOne())
+	for i := 1; i < order; i++ {
+		require.False(t, roots[i].IsOne())
+	}
+
+	brp := bitReversalPermutation(roots)
+	require.Equal(t, roots[order/2], brp[1])
+	require.Equal(t, roots, bitReversalPermutation(brp))
+}
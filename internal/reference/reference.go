@@ -0,0 +1,225 @@
+// Package reference is a slow, straightforward implementation of the KZG functions in the consensus-specs, which is
+// used to test the optimized code in the rest of this module.
+//
+// Each function mirrors the spec's Python function of the same name. Where the spec only describes a result, such as
+// recovering a polynomial from its cells, this package computes it in the most direct way: polynomials are divided
+// with long division, commitments and proofs are computed with a single MSM over the monomial setup, and recovery
+// is Lagrange interpolation. None of the internal packages of this module are used, so that a bug in the optimized
+// code cannot be hidden by the same bug here.
+//
+// Every assert in the spec returns an error. Functions that verify a proof return false, and no error, when the
+// inputs are well-formed but the proof does not verify.
+//
+// This package is only meant for tests. It is not constant time and is orders of magnitude slower than the rest of
+// this module.
+//
+// The spec functions can be found in [deneb] and [fulu].
+//
+// [deneb]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md
+// [fulu]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md
+package reference
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// bytesPerFieldElement matches BYTES_PER_FIELD_ELEMENT in the spec.
+	bytesPerFieldElement = 32
+	// bytesPerG1 is the size of a compressed G1 point, which is the size of a commitment and a proof.
+	bytesPerG1 = 48
+	// primitiveRootOfUnity matches PRIMITIVE_ROOT_OF_UNITY in the spec.
+	primitiveRootOfUnity = 7
+)
+
+// Setup is a trusted setup, with the points in the order in which they are stored in the trusted setup file.
+type Setup struct {
+	// G1Monomial matches KZG_SETUP_G1_MONOMIAL in the spec.
+	G1Monomial []bls12381.G1Affine
+	// G1Lagrange matches KZG_SETUP_G1_LAGRANGE in the spec.
+	G1Lagrange []bls12381.G1Affine
+	// G2Monomial matches KZG_SETUP_G2_MONOMIAL in the spec.
+	G2Monomial []bls12381.G2Affine
+}
+
+// Context holds the trusted setup and the roots of unity that the spec functions use.
+type Context struct {
+	setup Setup
+
+	fieldElementsPerBlob    int
+	fieldElementsPerCell    int
+	fieldElementsPerExtBlob int
+	cellsPerExtBlob         int
+
+	// blobRootsBRP are the roots of unity that the blob polynomial is evaluated at, in bit-reversed order.
+	blobRootsBRP []fr.Element
+	// extRootsBRP are the roots of unity that the extended blob polynomial is evaluated at, in bit-reversed order.
+	extRootsBRP []fr.Element
+}
+
+// NewContext returns a Context for blobs of len(setup.G1Lagrange) field elements, which are extended by
+// `expansionFactor` and split into cells of `fieldElementsPerCell` field elements.
+//
+// The spec fixes the expansion factor to 2. Other values are supported so that contexts with other parameters can be
+// tested too.
+func NewContext(setup Setup, fieldElementsPerCell, expansionFactor int) (*Context, error) {
+	fieldElementsPerBlob := len(setup.G1Lagrange)
+	if !isPowerOfTwo(fieldElementsPerBlob) || !isPowerOfTwo(fieldElementsPerCell) || !isPowerOfTwo(expansionFactor) {
+		return nil, ErrInvalidSetup
+	}
+	if expansionFactor < 2 || fieldElementsPerCell > fieldElementsPerBlob {
+		return nil, ErrInvalidSetup
+	}
+	if len(setup.G1Monomial) != fieldElementsPerBlob || len(setup.G2Monomial) < fieldElementsPerCell+1 {
+		return nil, ErrInvalidSetup
+	}
+
+	fieldElementsPerExtBlob := fieldElementsPerBlob * expansionFactor
+	return &Context{
+		setup:                   setup,
+		fieldElementsPerBlob:    fieldElementsPerBlob,
+		fieldElementsPerCell:    fieldElementsPerCell,
+		fieldElementsPerExtBlob: fieldElementsPerExtBlob,
+		cellsPerExtBlob:         fieldElementsPerExtBlob / fieldElementsPerCell,
+		blobRootsBRP:            bitReversalPermutation(computeRootsOfUnity(fieldElementsPerBlob)),
+		extRootsBRP:             bitReversalPermutation(computeRootsOfUnity(fieldElementsPerExtBlob)),
+	}, nil
+}
+
+func isPowerOfTwo(value int) bool {
+	return value > 0 && value&(value-1) == 0
+}
+
+// reverseBits matches [reverse_bits] in the spec.
+//
+// [reverse_bits]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#reverse_bits
+func reverseBits(n, order int) int {
+	return int(bits.Reverse64(uint64(n)) >> (64 - bits.TrailingZeros64(uint64(order))))
+}
+
+// bitReversalPermutation matches [bit_reversal_permutation] in the spec.
+//
+// [bit_reversal_permutation]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#bit_reversal_permutation
+func bitReversalPermutation[T any](sequence []T) []T {
+	permuted := make([]T, len(sequence))
+	for i := range sequence {
+		permuted[i] = sequence[reverseBits(i, len(sequence))]
+	}
+	return permuted
+}
+
+// computeRootsOfUnity matches [compute_roots_of_unity] in the spec.
+//
+// [compute_roots_of_unity]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_roots_of_unity
+func computeRootsOfUnity(order int) []fr.Element {
+	exponent := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	exponent.Div(exponent, big.NewInt(int64(order)))
+
+	var root fr.Element
+	root.SetUint64(primitiveRootOfUnity)
+	root.Exp(root, exponent)
+
+	return computePowers(root, order)
+}
+
+// computePowers matches [compute_powers] in the spec.
+//
+// [compute_powers]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_powers
+func computePowers(x fr.Element, n int) []fr.Element {
+	powers := make([]fr.Element, n)
+	var currentPower fr.Element
+	currentPower.SetOne()
+	for i := range powers {
+		powers[i] = currentPower
+		currentPower.Mul(&currentPower, &x)
+	}
+	return powers
+}
+
+// bytesToBLSField matches [bytes_to_bls_field] in the spec.
+//
+// [bytes_to_bls_field]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#bytes_to_bls_field
+func bytesToBLSField(b []byte) (fr.Element, error) {
+	if len(b) != bytesPerFieldElement {
+		return fr.Element{}, ErrInvalidLength
+	}
+	fieldElement := new(big.Int).SetBytes(b)
+	if fieldElement.Cmp(fr.Modulus()) >= 0 {
+		return fr.Element{}, ErrInvalidFieldElement
+	}
+
+	var element fr.Element
+	element.SetBigInt(fieldElement)
+	return element, nil
+}
+
+// bytesToG1 matches [bytes_to_kzg_commitment] and [bytes_to_kzg_proof] in the spec, which both call
+// [validate_kzg_g1].
+//
+// The point at infinity is valid, and every other point has to be in the G1 subgroup.
+//
+// [bytes_to_kzg_commitment]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#bytes_to_kzg_commitment
+// [bytes_to_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#bytes_to_kzg_proof
+// [validate_kzg_g1]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#validate_kzg_g1
+func bytesToG1(b []byte) (bls12381.G1Affine, error) {
+	if len(b) != bytesPerG1 {
+		return bls12381.G1Affine{}, ErrInvalidLength
+	}
+	var point bls12381.G1Affine
+	if _, err := point.SetBytes(b); err != nil {
+		return bls12381.G1Affine{}, ErrInvalidG1Point
+	}
+	return point, nil
+}
+
+// g1ToBytes serializes a G1 point in compressed form.
+func g1ToBytes(point *bls12381.G1Affine) []byte {
+	byts := point.Bytes()
+	return byts[:]
+}
+
+// fieldToBytes serializes a field element as a big-endian integer.
+func fieldToBytes(element *fr.Element) []byte {
+	return element.BigInt(new(big.Int)).FillBytes(make([]byte, bytesPerFieldElement))
+}
+
+// g1Lincomb matches [g1_lincomb] in the spec.
+//
+// Like the spec, this uses the multi-exponentiation of the curve library, since a loop of scalar multiplications is
+// too slow to compute every cell proof of a mainnet blob.
+//
+// [g1_lincomb]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#g1_lincomb
+func g1Lincomb(points []bls12381.G1Affine, scalars []fr.Element) bls12381.G1Affine {
+	var result bls12381.G1Affine
+	if len(scalars) == 0 {
+		return result
+	}
+	if _, err := result.MultiExp(points[:len(scalars)], scalars, ecc.MultiExpConfig{}); err != nil {
+		// This only fails for mismatched lengths, which the slicing above rules out
+		panic(err)
+	}
+	return result
+}
+
+// g2Lincomb is the same as [g1Lincomb] for G2 points.
+func g2Lincomb(points []bls12381.G2Affine, scalars []fr.Element) bls12381.G2Affine {
+	var result bls12381.G2Affine
+	if len(scalars) == 0 {
+		return result
+	}
+	if _, err := result.MultiExp(points[:len(scalars)], scalars, ecc.MultiExpConfig{}); err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// pairingCheck returns true when the product of the pairings of each pair of points is the identity.
+func pairingCheck(g1Points []bls12381.G1Affine, g2Points []bls12381.G2Affine) bool {
+	ok, err := bls12381.PairingCheck(g1Points, g2Points)
+	return err == nil && ok
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ResolveNumGoRoutines returns the number of go-routines that should be used for a
+// concurrency setting of `numGoRoutines`.
+//
+// Following the convention of gnark-crypto, a value of 0 or below means that
+// one go-routine should be used for each CPU.
+func ResolveNumGoRoutines(numGoRoutines int) int {
+	if numGoRoutines <= 0 {
+		return runtime.NumCPU()
+	}
+	return numGoRoutines
+}
+
+// ParallelFor splits the range [0, n) into at most `numGoRoutines` contiguous chunks
+// and calls `work` on each of them in its own go-routine. It returns once all of the
+// calls have returned.
+//
+// `numGoRoutines` is resolved with [ResolveNumGoRoutines]. If it resolves to one, or
+// there is only a single item, then `work` is called on the current go-routine.
+func ParallelFor(n, numGoRoutines int, work func(start, end int)) {
+	if n <= 0 {
+		return
+	}
+
+	numChunks := min(ResolveNumGoRoutines(numGoRoutines), n)
+	if numChunks == 1 {
+		work(0, n)
+		return
+	}
+
+	chunkSize := (n + numChunks - 1) / numChunks
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunkSize {
+		end := min(start+chunkSize, n)
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			work(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// ParallelForEach calls `work` once for each index in [0, n), using a pool of at most
+// `numGoRoutines` go-routines. It returns once all of the calls have returned.
+//
+// Unlike [ParallelFor], the indices are handed out one at a time, so that every
+// go-routine stays busy even when n is not a multiple of the number of go-routines.
+func ParallelForEach(n, numGoRoutines int, work func(i int)) {
+	if n <= 0 {
+		return
+	}
+
+	numWorkers := min(ResolveNumGoRoutines(numGoRoutines), n)
+	if numWorkers == 1 {
+		for i := 0; i < n; i++ {
+			work(i)
+		}
+		return
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				work(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"math"
 	"math/big"
+	"sync/atomic"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -144,3 +145,37 @@ func randReducedBigInt() big.Int {
 
 	return randBigInt
 }
+
+func TestParallelFor(t *testing.T) {
+	for _, numGoRoutines := range []int{-1, 0, 1, 3, 8, 100} {
+		for _, n := range []int{0, 1, 7, 64} {
+			visited := make([]int, n)
+			ParallelFor(n, numGoRoutines, func(start, end int) {
+				for i := start; i < end; i++ {
+					visited[i]++
+				}
+			})
+			for i, count := range visited {
+				if count != 1 {
+					t.Fatalf("index %d was visited %d times for n=%d, numGoRoutines=%d", i, count, n, numGoRoutines)
+				}
+			}
+		}
+	}
+}
+
+func TestParallelForEach(t *testing.T) {
+	for _, numGoRoutines := range []int{-1, 0, 1, 3, 8, 100} {
+		for _, n := range []int{0, 1, 7, 64} {
+			visited := make([]atomic.Int32, n)
+			ParallelForEach(n, numGoRoutines, func(i int) {
+				visited[i].Add(1)
+			})
+			for i := range visited {
+				if count := visited[i].Load(); count != 1 {
+					t.Fatalf("index %d was visited %d times for n=%d, numGoRoutines=%d", i, count, n, numGoRoutines)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package goethkzg
+
+import (
+	"fmt"
+
+	"github.com/crate-crypto/go-eth-kzg/internal/utils"
+)
+
+// Params holds the sizes of the blobs and cells that a [Context] works with.
+//
+// [MainnetParams] holds the values that are used on Ethereum mainnet. Other values are useful for devnets and research
+// simulations, where smaller blobs or a larger expansion factor are needed.
+//
+// The fixed-size [Blob] and [Cell] types can only hold data for [MainnetParams]. A Context that was created with other
+// parameters should be used with the methods ending in `Bytes`, such as [Context.BlobToKZGCommitmentBytes]. These take
+// blobs and cells as byte slices, whose lengths are given by [Params.BytesPerBlob] and [Params.BytesPerCell].
+type Params struct {
+	// FieldElementsPerBlob is the number of field elements in a blob.
+	//
+	// It matches [FIELD_ELEMENTS_PER_BLOB] in the spec.
+	//
+	// [FIELD_ELEMENTS_PER_BLOB]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob
+	FieldElementsPerBlob uint64
+	// FieldElementsPerCell is the number of field elements in a cell.
+	//
+	// It matches [FIELD_ELEMENTS_PER_CELL] in the spec.
+	//
+	// [FIELD_ELEMENTS_PER_CELL]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#cells
+	FieldElementsPerCell uint64
+	// ExpansionFactor is the factor by which a blob is extended before it is split into cells.
+	ExpansionFactor uint64
+}
+
+// MainnetParams are the parameters used on Ethereum mainnet.
+//
+// These are the only parameters that the fixed-size [Blob] and [Cell] types can be used with.
+var MainnetParams = Params{
+	FieldElementsPerBlob: ScalarsPerBlob,
+	FieldElementsPerCell: scalarsPerCell,
+	ExpansionFactor:      expansionFactor,
+}
+
+// maxFieldElementsPerExtBlob bounds the size of the extended blob, so that the sizes we derive
+// from the parameters can never overflow.
+const maxFieldElementsPerExtBlob = 1 << 28
+
+// validate checks that a Context can be created for these parameters.
+//
+// All sizes need to be powers of two, since they are used as the sizes of FFT domains, and a
+// blob needs to be split over at least two cells, so that the FK20 proofs are well-defined.
+func (p Params) validate() error {
+	if p.FieldElementsPerBlob < 2 || !utils.IsPowerOfTwo(p.FieldElementsPerBlob) {
+		return fmt.Errorf("%w: field elements per blob must be a power of two that is at least 2", ErrInvalidParams)
+	}
+	if p.FieldElementsPerCell == 0 || !utils.IsPowerOfTwo(p.FieldElementsPerCell) {
+		return fmt.Errorf("%w: field elements per cell must be a power of two", ErrInvalidParams)
+	}
+	if p.FieldElementsPerCell >= p.FieldElementsPerBlob {
+		return fmt.Errorf("%w: field elements per cell must be less than the field elements per blob", ErrInvalidParams)
+	}
+	if p.ExpansionFactor < 2 || !utils.IsPowerOfTwo(p.ExpansionFactor) {
+		return fmt.Errorf("%w: expansion factor must be a power of two that is at least 2", ErrInvalidParams)
+	}
+	if p.FieldElementsPerBlob > maxFieldElementsPerExtBlob/p.ExpansionFactor {
+		return fmt.Errorf("%w: extended blob must have at most %d field elements", ErrInvalidParams, maxFieldElementsPerExtBlob)
+	}
+	return nil
+}
+
+// FieldElementsPerExtBlob returns the number of field elements in an extended blob.
+func (p Params) FieldElementsPerExtBlob() uint64 {
+	return p.FieldElementsPerBlob * p.ExpansionFactor
+}
+
+// CellsPerExtBlob returns the number of cells in an extended blob.
+func (p Params) CellsPerExtBlob() uint64 {
+	return p.FieldElementsPerExtBlob() / p.FieldElementsPerCell
+}
+
+// BytesPerBlob returns the number of bytes in a serialized blob.
+func (p Params) BytesPerBlob() int {
+	return int(p.FieldElementsPerBlob) * SerializedScalarSize
+}
+
+// BytesPerCell returns the number of bytes in a serialized cell.
+func (p Params) BytesPerCell() int {
+	return int(p.FieldElementsPerCell) * SerializedScalarSize
+}
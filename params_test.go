@@ -0,0 +1,276 @@
+package goethkzg_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/crate-crypto/go-eth-kzg/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// smallParams are used to test contexts that are not created for mainnet.
+// They use a 4x expansion, so that recovery needs a quarter of the cells.
+var smallParams = goethkzg.Params{
+	FieldElementsPerBlob: 256,
+	FieldElementsPerCell: 16,
+	ExpansionFactor:      4,
+}
+
+// newInsecureSetup creates a trusted setup with a known secret for `numG1` G1 points and `numG2` G2 points.
+func newInsecureSetup(secret int64, numG1, numG2 int) *goethkzg.JSONTrustedSetup {
+	_, _, genG1, genG2 := bls12381.Generators()
+
+	var alpha fr.Element
+	alpha.SetInt64(secret)
+	powers := make([]fr.Element, max(numG1, numG2))
+	powers[0].SetOne()
+	for i := 1; i < len(powers); i++ {
+		powers[i].Mul(&powers[i-1], &alpha)
+	}
+
+	monomialG1 := bls12381.BatchScalarMultiplicationG1(&genG1, powers[:numG1])
+	monomialG2 := bls12381.BatchScalarMultiplicationG2(&genG2, powers[:numG2])
+
+	lagrangeG1 := make([]bls12381.G1Affine, numG1)
+	copy(lagrangeG1, monomialG1)
+	domain.NewDomain(uint64(numG1)).IfftG1(lagrangeG1)
+
+	setup := &goethkzg.JSONTrustedSetup{
+		SetupG2:         make([]goethkzg.G2CompressedHexStr, numG2),
+		SetupG1Lagrange: make([]goethkzg.G1CompressedHexStr, numG1),
+		SetupG1Monomial: make([]goethkzg.G1CompressedHexStr, numG1),
+	}
+	for i := 0; i < numG1; i++ {
+		monomial := monomialG1[i].Bytes()
+		lagrange := lagrangeG1[i].Bytes()
+		setup.SetupG1Monomial[i] = "0x" + hex.EncodeToString(monomial[:])
+		setup.SetupG1Lagrange[i] = "0x" + hex.EncodeToString(lagrange[:])
+	}
+	for i := 0; i < numG2; i++ {
+		point := monomialG2[i].Bytes()
+		setup.SetupG2[i] = "0x" + hex.EncodeToString(point[:])
+	}
+	return setup
+}
+
+func getRandBlobBytes(params goethkzg.Params, seed int64) []byte {
+	blob := make([]byte, params.BytesPerBlob())
+	for i := 0; i < len(blob); i += goethkzg.SerializedScalarSize {
+		fieldElementBytes := GetRandFieldElement(seed + int64(i))
+		copy(blob[i:], fieldElementBytes[:])
+	}
+	return blob
+}
+
+func TestNewContextWithParams(t *testing.T) {
+	setup := newInsecureSetup(1337, int(smallParams.FieldElementsPerBlob), int(smallParams.FieldElementsPerCell)+1)
+	smallCtx, err := goethkzg.NewContextWithParams(setup, smallParams)
+	require.NoError(t, err)
+	require.Equal(t, smallParams, smallCtx.Params())
+
+	blob := getRandBlobBytes(smallParams, 1)
+
+	// EIP-4844
+	commitment, err := smallCtx.BlobToKZGCommitmentBytes(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	blobProof, err := smallCtx.ComputeBlobKZGProofBytes(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, smallCtx.VerifyBlobKZGProofBytes(blob, commitment, blobProof))
+	require.NoError(t, smallCtx.VerifyBlobKZGProofBatchBytes([][]byte{blob, blob}, []goethkzg.KZGCommitment{commitment, commitment}, []goethkzg.KZGProof{blobProof, blobProof}))
+
+	inputPoint := GetRandFieldElement(2)
+	proof, claimedValue, err := smallCtx.ComputeKZGProofBytes(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, smallCtx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof))
+
+	otherBlob := getRandBlobBytes(smallParams, 3)
+	require.Error(t, smallCtx.VerifyBlobKZGProofBytes(otherBlob, commitment, blobProof))
+	invalid, err := smallCtx.FindInvalidBlobProofsBytes([][]byte{blob, otherBlob}, []goethkzg.KZGCommitment{commitment, commitment}, []goethkzg.KZGProof{blobProof, blobProof})
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, invalid)
+
+	// A mainnet sized blob cannot be deserialized with these params
+	invalid, err = smallCtx.FindInvalidBlobProofs([]*goethkzg.Blob{GetRandBlob(1)}, []goethkzg.KZGCommitment{commitment}, []goethkzg.KZGProof{blobProof})
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, invalid)
+
+	// EIP-7594
+	cells, proofs, err := smallCtx.ComputeCellsAndKZGProofsBytes(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Len(t, cells, int(smallParams.CellsPerExtBlob()))
+	require.Len(t, proofs, int(smallParams.CellsPerExtBlob()))
+	for _, cell := range cells {
+		require.Len(t, cell, smallParams.BytesPerCell())
+	}
+
+	// The first cells hold the blob itself, since the blob is the evaluation form of the polynomial
+	// in bit-reversed order.
+	numCellsInBlob := int(smallParams.FieldElementsPerBlob / smallParams.FieldElementsPerCell)
+	for i := 0; i < numCellsInBlob; i++ {
+		require.Equal(t, blob[i*smallParams.BytesPerCell():(i+1)*smallParams.BytesPerCell()], cells[i])
+	}
+
+	commitments := make([]goethkzg.KZGCommitment, len(cells))
+	cellIndices := make([]uint64, len(cells))
+	for i := range cells {
+		commitments[i] = commitment
+		cellIndices[i] = uint64(i)
+	}
+	require.NoError(t, smallCtx.VerifyCellKZGProofBatchBytes(commitments, cellIndices, cells, proofs))
+
+	// Swapping two proofs should make the batch fail
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	require.Error(t, smallCtx.VerifyCellKZGProofBatchBytes(commitments, cellIndices, cells, proofs))
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+
+	// Recover using every fourth cell, which is the minimum for a 4x expansion
+	var cellIDs []uint64
+	var partialCells [][]byte
+	for i := 0; i < len(cells); i += int(smallParams.ExpansionFactor) {
+		cellIDs = append(cellIDs, uint64(i))
+		partialCells = append(partialCells, cells[i])
+	}
+	recoveredCells, recoveredProofs, err := smallCtx.RecoverCellsAndComputeKZGProofsBytes(cellIDs, partialCells, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, cells, recoveredCells)
+	require.Equal(t, proofs, recoveredProofs)
+
+	_, _, err = smallCtx.RecoverCellsAndComputeKZGProofsBytes(cellIDs[1:], partialCells[1:], NumGoRoutines)
+	require.ErrorIs(t, err, goethkzg.ErrNotEnoughCellsForReconstruction)
+
+	// The context should survive a round trip through its serialized form
+	serialized, err := smallCtx.MarshalBinary()
+	require.NoError(t, err)
+	loaded, err := goethkzg.UnmarshalContext(serialized)
+	require.NoError(t, err)
+	require.Equal(t, smallParams, loaded.Params())
+	gotCells, gotProofs, err := loaded.ComputeCellsAndKZGProofsBytes(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, cells, gotCells)
+	require.Equal(t, proofs, gotProofs)
+}
+
+func TestNewContextWithParamsFixedSizeTypes(t *testing.T) {
+	setup := newInsecureSetup(1337, int(smallParams.FieldElementsPerBlob), int(smallParams.FieldElementsPerCell)+1)
+	smallCtx, err := goethkzg.NewContextWithParams(setup, smallParams)
+	require.NoError(t, err)
+
+	blob := GetRandBlob(1)
+	_, err = smallCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.ErrorIs(t, err, goethkzg.ErrInvalidBlobLength)
+
+	_, _, err = smallCtx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.ErrorIs(t, err, goethkzg.ErrParamsMismatch)
+
+	_, _, err = smallCtx.RecoverCellsAndComputeKZGProofs(nil, nil, NumGoRoutines)
+	require.ErrorIs(t, err, goethkzg.ErrParamsMismatch)
+
+	err = smallCtx.VerifyCellKZGProofBatch(nil, nil, nil, nil)
+	require.ErrorIs(t, err, goethkzg.ErrParamsMismatch)
+
+	_, _, err = smallCtx.ComputeCellsAndKZGProofsBytes(make([]byte, smallParams.BytesPerBlob()-1), NumGoRoutines)
+	require.ErrorIs(t, err, goethkzg.ErrInvalidBlobLength)
+
+	err = smallCtx.VerifyCellKZGProofBatchBytes([]goethkzg.KZGCommitment{{}}, []uint64{0}, [][]byte{make([]byte, smallParams.BytesPerCell()+1)}, []goethkzg.KZGProof{{}})
+	require.Error(t, err)
+}
+
+func TestMainnetParamsBytesMethods(t *testing.T) {
+	require.Equal(t, goethkzg.MainnetParams, ctx.Params())
+	require.Equal(t, uint64(goethkzg.CellsPerExtBlob), goethkzg.MainnetParams.CellsPerExtBlob())
+	require.Equal(t, goethkzg.BytesPerCell, goethkzg.MainnetParams.BytesPerCell())
+
+	blob := GetRandBlob(4)
+
+	expectedCommitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	commitment, err := ctx.BlobToKZGCommitmentBytes(blob[:], NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCommitment, commitment)
+
+	expectedCells, expectedProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofsBytes(blob[:], NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedProofs[:], proofs)
+	for i := range cells {
+		require.Equal(t, expectedCells[i][:], cells[i])
+	}
+}
+
+func TestNewContextWithParamsInvalid(t *testing.T) {
+	invalidParams := []goethkzg.Params{
+		{},
+		{FieldElementsPerBlob: 255, FieldElementsPerCell: 16, ExpansionFactor: 2},
+		{FieldElementsPerBlob: 256, FieldElementsPerCell: 0, ExpansionFactor: 2},
+		{FieldElementsPerBlob: 256, FieldElementsPerCell: 24, ExpansionFactor: 2},
+		{FieldElementsPerBlob: 256, FieldElementsPerCell: 256, ExpansionFactor: 2},
+		{FieldElementsPerBlob: 256, FieldElementsPerCell: 16, ExpansionFactor: 1},
+		{FieldElementsPerBlob: 256, FieldElementsPerCell: 16, ExpansionFactor: 3},
+		{FieldElementsPerBlob: 1 << 40, FieldElementsPerCell: 16, ExpansionFactor: 2},
+	}
+	setup := newInsecureSetup(1337, 4, 2)
+	for _, params := range invalidParams {
+		_, err := goethkzg.NewContextWithParams(setup, params)
+		require.ErrorIs(t, err, goethkzg.ErrInvalidParams, "params: %+v", params)
+	}
+
+	// The setup has the wrong number of G1 points
+	setup = newInsecureSetup(1337, 128, int(smallParams.FieldElementsPerCell)+1)
+	_, err := goethkzg.NewContextWithParams(setup, smallParams)
+	require.ErrorIs(t, err, goethkzg.ErrInvalidTrustedSetupLen)
+
+	// The setup does not have enough G2 points to verify cell proofs
+	setup = newInsecureSetup(1337, int(smallParams.FieldElementsPerBlob), int(smallParams.FieldElementsPerCell))
+	_, err = goethkzg.NewContextWithParams(setup, smallParams)
+	require.ErrorIs(t, err, goethkzg.ErrInvalidTrustedSetupLen)
+}
+
+func TestNewInsecureContextForTesting(t *testing.T) {
+	secret := big.NewInt(1337)
+	trustedSetup, err := goethkzg.NewInsecureTrustedSetupForTesting(secret, smallParams)
+	require.NoError(t, err)
+	require.Equal(t, newInsecureSetup(1337, int(smallParams.FieldElementsPerBlob), int(smallParams.FieldElementsPerCell)+1), trustedSetup)
+	require.NoError(t, goethkzg.VerifyTrustedSetupConsistency(trustedSetup))
+
+	testCtx, err := goethkzg.NewInsecureContextForTesting(secret, smallParams)
+	require.NoError(t, err)
+	require.Equal(t, smallParams, testCtx.Params())
+
+	blob := getRandBlobBytes(smallParams, 1)
+	commitment, err := testCtx.BlobToKZGCommitmentBytes(blob, NumGoRoutines)
+	require.NoError(t, err)
+	blobProof, err := testCtx.ComputeBlobKZGProofBytes(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, testCtx.VerifyBlobKZGProofBytes(blob, commitment, blobProof))
+
+	cells, proofs, err := testCtx.ComputeCellsAndKZGProofsBytes(blob, NumGoRoutines)
+	require.NoError(t, err)
+	commitments := make([]goethkzg.KZGCommitment, len(cells))
+	cellIndices := make([]uint64, len(cells))
+	for i := range cells {
+		commitments[i] = commitment
+		cellIndices[i] = uint64(i)
+	}
+	require.NoError(t, testCtx.VerifyCellKZGProofBatchBytes(commitments, cellIndices, cells, proofs))
+
+	// A different secret gives a different setup
+	otherCtx, err := goethkzg.NewInsecureContextForTesting(big.NewInt(1338), smallParams)
+	require.NoError(t, err)
+	require.NotEqual(t, testCtx.SetupFingerprint(), otherCtx.SetupFingerprint())
+	require.Error(t, otherCtx.VerifyBlobKZGProofBytes(blob, commitment, blobProof))
+
+	// The secret is reduced modulo the scalar field, so these would make every point the same
+	for _, secret := range []*big.Int{nil, big.NewInt(0), big.NewInt(1), fr.Modulus()} {
+		_, err := goethkzg.NewInsecureContextForTesting(secret, smallParams)
+		require.ErrorIs(t, err, goethkzg.ErrInvalidSecret, "secret: %v", secret)
+	}
+
+	_, err = goethkzg.NewInsecureContextForTesting(secret, goethkzg.Params{})
+	require.ErrorIs(t, err, goethkzg.ErrInvalidParams)
+}
@@ -0,0 +1,65 @@
+package goethkzg
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// PointEvaluationInputSize is the number of bytes in the input of the point evaluation precompile: the versioned
+// hash, z, y, the commitment and the proof.
+const PointEvaluationInputSize = 32 + SerializedScalarSize + SerializedScalarSize + CompressedG1Size + CompressedG1Size
+
+// PointEvaluationOutputSize is the number of bytes returned by the point evaluation precompile.
+const PointEvaluationOutputSize = 64
+
+// PointEvaluationGas is the gas cost of a call to the point evaluation precompile, which does not depend on the input.
+const PointEvaluationGas = 50000
+
+// PointEvaluationPrecompile implements the [point evaluation precompile] of EIP-4844, which execution clients expose
+// at address 0x0A.
+//
+// `input` is the versioned hash of the commitment, followed by z, y, the commitment and the proof. An error is
+// returned unless the versioned hash is that of the commitment, z and y are canonical scalars and the proof shows
+// that the committed polynomial takes the value y at z. Otherwise, the output is FIELD_ELEMENTS_PER_BLOB followed by
+// BLS_MODULUS, both as 32 byte big-endian integers.
+//
+// The precompile costs [PointEvaluationGas] whatever the input, and charging it is left to the caller.
+//
+// [point evaluation precompile]: https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+func (c *Context) PointEvaluationPrecompile(input []byte) ([]byte, error) {
+	if len(input) != PointEvaluationInputSize {
+		return nil, fmt.Errorf("%w: expected %d bytes, found %d", ErrPointEvaluationInputLength, PointEvaluationInputSize, len(input))
+	}
+	versionedHash := VersionedHash(input[:32])
+	z := Scalar(input[32:64])
+	y := Scalar(input[64:96])
+	commitment := KZGCommitment(input[96:144])
+	proof := KZGProof(input[144:192])
+
+	// Verify commitment matches versioned_hash
+	if commitment.VersionedHash() != versionedHash {
+		return nil, ErrVersionedHashMismatch
+	}
+
+	if _, err := DeserializeScalar(z); err != nil {
+		return nil, fmt.Errorf("%w: z", err)
+	}
+	if _, err := DeserializeScalar(y); err != nil {
+		return nil, fmt.Errorf("%w: y", err)
+	}
+
+	// Verify KZG proof with z and y in big endian format
+	if err := c.VerifyKZGProof(commitment, z, y, proof); err != nil {
+		return nil, err
+	}
+
+	return c.pointEvaluationOutput(), nil
+}
+
+// pointEvaluationOutput returns the output of a successful call to the point evaluation precompile.
+func (c *Context) pointEvaluationOutput() []byte {
+	output := make([]byte, PointEvaluationOutputSize)
+	binary.BigEndian.PutUint64(output[24:32], c.params.FieldElementsPerBlob)
+	copy(output[32:], BlsModulus[:])
+	return output
+}
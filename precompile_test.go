@@ -0,0 +1,200 @@
+package goethkzg_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// pointEvaluationOutput is the output of a successful call for mainnet, as given in EIP-4844.
+const pointEvaluationOutput = "0000000000000000000000000000000000000000000000000000000000001000" +
+	"73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001"
+
+func pointEvaluationInput(versionedHash goethkzg.VersionedHash, z, y []byte, commitment goethkzg.KZGCommitment, proof []byte) []byte {
+	input := append([]byte(nil), versionedHash[:]...)
+	input = append(input, z...)
+	input = append(input, y...)
+	input = append(input, commitment[:]...)
+	return append(input, proof...)
+}
+
+func TestPointEvaluationPrecompile(t *testing.T) {
+	expectedOutput, err := hex.DecodeString(pointEvaluationOutput)
+	require.NoError(t, err)
+
+	var infinity goethkzg.KZGCommitment
+	infinity[0] = 0xc0
+	var zero, one goethkzg.Scalar
+	one[31] = 1
+
+	// The point at infinity commits to the zero polynomial, which is zero everywhere
+	input := pointEvaluationInput(infinity.VersionedHash(), one[:], zero[:], infinity, infinity[:])
+	output, err := ctx.PointEvaluationPrecompile(input)
+	require.NoError(t, err)
+	require.Equal(t, expectedOutput, output)
+
+	t.Run("incorrect proof", func(t *testing.T) {
+		input := pointEvaluationInput(infinity.VersionedHash(), one[:], one[:], infinity, infinity[:])
+		_, err := ctx.PointEvaluationPrecompile(input)
+		require.ErrorIs(t, err, goethkzg.ErrVerifyOpeningProof)
+	})
+
+	t.Run("versioned hash mismatch", func(t *testing.T) {
+		versionedHash := infinity.VersionedHash()
+		versionedHash[0] = 0x02
+		_, err := ctx.PointEvaluationPrecompile(pointEvaluationInput(versionedHash, one[:], zero[:], infinity, infinity[:]))
+		require.ErrorIs(t, err, goethkzg.ErrVersionedHashMismatch)
+	})
+
+	t.Run("non-canonical scalars", func(t *testing.T) {
+		_, err := ctx.PointEvaluationPrecompile(pointEvaluationInput(infinity.VersionedHash(), goethkzg.BlsModulus[:], zero[:], infinity, infinity[:]))
+		require.ErrorIs(t, err, goethkzg.ErrNonCanonicalScalar)
+		_, err = ctx.PointEvaluationPrecompile(pointEvaluationInput(infinity.VersionedHash(), one[:], goethkzg.BlsModulus[:], infinity, infinity[:]))
+		require.ErrorIs(t, err, goethkzg.ErrNonCanonicalScalar)
+	})
+
+	t.Run("input length", func(t *testing.T) {
+		for _, length := range []int{0, goethkzg.PointEvaluationInputSize - 1, goethkzg.PointEvaluationInputSize + 1} {
+			_, err := ctx.PointEvaluationPrecompile(make([]byte, length))
+			require.ErrorIs(t, err, goethkzg.ErrPointEvaluationInputLength)
+		}
+		_, err := ctx.PointEvaluationPrecompile(append(input, 0))
+		require.ErrorIs(t, err, goethkzg.ErrPointEvaluationInputLength)
+	})
+
+	t.Run("params", func(t *testing.T) {
+		smallCtx, err := goethkzg.NewContextWithParams(newInsecureSetup(1337, int(smallParams.FieldElementsPerBlob), int(smallParams.FieldElementsPerCell)+1), smallParams)
+		require.NoError(t, err)
+		output, err := smallCtx.PointEvaluationPrecompile(input)
+		require.NoError(t, err)
+		require.Equal(t, byte(smallParams.FieldElementsPerBlob>>8), output[30])
+		require.Equal(t, byte(smallParams.FieldElementsPerBlob), output[31])
+		require.Equal(t, expectedOutput[32:], output[32:])
+	})
+}
+
+// pointEvaluationFixtures holds the point evaluation precompile fixtures, in the format of the precompile tests of
+// execution clients. The valid fixtures give the expected output, and the failing ones the reason for the failure.
+const pointEvaluationFixtures = "tests/point_evaluation_precompile"
+
+func TestPointEvaluationPrecompileFixtures(t *testing.T) {
+	type Fixture struct {
+		Name          string
+		Input         string
+		Expected      string
+		ExpectedError string
+		Gas           uint64
+	}
+	readFixtures := func(name string) []Fixture {
+		data, err := os.ReadFile(filepath.Join(pointEvaluationFixtures, name))
+		require.NoError(t, err)
+		var fixtures []Fixture
+		require.NoError(t, json.Unmarshal(data, &fixtures))
+		require.NotEmpty(t, fixtures)
+		return fixtures
+	}
+
+	for _, fixture := range readFixtures("pointEvaluation.json") {
+		t.Run(fixture.Name, func(t *testing.T) {
+			input, err := hex.DecodeString(fixture.Input)
+			require.NoError(t, err)
+			output, err := ctx.PointEvaluationPrecompile(input)
+			require.NoError(t, err)
+			require.Equal(t, fixture.Expected, hex.EncodeToString(output))
+			require.Equal(t, uint64(goethkzg.PointEvaluationGas), fixture.Gas)
+		})
+	}
+
+	// A nil error only requires the precompile to fail, since a malformed point is reported by gnark-crypto
+	expectedErrors := map[string]error{
+		"invalid input length":      goethkzg.ErrPointEvaluationInputLength,
+		"mismatched versioned hash": goethkzg.ErrVersionedHashMismatch,
+		"non-canonical scalar":      goethkzg.ErrNonCanonicalScalar,
+		"invalid point":             nil,
+		"verification failed":       goethkzg.ErrVerifyOpeningProof,
+	}
+	for _, fixture := range readFixtures("fail-pointEvaluation.json") {
+		t.Run(fixture.Name, func(t *testing.T) {
+			expectedErr, ok := expectedErrors[fixture.ExpectedError]
+			require.True(t, ok, fixture.ExpectedError)
+			input, err := hex.DecodeString(fixture.Input)
+			require.NoError(t, err)
+			output, err := ctx.PointEvaluationPrecompile(input)
+			require.Nil(t, output)
+			require.Error(t, err)
+			if expectedErr != nil {
+				require.ErrorIs(t, err, expectedErr)
+			}
+		})
+	}
+}
+
+// TestPointEvaluationPrecompileSpecVectors calls the precompile with the inputs of the verify_kzg_proof vectors, and
+// the versioned hash of their commitment.
+func TestPointEvaluationPrecompileSpecVectors(t *testing.T) {
+	type Test struct {
+		Input struct {
+			Commitment string `yaml:"commitment"`
+			Z          string `yaml:"z"`
+			Y          string `yaml:"y"`
+			Proof      string `yaml:"proof"`
+		}
+		ProofIsValid *bool `yaml:"output"`
+	}
+	decode := func(hexStr string, size int) ([]byte, bool) {
+		byts, err := hex.DecodeString(strings.TrimPrefix(hexStr, "0x"))
+		return byts, err == nil && len(byts) == size
+	}
+
+	expectedOutput, err := hex.DecodeString(pointEvaluationOutput)
+	require.NoError(t, err)
+
+	tests, err := filepath.Glob(verifyKZGProofTests)
+	require.NoError(t, err)
+	require.True(t, len(tests) > 0)
+
+	for _, testPath := range tests {
+		t.Run(testPath, func(t *testing.T) {
+			testFile, err := os.Open(testPath)
+			require.NoError(t, err)
+			test := Test{}
+			err = yaml.NewDecoder(testFile).Decode(&test)
+			require.NoError(t, testFile.Close())
+			require.NoError(t, err)
+			proofIsValid := test.ProofIsValid != nil && *test.ProofIsValid
+
+			// Inputs of the wrong size cannot be laid out as a precompile input
+			commitment, ok1 := decode(test.Input.Commitment, goethkzg.CompressedG1Size)
+			z, ok2 := decode(test.Input.Z, goethkzg.SerializedScalarSize)
+			y, ok3 := decode(test.Input.Y, goethkzg.SerializedScalarSize)
+			proof, ok4 := decode(test.Input.Proof, goethkzg.CompressedG1Size)
+			if !ok1 || !ok2 || !ok3 || !ok4 {
+				require.False(t, proofIsValid)
+				return
+			}
+
+			input := pointEvaluationInput(goethkzg.KZGCommitment(commitment).VersionedHash(), z, y, goethkzg.KZGCommitment(commitment), proof)
+			output, err := ctx.PointEvaluationPrecompile(input)
+			if !proofIsValid {
+				require.Error(t, err)
+				require.Nil(t, output)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, expectedOutput, output)
+
+			// The same call with the hash of another commitment fails
+			versionedHash := goethkzg.KZGCommitment(commitment).VersionedHash()
+			versionedHash[31] ^= 1
+			_, err = ctx.PointEvaluationPrecompile(pointEvaluationInput(versionedHash, z, y, goethkzg.KZGCommitment(commitment), proof))
+			require.ErrorIs(t, err, goethkzg.ErrVersionedHashMismatch)
+		})
+	}
+}
@@ -11,10 +11,20 @@ import (
 //
 // [blob_to_kzg_commitment]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob_to_kzg_commitment
 func (c *Context) BlobToKZGCommitment(blob *Blob, numGoRoutines int) (KZGCommitment, error) {
+	return c.BlobToKZGCommitmentBytes(blobBytes(blob), numGoRoutines)
+}
+
+// BlobToKZGCommitmentBytes is the same as [Context.BlobToKZGCommitment] for a blob whose size is given by the context
+// parameters. See [Params].
+func (c *Context) BlobToKZGCommitmentBytes(blob []byte, numGoRoutines int) (KZGCommitment, error) {
+	if err := c.checkEIP4844Prover(); err != nil {
+		return KZGCommitment{}, err
+	}
+
 	// 1. Deserialization
 	//
 	// Deserialize blob into polynomial
-	polynomial, err := DeserializeBlob(blob)
+	polynomial, err := c.deserializeBlob(blob)
 	if err != nil {
 		return KZGCommitment{}, err
 	}
@@ -44,9 +54,19 @@ func (c *Context) BlobToKZGCommitment(blob *Blob, numGoRoutines int) (KZGCommitm
 //
 // [compute_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_blob_kzg_proof
 func (c *Context) ComputeBlobKZGProof(blob *Blob, blobCommitment KZGCommitment, numGoRoutines int) (KZGProof, error) {
+	return c.ComputeBlobKZGProofBytes(blobBytes(blob), blobCommitment, numGoRoutines)
+}
+
+// ComputeBlobKZGProofBytes is the same as [Context.ComputeBlobKZGProof] for a blob whose size is given by the context
+// parameters. See [Params].
+func (c *Context) ComputeBlobKZGProofBytes(blob []byte, blobCommitment KZGCommitment, numGoRoutines int) (KZGProof, error) {
+	if err := c.checkEIP4844Prover(); err != nil {
+		return KZGProof{}, err
+	}
+
 	// 1. Deserialization
 	//
-	polynomial, err := DeserializeBlob(blob)
+	polynomial, err := c.deserializeBlob(blob)
 	if err != nil {
 		return KZGProof{}, err
 	}
@@ -83,9 +103,19 @@ func (c *Context) ComputeBlobKZGProof(blob *Blob, blobCommitment KZGCommitment,
 //
 // [compute_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_kzg_proof
 func (c *Context) ComputeKZGProof(blob *Blob, inputPointBytes Scalar, numGoRoutines int) (KZGProof, Scalar, error) {
+	return c.ComputeKZGProofBytes(blobBytes(blob), inputPointBytes, numGoRoutines)
+}
+
+// ComputeKZGProofBytes is the same as [Context.ComputeKZGProof] for a blob whose size is given by the context
+// parameters. See [Params].
+func (c *Context) ComputeKZGProofBytes(blob []byte, inputPointBytes Scalar, numGoRoutines int) (KZGProof, Scalar, error) {
+	if err := c.checkEIP4844Prover(); err != nil {
+		return KZGProof{}, [32]byte{}, err
+	}
+
 	// 1. Deserialization
 	//
-	polynomial, err := DeserializeBlob(blob)
+	polynomial, err := c.deserializeBlob(blob)
 	if err != nil {
 		return KZGProof{}, [32]byte{}, err
 	}
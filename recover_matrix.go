@@ -0,0 +1,163 @@
+package goethkzg
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-eth-kzg/internal/domain"
+	kzgmulti "github.com/crate-crypto/go-eth-kzg/internal/kzg_multi"
+	"github.com/crate-crypto/go-eth-kzg/internal/utils"
+)
+
+// RecoverMatrix implements [recover_matrix]: it recovers every cell and proof of the extended blobs of a block from
+// the columns that are available.
+//
+// `cellsByColumn` maps the index of each available column to its cells, which hold one cell for each of the
+// `numBlobs` blobs. Since the same columns are missing from every blob, the vanishing polynomial of the missing
+// columns is computed once and shared by all of the blobs.
+//
+// The result is in column-major order, as for [Context.ComputeCellsAndKZGProofsBatch]: cells[column][row] is the
+// cell of blob `row` at index `column`, which is the order in which cells are grouped into a DataColumnSidecar.
+//
+// The blobs are recovered in parallel, using all of the available CPUs. [Context.RecoverMatrixCtx] can be used to
+// bound the number of go-routines.
+//
+// A column index that is out of range is reported as a [BatchError] of kind [ErrorKindInvalidCellIndex], and a
+// column whose number of cells is not `numBlobs` as one of kind [ErrorKindBatchLength]. A cell that cannot be
+// deserialized is reported as a [BatchError] whose Index is the row of the cell.
+//
+// [recover_matrix]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/das-core.md#recover_matrix
+func (c *Context) RecoverMatrix(cellsByColumn map[uint64][]*Cell, numBlobs int) ([CellsPerExtBlob][]*Cell, [CellsPerExtBlob][]KZGProof, error) {
+	return c.RecoverMatrixCtx(context.Background(), cellsByColumn, numBlobs, 0)
+}
+
+// RecoverMatrixCtx is the same as [Context.RecoverMatrix], but stops early and returns ctx.Err() if `ctx` is
+// cancelled or its deadline passes.
+//
+// numGoRoutines bounds the number of go-routines used for the whole matrix, as for
+// [Context.ComputeCellsAndKZGProofsBatch]. Setting this value to a negative number or 0 will make it default to the
+// number of CPUs.
+func (c *Context) RecoverMatrixCtx(ctx context.Context, cellsByColumn map[uint64][]*Cell, numBlobs int, numGoRoutines int) ([CellsPerExtBlob][]*Cell, [CellsPerExtBlob][]KZGProof, error) {
+	if err := c.checkFixedSizeTypes(); err != nil {
+		return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, err
+	}
+	if err := c.checkEIP7594Prover(); err != nil {
+		return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, err
+	}
+
+	// 1. Check the shape of the matrix
+	if numBlobs < 0 {
+		return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, newBatchError(-1, ErrorKindBatchLength, fmt.Errorf("%w: %d blobs", ErrBatchLengthCheck, numBlobs))
+	}
+	columnIndices := make([]uint64, 0, len(cellsByColumn))
+	for columnIndex, column := range cellsByColumn {
+		if columnIndex >= CellsPerExtBlob {
+			return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, newBatchError(-1, ErrorKindInvalidCellIndex,
+				fmt.Errorf("%w: column %d", ErrFoundInvalidCellID, columnIndex))
+		}
+		if len(column) != numBlobs {
+			return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, newBatchError(-1, ErrorKindBatchLength,
+				fmt.Errorf("%w: column %d has %d cells for %d blobs", ErrBatchLengthCheck, columnIndex, len(column), numBlobs))
+		}
+		columnIndices = append(columnIndices, columnIndex)
+	}
+	slices.Sort(columnIndices)
+
+	// All of the cells share a single allocation, laid out in column-major order, as do the proofs
+	cellsBuf := make([]Cell, CellsPerExtBlob*numBlobs)
+	proofsBuf := make([]KZGProof, CellsPerExtBlob*numBlobs)
+	var cells [CellsPerExtBlob][]*Cell
+	var proofs [CellsPerExtBlob][]KZGProof
+	for column := 0; column < CellsPerExtBlob; column++ {
+		cells[column] = make([]*Cell, numBlobs)
+		for row := 0; row < numBlobs; row++ {
+			cells[column][row] = &cellsBuf[column*numBlobs+row]
+		}
+		proofs[column] = proofsBuf[column*numBlobs : (column+1)*numBlobs : (column+1)*numBlobs]
+	}
+	if numBlobs == 0 {
+		return cells, proofs, nil
+	}
+
+	if len(columnIndices) < c.dataRecovery.NumBlocksNeededToReconstruct() {
+		return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, ErrNotEnoughCellsForReconstruction
+	}
+
+	numGoRoutines = utils.ResolveNumGoRoutines(numGoRoutines)
+
+	// 2. Deserialize all of the available cells in parallel.
+	//
+	// The extended blobs share a single scratch buffer, with the missing cells left as zero.
+	extBlobSize := int(c.params.FieldElementsPerExtBlob())
+	extendedBlobs := make([]fr.Element, numBlobs*extBlobSize)
+	errs := make([]error, numBlobs)
+	utils.ParallelFor(numBlobs, numGoRoutines, func(start, end int) {
+		for row := start; row < end; row++ {
+			extendedBlob := extendedBlobs[row*extBlobSize : (row+1)*extBlobSize]
+			for _, columnIndex := range columnIndices {
+				var cell []byte
+				if cellsByColumn[columnIndex][row] != nil {
+					cell = cellsByColumn[columnIndex][row][:]
+				}
+				cellEvals, err := c.deserializeCell(cell)
+				if err != nil {
+					errs[row] = newBatchError(row, ErrorKindMalformedCell, fmt.Errorf("column %d: %w", columnIndex, err))
+					break
+				}
+				copy(extendedBlob[columnIndex*scalarsPerCell:], cellEvals)
+			}
+			// Bit reverse the extendedBlob so that it is in normal order
+			domain.BitReverse(extendedBlob)
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, err
+		}
+	}
+
+	// 3. Compute the vanishing polynomial of the missing columns, which is the same for every blob
+	zX, err := c.dataRecovery.NewVanishingPoly(ctx, c.missingCellIDs(columnIndices))
+	if err != nil {
+		return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, err
+	}
+
+	// 4. Recover each blob, and compute its cells and proofs
+	numWorkers := max(1, min(numGoRoutines, numBlobs))
+	goRoutinesPerBlob := max(1, numGoRoutines/numWorkers)
+	utils.ParallelForEach(numBlobs, numWorkers, func(row int) {
+		extendedBlob := extendedBlobs[row*extBlobSize : (row+1)*extBlobSize]
+		polyCoeff, err := c.dataRecovery.RecoverPolynomialCoefficientsWithVanishingPoly(ctx, extendedBlob, zX)
+		if err != nil {
+			errs[row] = err
+			return
+		}
+
+		cosetEvaluations, err := c.fk20.ComputeExtendedPolynomial(ctx, polyCoeff, goRoutinesPerBlob)
+		if err != nil {
+			errs[row] = err
+			return
+		}
+		for column, cosetEval := range cosetEvaluations {
+			serializeScalars(cells[column][row][:], cosetEval)
+		}
+
+		proofsG1, err := kzgmulti.ComputeMultiPointKZGProofs(ctx, c.fk20, polyCoeff, goRoutinesPerBlob)
+		if err != nil {
+			errs[row] = err
+			return
+		}
+		for column, proof := range proofsG1 {
+			proofs[column][row] = KZGProof(SerializeG1Point(proof))
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return [CellsPerExtBlob][]*Cell{}, [CellsPerExtBlob][]KZGProof{}, err
+		}
+	}
+
+	return cells, proofs, nil
+}
@@ -0,0 +1,108 @@
+package goethkzg_test
+
+import (
+	"context"
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverMatrix(t *testing.T) {
+	const numBlobs = 3
+	blobs := make([]*goethkzg.Blob, numBlobs)
+	for i := range blobs {
+		blobs[i] = GetRandBlob(int64(40 + i))
+	}
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofsBatch(blobs, NumGoRoutines)
+	require.NoError(t, err)
+
+	// available returns the columns at the given indices, as received from the network
+	available := func(columnIndices []uint64) map[uint64][]*goethkzg.Cell {
+		cellsByColumn := make(map[uint64][]*goethkzg.Cell, len(columnIndices))
+		for _, columnIndex := range columnIndices {
+			cellsByColumn[columnIndex] = cells[columnIndex]
+		}
+		return cellsByColumn
+	}
+	// The odd columns, and the first half of the columns, are each just enough to recover the blobs
+	var oddColumns, firstHalf, allColumns []uint64
+	for i := uint64(0); i < goethkzg.CellsPerExtBlob; i++ {
+		if i%2 == 1 {
+			oddColumns = append(oddColumns, i)
+		}
+		if i < goethkzg.CellsPerExtBlob/2 {
+			firstHalf = append(firstHalf, i)
+		}
+		allColumns = append(allColumns, i)
+	}
+
+	for _, columnIndices := range [][]uint64{oddColumns, firstHalf, allColumns} {
+		recoveredCells, recoveredProofs, err := ctx.RecoverMatrix(available(columnIndices), numBlobs)
+		require.NoError(t, err)
+		require.Equal(t, cells, recoveredCells)
+		require.Equal(t, proofs, recoveredProofs)
+	}
+
+	// The result does not depend on the number of go-routines
+	recoveredCells, recoveredProofs, err := ctx.RecoverMatrixCtx(context.Background(), available(oddColumns), numBlobs, 1)
+	require.NoError(t, err)
+	require.Equal(t, cells, recoveredCells)
+	require.Equal(t, proofs, recoveredProofs)
+
+	t.Run("no blobs", func(t *testing.T) {
+		recoveredCells, recoveredProofs, err := ctx.RecoverMatrix(nil, 0)
+		require.NoError(t, err)
+		for column := range recoveredCells {
+			require.Empty(t, recoveredCells[column])
+			require.Empty(t, recoveredProofs[column])
+		}
+	})
+
+	t.Run("not enough columns", func(t *testing.T) {
+		_, _, err := ctx.RecoverMatrix(available(oddColumns[1:]), numBlobs)
+		require.ErrorIs(t, err, goethkzg.ErrNotEnoughCellsForReconstruction)
+	})
+
+	t.Run("invalid column index", func(t *testing.T) {
+		cellsByColumn := available(oddColumns)
+		cellsByColumn[goethkzg.CellsPerExtBlob] = cells[0]
+		_, _, err := ctx.RecoverMatrix(cellsByColumn, numBlobs)
+		requireBatchError(t, err, -1, goethkzg.ErrorKindInvalidCellIndex)
+		require.ErrorIs(t, err, goethkzg.ErrFoundInvalidCellID)
+	})
+
+	t.Run("column length", func(t *testing.T) {
+		cellsByColumn := available(oddColumns)
+		cellsByColumn[1] = cells[1][:numBlobs-1]
+		_, _, err := ctx.RecoverMatrix(cellsByColumn, numBlobs)
+		requireBatchError(t, err, -1, goethkzg.ErrorKindBatchLength)
+		require.ErrorIs(t, err, goethkzg.ErrBatchLengthCheck)
+
+		_, _, err = ctx.RecoverMatrix(available(oddColumns), -1)
+		requireBatchError(t, err, -1, goethkzg.ErrorKindBatchLength)
+	})
+
+	t.Run("malformed cell", func(t *testing.T) {
+		cellsByColumn := available(oddColumns)
+		badCell := *cells[5][2]
+		nonCanonical := nonCanonicalScalar(40)
+		copy(badCell[:], nonCanonical[:])
+		cellsByColumn[5] = []*goethkzg.Cell{cells[5][0], cells[5][1], &badCell}
+		_, _, err := ctx.RecoverMatrix(cellsByColumn, numBlobs)
+		requireBatchError(t, err, 2, goethkzg.ErrorKindMalformedCell)
+		require.ErrorIs(t, err, goethkzg.ErrNonCanonicalScalar)
+
+		cellsByColumn[5] = []*goethkzg.Cell{cells[5][0], nil, cells[5][2]}
+		_, _, err = ctx.RecoverMatrix(cellsByColumn, numBlobs)
+		requireBatchError(t, err, 1, goethkzg.ErrorKindMalformedCell)
+		require.ErrorIs(t, err, goethkzg.ErrDeserializeNilInput)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, err := ctx.RecoverMatrixCtx(cancelled, available(oddColumns), numBlobs, NumGoRoutines)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,383 @@
+package goethkzg_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/crate-crypto/go-eth-kzg/internal/reference"
+	"github.com/stretchr/testify/require"
+)
+
+// The tests in this file run the same inputs through the optimized code and through the naive implementation in
+// internal/reference, and check that every output byte is the same. They also check that both reject the same
+// inputs, and that a proof which is rejected by one is rejected by the other.
+
+// differentialContexts returns an optimized and a reference context for the same trusted setup and parameters.
+func differentialContexts(t *testing.T, trustedSetup *goethkzg.JSONTrustedSetup, params goethkzg.Params) (*goethkzg.Context, *reference.Context) {
+	t.Helper()
+	optimized, err := goethkzg.NewContextWithParams(trustedSetup, params)
+	require.NoError(t, err)
+
+	var setup reference.Setup
+	for _, point := range trustedSetup.SetupG1Monomial {
+		setup.G1Monomial = append(setup.G1Monomial, decodeG1(t, point))
+	}
+	for _, point := range trustedSetup.SetupG1Lagrange {
+		setup.G1Lagrange = append(setup.G1Lagrange, decodeG1(t, point))
+	}
+	for _, point := range trustedSetup.SetupG2 {
+		var g2 bls12381.G2Affine
+		_, err := g2.SetBytes(decodeHex(t, point))
+		require.NoError(t, err)
+		setup.G2Monomial = append(setup.G2Monomial, g2)
+	}
+	naive, err := reference.NewContext(setup, int(params.FieldElementsPerCell), int(params.ExpansionFactor))
+	require.NoError(t, err)
+
+	return optimized, naive
+}
+
+func decodeHex(t *testing.T, hexStr string) []byte {
+	t.Helper()
+	byts, err := hex.DecodeString(strings.TrimPrefix(hexStr, "0x"))
+	require.NoError(t, err)
+	return byts
+}
+
+func decodeG1(t *testing.T, hexStr string) bls12381.G1Affine {
+	t.Helper()
+	var point bls12381.G1Affine
+	_, err := point.SetBytes(decodeHex(t, hexStr))
+	require.NoError(t, err)
+	return point
+}
+
+// requireSameResult checks that the optimized code fails exactly when the reference does, and otherwise returns the
+// same result.
+func requireSameResult(t *testing.T, expected any, expectedErr error, actual any, actualErr error) {
+	t.Helper()
+	if expectedErr != nil {
+		require.Error(t, actualErr, "reference error: %v", expectedErr)
+		return
+	}
+	require.NoError(t, actualErr)
+	require.Equal(t, expected, actual)
+}
+
+// requireSameVerification checks that the optimized code rejects the inputs exactly when the reference does, and
+// otherwise accepts the proof exactly when the reference does.
+func requireSameVerification(t *testing.T, expectedValid bool, expectedErr error, actualErr error) {
+	t.Helper()
+	switch {
+	case expectedErr != nil:
+		require.Error(t, actualErr, "reference error: %v", expectedErr)
+		require.NotErrorIs(t, actualErr, goethkzg.ErrVerifyOpeningProof)
+	case expectedValid:
+		require.NoError(t, actualErr)
+	default:
+		require.ErrorIs(t, actualErr, goethkzg.ErrVerifyOpeningProof)
+	}
+}
+
+// differentialBlobs returns random blobs, along with blobs that exercise the edge cases of the optimized code.
+func differentialBlobs(rng *rand.Rand, params goethkzg.Params) map[string][]byte {
+	randomScalar := func() []byte {
+		var element fr.Element
+		element.SetBigInt(new(big.Int).Rand(rng, fr.Modulus()))
+		serScalar := goethkzg.SerializeScalar(element)
+		return serScalar[:]
+	}
+	blobWith := func(scalar func(i int) []byte) []byte {
+		blob := make([]byte, 0, params.BytesPerBlob())
+		for i := 0; i < int(params.FieldElementsPerBlob); i++ {
+			blob = append(blob, scalar(i)...)
+		}
+		return blob
+	}
+
+	modulusMinusOne := new(big.Int).Sub(fr.Modulus(), big.NewInt(1)).FillBytes(make([]byte, goethkzg.SerializedScalarSize))
+	constant := randomScalar()
+	nonCanonical := blobWith(func(int) []byte { return randomScalar() })
+	copy(nonCanonical[rng.Intn(int(params.FieldElementsPerBlob))*goethkzg.SerializedScalarSize:], goethkzg.BlsModulus[:])
+
+	return map[string][]byte{
+		"random":         blobWith(func(int) []byte { return randomScalar() }),
+		"other_random":   blobWith(func(int) []byte { return randomScalar() }),
+		"zero":           make([]byte, params.BytesPerBlob()),
+		"constant":       blobWith(func(int) []byte { return constant }),
+		"modulus_minus1": blobWith(func(int) []byte { return modulusMinusOne }),
+		"single_nonzero": blobWith(func(i int) []byte {
+			if i == 1 {
+				return constant
+			}
+			return make([]byte, goethkzg.SerializedScalarSize)
+		}),
+		"non_canonical": nonCanonical,
+		"short":         make([]byte, params.BytesPerBlob()-1),
+	}
+}
+
+// differentialPoints returns random points and points that exercise the edge cases of the optimized code, such as the
+// points of the evaluation domain.
+func differentialPoints(rng *rand.Rand, params goethkzg.Params) map[string]goethkzg.Scalar {
+	var root fr.Element
+	exponent := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	exponent.Div(exponent, new(big.Int).SetUint64(params.FieldElementsPerBlob))
+	root.SetUint64(7)
+	root.Exp(root, exponent)
+	root.Exp(root, big.NewInt(rng.Int63n(int64(params.FieldElementsPerBlob))))
+
+	var random fr.Element
+	random.SetBigInt(new(big.Int).Rand(rng, fr.Modulus()))
+
+	var one fr.Element
+	one.SetOne()
+
+	return map[string]goethkzg.Scalar{
+		"random":        goethkzg.SerializeScalar(random),
+		"domain":        goethkzg.SerializeScalar(root),
+		"one":           goethkzg.SerializeScalar(one),
+		"zero":          {},
+		"non_canonical": goethkzg.Scalar(goethkzg.BlsModulus),
+	}
+}
+
+func TestDifferentialEIP4844(t *testing.T) {
+	params := goethkzg.Params{FieldElementsPerBlob: 64, FieldElementsPerCell: 8, ExpansionFactor: 2}
+	trustedSetup, err := goethkzg.NewInsecureTrustedSetupForTesting(big.NewInt(1337), params)
+	require.NoError(t, err)
+	optimized, naive := differentialContexts(t, trustedSetup, params)
+
+	for seed := int64(0); seed < 3; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		testDifferentialEIP4844(t, optimized, naive, differentialBlobs(rng, params), differentialPoints(rng, params))
+	}
+}
+
+func testDifferentialEIP4844(t *testing.T, optimized *goethkzg.Context, naive *reference.Context, blobs map[string][]byte, points map[string]goethkzg.Scalar) {
+	infinity := goethkzg.KZGCommitment{0xc0}
+	notInSubgroup := goethkzg.KZGCommitment{0x80}
+
+	for blobName, blob := range blobs {
+		t.Run(blobName, func(t *testing.T) {
+			// 1. blob_to_kzg_commitment
+			//
+			expectedCommitment, expectedErr := naive.BlobToKZGCommitment(blob)
+			commitment, err := optimized.BlobToKZGCommitmentBytes(blob, NumGoRoutines)
+			requireSameResult(t, expectedCommitment, expectedErr, commitment[:], err)
+
+			// 2. compute_blob_kzg_proof and verify_blob_kzg_proof, with the commitment and with other points
+			//
+			for _, otherCommitment := range []goethkzg.KZGCommitment{commitment, infinity, notInSubgroup} {
+				expectedProof, expectedErr := naive.ComputeBlobKZGProof(blob, otherCommitment[:])
+				blobProof, err := optimized.ComputeBlobKZGProofBytes(blob, otherCommitment, NumGoRoutines)
+				requireSameResult(t, expectedProof, expectedErr, blobProof[:], err)
+
+				for _, proof := range []goethkzg.KZGProof{blobProof, goethkzg.KZGProof(infinity), goethkzg.KZGProof(notInSubgroup)} {
+					expectedValid, expectedErr := naive.VerifyBlobKZGProof(blob, otherCommitment[:], proof[:])
+					err := optimized.VerifyBlobKZGProofBytes(blob, otherCommitment, proof)
+					requireSameVerification(t, expectedValid, expectedErr, err)
+				}
+			}
+
+			// 3. compute_kzg_proof and verify_kzg_proof, at points inside and outside of the domain
+			//
+			for _, z := range points {
+				expectedProof, expectedY, expectedErr := naive.ComputeKZGProof(blob, z[:])
+				proof, y, err := optimized.ComputeKZGProofBytes(blob, z, NumGoRoutines)
+				requireSameResult(t, [][]byte{expectedProof, expectedY}, expectedErr, [][]byte{proof[:], y[:]}, err)
+
+				var wrongY goethkzg.Scalar
+				wrongY[goethkzg.SerializedScalarSize-1] = 1
+				for _, claimedY := range []goethkzg.Scalar{y, wrongY, goethkzg.Scalar(goethkzg.BlsModulus)} {
+					expectedValid, expectedErr := naive.VerifyKZGProof(commitment[:], z[:], claimedY[:], proof[:])
+					err := optimized.VerifyKZGProof(commitment, z, claimedY, proof)
+					requireSameVerification(t, expectedValid, expectedErr, err)
+				}
+			}
+		})
+	}
+
+	// 4. verify_blob_kzg_proof_batch, over pairs of blobs with each of their proofs swapped
+	//
+	var batchBlobs [][]byte
+	var batchCommitments []goethkzg.KZGCommitment
+	var batchProofs []goethkzg.KZGProof
+	for _, blobName := range []string{"random", "other_random", "non_canonical"} {
+		blob, ok := blobs[blobName]
+		if !ok {
+			continue
+		}
+		commitment, _ := optimized.BlobToKZGCommitmentBytes(blob, NumGoRoutines)
+		proof, _ := optimized.ComputeBlobKZGProofBytes(blob, commitment, NumGoRoutines)
+		batchBlobs = append(batchBlobs, blob)
+		batchCommitments = append(batchCommitments, commitment)
+		batchProofs = append(batchProofs, proof)
+	}
+	verifyBatch := func(blobs [][]byte, commitments []goethkzg.KZGCommitment, proofs []goethkzg.KZGProof) {
+		serCommitments := make([][]byte, len(commitments))
+		for i := range commitments {
+			serCommitments[i] = commitments[i][:]
+		}
+		serProofs := make([][]byte, len(proofs))
+		for i := range proofs {
+			serProofs[i] = proofs[i][:]
+		}
+		expectedValid, expectedErr := naive.VerifyBlobKZGProofBatch(blobs, serCommitments, serProofs)
+		err := optimized.VerifyBlobKZGProofBatchBytes(blobs, commitments, proofs)
+		requireSameVerification(t, expectedValid, expectedErr, err)
+	}
+	verifyBatch(nil, nil, nil)
+	verifyBatch(batchBlobs[:2], batchCommitments[:2], batchProofs[:2])
+	verifyBatch(batchBlobs[:2], batchCommitments[:2], []goethkzg.KZGProof{batchProofs[1], batchProofs[0]})
+	verifyBatch(batchBlobs[:2], batchCommitments[:2], batchProofs[:1])
+	verifyBatch(batchBlobs, batchCommitments, batchProofs)
+}
+
+func TestDifferentialEIP7594(t *testing.T) {
+	for _, params := range []goethkzg.Params{
+		{FieldElementsPerBlob: 64, FieldElementsPerCell: 8, ExpansionFactor: 2},
+		{FieldElementsPerBlob: 64, FieldElementsPerCell: 4, ExpansionFactor: 4},
+	} {
+		trustedSetup, err := goethkzg.NewInsecureTrustedSetupForTesting(big.NewInt(1337), params)
+		require.NoError(t, err)
+		optimized, naive := differentialContexts(t, trustedSetup, params)
+
+		rng := rand.New(rand.NewSource(0))
+		for blobName, blob := range differentialBlobs(rng, params) {
+			t.Run(blobName, func(t *testing.T) {
+				testDifferentialEIP7594(t, optimized, naive, rng, blob)
+			})
+		}
+	}
+}
+
+// TestDifferentialMainnet checks fewer cases than the tests with smaller parameters, since the reference takes
+// seconds for each blob. For EIP-7594, it only checks the cells and proofs of a single blob. Recovery works the same
+// way for all parameters, and is checked in TestDifferentialEIP7594.
+func TestDifferentialMainnet(t *testing.T) {
+	optimized, naive := differentialContexts(t, loadMainnetSetup(t), goethkzg.MainnetParams)
+	rng := rand.New(rand.NewSource(0))
+	blobs := differentialBlobs(rng, goethkzg.MainnetParams)
+	points := differentialPoints(rng, goethkzg.MainnetParams)
+
+	t.Run("EIP4844", func(t *testing.T) {
+		testDifferentialEIP4844(t, optimized, naive,
+			map[string][]byte{"random": blobs["random"], "non_canonical": blobs["non_canonical"]},
+			map[string]goethkzg.Scalar{"random": points["random"], "domain": points["domain"]},
+		)
+	})
+
+	t.Run("EIP7594", func(t *testing.T) {
+		expectedCells, expectedProofs, err := naive.ComputeCellsAndKZGProofs(blobs["random"])
+		require.NoError(t, err)
+		cells, proofs, err := optimized.ComputeCellsAndKZGProofsBytes(blobs["random"], NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expectedCells, cells)
+		for i := range proofs {
+			require.Equal(t, expectedProofs[i], proofs[i][:])
+		}
+	})
+}
+
+func testDifferentialEIP7594(t *testing.T, optimized *goethkzg.Context, naive *reference.Context, rng *rand.Rand, blob []byte) {
+	// 1. compute_cells_and_kzg_proofs
+	//
+	expectedCells, expectedProofs, expectedErr := naive.ComputeCellsAndKZGProofs(blob)
+	cells, proofs, err := optimized.ComputeCellsAndKZGProofsBytes(blob, NumGoRoutines)
+	serProofs := make([][]byte, len(proofs))
+	for i := range proofs {
+		serProofs[i] = proofs[i][:]
+	}
+	requireSameResult(t, [][][]byte{expectedCells, expectedProofs}, expectedErr, [][][]byte{cells, serProofs}, err)
+	if expectedErr != nil {
+		return
+	}
+	commitment, err := optimized.BlobToKZGCommitmentBytes(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	params := optimized.Params()
+	numCells := int(params.CellsPerExtBlob())
+	minCells := numCells / int(params.ExpansionFactor)
+
+	// 2. verify_cell_kzg_proof_batch, on a random subset of the cells and on corrupted versions of it
+	//
+	verifyBatch := func(cellIndices []uint64, cells [][]byte, proofs []goethkzg.KZGProof) {
+		commitments := make([]goethkzg.KZGCommitment, len(cells))
+		serCommitments := make([][]byte, len(cells))
+		for i := range commitments {
+			commitments[i] = commitment
+			serCommitments[i] = commitment[:]
+		}
+		serProofs := make([][]byte, len(proofs))
+		for i := range proofs {
+			serProofs[i] = proofs[i][:]
+		}
+		expectedValid, expectedErr := naive.VerifyCellKZGProofBatch(serCommitments, cellIndices, cells, serProofs)
+		err := optimized.VerifyCellKZGProofBatchBytes(commitments, cellIndices, cells, proofs)
+		requireSameVerification(t, expectedValid, expectedErr, err)
+	}
+	subset := rng.Perm(numCells)[:1+rng.Intn(numCells)]
+	subsetIndices := make([]uint64, len(subset))
+	subsetCells := make([][]byte, len(subset))
+	subsetProofs := make([]goethkzg.KZGProof, len(subset))
+	for k, i := range subset {
+		subsetIndices[k] = uint64(i)
+		subsetCells[k] = cells[i]
+		subsetProofs[k] = proofs[i]
+	}
+	verifyBatch(subsetIndices, subsetCells, subsetProofs)
+	verifyBatch(nil, nil, nil)
+
+	corruptCell := append([]byte(nil), subsetCells[0]...)
+	corruptCell[len(corruptCell)-1] ^= 1
+	verifyBatch(subsetIndices, append([][]byte{corruptCell}, subsetCells[1:]...), subsetProofs)
+	verifyBatch(append([]uint64{(subsetIndices[0] + 1) % uint64(numCells)}, subsetIndices[1:]...), subsetCells, subsetProofs)
+	verifyBatch(append([]uint64{uint64(numCells)}, subsetIndices[1:]...), subsetCells, subsetProofs)
+	verifyBatch(append(subsetIndices, subsetIndices[0]), append(subsetCells, subsetCells[0]), append(subsetProofs, subsetProofs[0]))
+
+	// 3. recover_cells_and_kzg_proofs, from random subsets that are large enough and from invalid subsets
+	//
+	recoverCells := func(cellIndices []uint64, cells [][]byte) {
+		expectedCells, expectedProofs, expectedErr := naive.RecoverCellsAndKZGProofs(cellIndices, cells)
+		recoveredCells, recoveredProofs, err := optimized.RecoverCellsAndComputeKZGProofsBytes(cellIndices, cells, NumGoRoutines)
+		serProofs := make([][]byte, len(recoveredProofs))
+		for i := range recoveredProofs {
+			serProofs[i] = recoveredProofs[i][:]
+		}
+		requireSameResult(t, [][][]byte{expectedCells, expectedProofs}, expectedErr, [][][]byte{recoveredCells, serProofs}, err)
+	}
+	for _, numKept := range []int{minCells, minCells + rng.Intn(numCells-minCells+1), numCells} {
+		keptIndices, keptCells := sortedSubset(rng.Perm(numCells)[:numKept], cells)
+		recoverCells(keptIndices, keptCells)
+	}
+	keptIndices, keptCells := sortedSubset(rng.Perm(numCells)[:minCells+1], cells)
+	recoverCells(keptIndices[1:], keptCells[1:])
+	recoverCells(append([]uint64{keptIndices[1]}, keptIndices[1:]...), append([][]byte{keptCells[1]}, keptCells[1:]...))
+	recoverCells(append([]uint64{keptIndices[1], keptIndices[0]}, keptIndices[2:]...), append([][]byte{keptCells[1], keptCells[0]}, keptCells[2:]...))
+	recoverCells(append(keptIndices[:len(keptIndices)-1:len(keptIndices)-1], uint64(numCells)), keptCells)
+	recoverCells(keptIndices, append([][]byte{keptCells[0][1:]}, keptCells[1:]...))
+}
+
+// sortedSubset returns the given cell indices in ascending order, and the cells at those indices.
+func sortedSubset(indices []int, cells [][]byte) ([]uint64, [][]byte) {
+	isKept := make([]bool, len(cells))
+	for _, i := range indices {
+		isKept[i] = true
+	}
+	var keptIndices []uint64
+	var keptCells [][]byte
+	for i, kept := range isKept {
+		if kept {
+			keptIndices = append(keptIndices, uint64(i))
+			keptCells = append(keptCells, cells[i])
+		}
+	}
+	return keptIndices, keptCells
+}
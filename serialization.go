@@ -135,14 +135,53 @@ func DeserializeBlob(blob *Blob) (kzg.Polynomial, error) {
 	if blob == nil {
 		return nil, ErrDeserializeNilInput
 	}
-	poly := make(kzg.Polynomial, ScalarsPerBlob)
-	for i := 0; i < ScalarsPerBlob; i++ {
-		chunk := blob[i*SerializedScalarSize : (i+1)*SerializedScalarSize]
-		if err := poly[i].SetBytesCanonical(chunk); err != nil {
-			return nil, ErrNonCanonicalScalar
+	return deserializeScalars(blob[:])
+}
+
+// deserializeBlob is the same as [DeserializeBlob] for a blob whose size is given by the context parameters.
+func (ctx *Context) deserializeBlob(blob []byte) (kzg.Polynomial, error) {
+	if blob == nil {
+		return nil, ErrDeserializeNilInput
+	}
+	if len(blob) != ctx.params.BytesPerBlob() {
+		return nil, ErrInvalidBlobLength
+	}
+	return deserializeScalars(blob)
+}
+
+// deserializeBlobInto is the same as deserializeBlob, but writes the scalars into `out`,
+// which must hold [Params.FieldElementsPerBlob] elements.
+func (ctx *Context) deserializeBlobInto(out []fr.Element, blob []byte) error {
+	if blob == nil {
+		return ErrDeserializeNilInput
+	}
+	if len(blob) != ctx.params.BytesPerBlob() {
+		return ErrInvalidBlobLength
+	}
+	return deserializeScalarsInto(out, blob)
+}
+
+// deserializeScalars deserializes a flattened list of scalars.
+//
+// Note: The length of `serScalars` must be a multiple of [SerializedScalarSize].
+func deserializeScalars(serScalars []byte) ([]fr.Element, error) {
+	scalars := make([]fr.Element, len(serScalars)/SerializedScalarSize)
+	if err := deserializeScalarsInto(scalars, serScalars); err != nil {
+		return nil, err
+	}
+	return scalars, nil
+}
+
+// deserializeScalarsInto is the same as deserializeScalars, but writes the scalars into `out`,
+// which must hold len(serScalars)/SerializedScalarSize elements.
+func deserializeScalarsInto(out []fr.Element, serScalars []byte) error {
+	for i := range out {
+		chunk := serScalars[i*SerializedScalarSize : (i+1)*SerializedScalarSize]
+		if err := out[i].SetBytesCanonical(chunk); err != nil {
+			return ErrNonCanonicalScalar
 		}
 	}
-	return poly, nil
+	return nil
 }
 
 // DeserializeScalar implements [bytes_to_bls_field].
@@ -183,34 +222,47 @@ func SerializePoly(poly kzg.Polynomial) *Blob {
 // serializeEvaluations converts an array of scalars of size `scalarsPerCell` to [Cell].
 func serializeEvaluations(evals *[scalarsPerCell]fr.Element) *Cell {
 	var cell Cell
+	serializeScalars(cell[:], evals[:])
+	return &cell
+}
 
-	for i := 0; i < scalarsPerCell; i++ {
-		chunk := cell[i*SerializedScalarSize : (i+1)*SerializedScalarSize]
-		serScalar := SerializeScalar(evals[i])
+// serializeScalars writes `scalars` to `out` as a flattened list.
+//
+// Note: `out` must have room for exactly `len(scalars)` serialized scalars.
+func serializeScalars(out []byte, scalars []fr.Element) {
+	for i := range scalars {
+		chunk := out[i*SerializedScalarSize : (i+1)*SerializedScalarSize]
+		serScalar := SerializeScalar(scalars[i])
 		copy(chunk, serScalar[:])
 	}
-
-	return &cell
 }
 
-func deserializeCell(cell *Cell) ([]fr.Element, error) {
+// deserializeCell deserializes a cell whose size is given by the context parameters.
+func (ctx *Context) deserializeCell(cell []byte) ([]fr.Element, error) {
 	if cell == nil {
 		return nil, ErrDeserializeNilInput
 	}
-	evals := make([]fr.Element, scalarsPerCell)
-
-	for i := 0; i < scalarsPerCell; i++ {
-		chunk := cell[i*SerializedScalarSize : (i+1)*SerializedScalarSize]
+	if len(cell) != ctx.params.BytesPerCell() {
+		return nil, ErrInvalidCellLength
+	}
+	return deserializeScalars(cell)
+}
 
-		chunk_arr := [SerializedScalarSize]byte{}
-		copy(chunk_arr[:], chunk)
+// blobBytes returns the contents of a fixed-size [Blob] as a slice, or nil if the blob is nil.
+func blobBytes(blob *Blob) []byte {
+	if blob == nil {
+		return nil
+	}
+	return blob[:]
+}
 
-		eval, err := DeserializeScalar(chunk_arr)
-		if err != nil {
-			return nil, err
+// cellsBytes returns the contents of fixed-size [Cell]s as slices. Nil cells are returned as nil.
+func cellsBytes(cells []*Cell) [][]byte {
+	result := make([][]byte, len(cells))
+	for i, cell := range cells {
+		if cell != nil {
+			result[i] = cell[:]
 		}
-		evals[i] = eval
 	}
-
-	return evals, nil
+	return result
 }
@@ -0,0 +1,10 @@
+package ssz
+
+import "errors"
+
+var (
+	ErrUnexpectedSize = errors.New("ssz data does not have the size of the type")
+	ErrInvalidOffset  = errors.New("ssz offset is out of bounds or not in order")
+	ErrListTooLong    = errors.New("ssz list has more elements than its limit")
+	ErrNilElement     = errors.New("cannot serialize a nil blob or cell")
+)
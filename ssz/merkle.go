@@ -0,0 +1,83 @@
+package ssz
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// chunkSize is the number of bytes in a leaf of a merkle tree.
+const chunkSize = 32
+
+// maxDepth is the depth of the deepest tree that is merkleized, which is that of the elements of a list of
+// MaxBlobCommitmentsPerBlock elements and that of the chunks of a blob.
+const maxDepth = 12
+
+// zeroHashes[i] is the root of a tree of depth i whose leaves are all zero.
+var zeroHashes = func() [maxDepth + 1][chunkSize]byte {
+	var hashes [maxDepth + 1][chunkSize]byte
+	for i := 1; i <= maxDepth; i++ {
+		hashes[i] = hashPair(hashes[i-1], hashes[i-1])
+	}
+	return hashes
+}()
+
+func hashPair(left, right [chunkSize]byte) [chunkSize]byte {
+	var buf [2 * chunkSize]byte
+	copy(buf[:chunkSize], left[:])
+	copy(buf[chunkSize:], right[:])
+	return sha256.Sum256(buf[:])
+}
+
+// merkleize implements [merkleize]: it is the root of the tree whose leaves are the chunks, padded with zero chunks to
+// the next power of two of `limit`.
+//
+// The chunks are overwritten, and `limit` must not be less than the number of chunks.
+//
+// [merkleize]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/ssz/simple-serialize.md#merkleization
+func merkleize(chunks [][chunkSize]byte, limit int) [chunkSize]byte {
+	depth := 0
+	for 1<<depth < limit {
+		depth++
+	}
+	if len(chunks) == 0 {
+		return zeroHashes[depth]
+	}
+
+	// Only the nodes with a non-zero leaf below them are hashed, the others are zero hashes
+	layer := chunks
+	for d := 0; d < depth; d++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[d])
+		}
+		next := layer[:len(layer)/2]
+		for i := range next {
+			next[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+	return layer[0]
+}
+
+// merkleizeBytes is the hash tree root of a byte vector: the root of the bytes packed into chunks.
+func merkleizeBytes(data []byte) [chunkSize]byte {
+	numChunks := (len(data) + chunkSize - 1) / chunkSize
+	chunks := make([][chunkSize]byte, numChunks)
+	for i := range chunks {
+		copy(chunks[i][:], data[i*chunkSize:])
+	}
+	return merkleize(chunks, numChunks)
+}
+
+// mixInLength implements [mix_in_length], which turns the root of the elements of a list into the root of the list.
+//
+// [mix_in_length]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/ssz/simple-serialize.md#merkleization
+func mixInLength(root [chunkSize]byte, length int) [chunkSize]byte {
+	return hashPair(root, uint64Root(uint64(length)))
+}
+
+// uint64Root is the hash tree root of a uint64, which is its little-endian encoding padded to a chunk.
+func uint64Root(value uint64) [chunkSize]byte {
+	var chunk [chunkSize]byte
+	binary.LittleEndian.PutUint64(chunk[:], value)
+	return chunk
+}
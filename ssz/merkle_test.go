@@ -0,0 +1,127 @@
+package ssz
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/stretchr/testify/require"
+)
+
+// naiveMerkleize hashes every node of the tree, including the ones whose leaves are all zero.
+func naiveMerkleize(chunks [][chunkSize]byte, limit int) [chunkSize]byte {
+	size := 1
+	for size < limit {
+		size *= 2
+	}
+	layer := make([][chunkSize]byte, size)
+	copy(layer, chunks)
+	for len(layer) > 1 {
+		next := make([][chunkSize]byte, len(layer)/2)
+		for i := range next {
+			next[i] = sha256.Sum256(append(layer[2*i][:], layer[2*i+1][:]...))
+		}
+		layer = next
+	}
+	return layer[0]
+}
+
+func naiveMerkleizeBytes(data []byte) [chunkSize]byte {
+	chunks := make([][chunkSize]byte, (len(data)+chunkSize-1)/chunkSize)
+	for i := range chunks {
+		copy(chunks[i][:], data[i*chunkSize:])
+	}
+	return naiveMerkleize(chunks, len(chunks))
+}
+
+func naiveMixInLength(root [chunkSize]byte, length int) [chunkSize]byte {
+	var lengthChunk [chunkSize]byte
+	binary.LittleEndian.PutUint64(lengthChunk[:], uint64(length))
+	return sha256.Sum256(append(root[:], lengthChunk[:]...))
+}
+
+func randomChunks(rng *rand.Rand, n int) [][chunkSize]byte {
+	chunks := make([][chunkSize]byte, n)
+	for i := range chunks {
+		rng.Read(chunks[i][:])
+	}
+	return chunks
+}
+
+func TestZeroHashes(t *testing.T) {
+	require.Equal(t, [chunkSize]byte{}, zeroHashes[0])
+	require.Equal(t, "f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b", hex.EncodeToString(zeroHashes[1][:]))
+	require.Equal(t, "db56114e00fdd4c1f85c892bf35ac9a89289aaecb1ebd0a96cde606a748b5d71", hex.EncodeToString(zeroHashes[2][:]))
+}
+
+func TestMerkleize(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, limit := range []int{0, 1, 2, 3, 5, 17, 64, MaxBlobCommitmentsPerBlock} {
+		for _, numChunks := range []int{0, 1, 2, 3, 7, 16, 17, 64} {
+			if numChunks > limit {
+				continue
+			}
+			chunks := randomChunks(rng, numChunks)
+			expected := naiveMerkleize(chunks, limit)
+			require.Equal(t, expected, merkleize(chunks, limit), "limit %d, %d chunks", limit, numChunks)
+		}
+	}
+}
+
+// TestDataColumnSidecarHashTreeRoot computes the root of a sidecar from the definitions in the spec, without the
+// zero hashes.
+func TestDataColumnSidecarHashTreeRoot(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	sidecar := DataColumnSidecar{Index: 17}
+	for i := 0; i < 3; i++ {
+		var cell goethkzg.Cell
+		var commitment goethkzg.KZGCommitment
+		var proof goethkzg.KZGProof
+		rng.Read(cell[:])
+		rng.Read(commitment[:])
+		rng.Read(proof[:])
+		sidecar.Column = append(sidecar.Column, &cell)
+		sidecar.KZGCommitments = append(sidecar.KZGCommitments, commitment)
+		sidecar.KZGProofs = append(sidecar.KZGProofs, proof)
+	}
+	header := &sidecar.SignedBlockHeader
+	header.Message.Slot = 1234
+	header.Message.ProposerIndex = 56
+	rng.Read(header.Message.ParentRoot[:])
+	rng.Read(header.Message.StateRoot[:])
+	rng.Read(header.Message.BodyRoot[:])
+	rng.Read(header.Signature[:])
+	for i := range sidecar.KZGCommitmentsInclusionProof {
+		rng.Read(sidecar.KZGCommitmentsInclusionProof[i][:])
+	}
+
+	var cellRoots, commitmentRoots, proofRoots [][chunkSize]byte
+	for i := range sidecar.Column {
+		cellRoots = append(cellRoots, naiveMerkleizeBytes(sidecar.Column[i][:]))
+		commitmentRoots = append(commitmentRoots, naiveMerkleizeBytes(sidecar.KZGCommitments[i][:]))
+		proofRoots = append(proofRoots, naiveMerkleizeBytes(sidecar.KZGProofs[i][:]))
+	}
+	messageRoot := naiveMerkleize([][chunkSize]byte{
+		uint64Root(1234),
+		uint64Root(56),
+		header.Message.ParentRoot,
+		header.Message.StateRoot,
+		header.Message.BodyRoot,
+	}, 5)
+	headerRoot := naiveMerkleize([][chunkSize]byte{messageRoot, naiveMerkleizeBytes(header.Signature[:])}, 2)
+	expected := naiveMerkleize([][chunkSize]byte{
+		uint64Root(17),
+		naiveMixInLength(naiveMerkleize(cellRoots, MaxBlobCommitmentsPerBlock), 3),
+		naiveMixInLength(naiveMerkleize(commitmentRoots, MaxBlobCommitmentsPerBlock), 3),
+		naiveMixInLength(naiveMerkleize(proofRoots, MaxBlobCommitmentsPerBlock), 3),
+		headerRoot,
+		naiveMerkleize(sidecar.KZGCommitmentsInclusionProof[:], KZGCommitmentsInclusionProofDepth),
+	}, 6)
+
+	root, err := sidecar.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, expected, root)
+}
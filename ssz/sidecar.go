@@ -0,0 +1,383 @@
+package ssz
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+)
+
+// KZGCommitmentsInclusionProofDepth is the number of hashes in the proof that the commitments of a
+// [DataColumnSidecar] are in the body of its block.
+//
+// It matches [KZG_COMMITMENTS_INCLUSION_PROOF_DEPTH] in the spec.
+//
+// [KZG_COMMITMENTS_INCLUSION_PROOF_DEPTH]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/p2p-interface.md#preset
+const KZGCommitmentsInclusionProofDepth = 4
+
+// KZGCommitmentInclusionProofDepth is the number of hashes in the proof that the commitment of a [BlobSidecar] is in
+// the body of its block.
+//
+// It matches [KZG_COMMITMENT_INCLUSION_PROOF_DEPTH] in the spec.
+//
+// [KZG_COMMITMENT_INCLUSION_PROOF_DEPTH]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/p2p-interface.md#preset
+const KZGCommitmentInclusionProofDepth = 17
+
+// BLSSignatureSize is the number of bytes in a BLS signature.
+const BLSSignatureSize = 96
+
+const (
+	beaconBlockHeaderSize       = 8 + 8 + 32 + 32 + 32
+	signedBeaconBlockHeaderSize = beaconBlockHeaderSize + BLSSignatureSize
+
+	// offsetSize is the number of bytes in the offset of a variable-size field.
+	offsetSize = 4
+
+	// dataColumnSidecarFixedSize is the number of bytes before the first variable-size field of a
+	// DataColumnSidecar: the index, the offsets of the column, commitments and proofs, the block header and the
+	// inclusion proof.
+	dataColumnSidecarFixedSize = 8 + 3*offsetSize + signedBeaconBlockHeaderSize + KZGCommitmentsInclusionProofDepth*32
+
+	blobSidecarSize = 8 + goethkzg.ScalarsPerBlob*goethkzg.SerializedScalarSize + 2*goethkzg.CompressedG1Size +
+		signedBeaconBlockHeaderSize + KZGCommitmentInclusionProofDepth*32
+)
+
+// BeaconBlockHeader matches [BeaconBlockHeader] in the spec.
+//
+// [BeaconBlockHeader]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/phase0/beacon-chain.md#beaconblockheader
+type BeaconBlockHeader struct {
+	Slot          uint64
+	ProposerIndex uint64
+	ParentRoot    [32]byte
+	StateRoot     [32]byte
+	BodyRoot      [32]byte
+}
+
+// SizeSSZ returns the number of bytes in the encoding of the header.
+func (h *BeaconBlockHeader) SizeSSZ() int {
+	return beaconBlockHeaderSize
+}
+
+// MarshalSSZ returns the SSZ encoding of the header.
+func (h *BeaconBlockHeader) MarshalSSZ() ([]byte, error) {
+	return h.MarshalSSZTo(make([]byte, 0, beaconBlockHeaderSize))
+}
+
+// MarshalSSZTo appends the SSZ encoding of the header to `dst`.
+func (h *BeaconBlockHeader) MarshalSSZTo(dst []byte) ([]byte, error) {
+	dst = binary.LittleEndian.AppendUint64(dst, h.Slot)
+	dst = binary.LittleEndian.AppendUint64(dst, h.ProposerIndex)
+	dst = append(dst, h.ParentRoot[:]...)
+	dst = append(dst, h.StateRoot[:]...)
+	return append(dst, h.BodyRoot[:]...), nil
+}
+
+// UnmarshalSSZ decodes the header from its SSZ encoding.
+func (h *BeaconBlockHeader) UnmarshalSSZ(data []byte) error {
+	if err := checkSize(data, beaconBlockHeaderSize); err != nil {
+		return err
+	}
+	h.Slot = binary.LittleEndian.Uint64(data[0:8])
+	h.ProposerIndex = binary.LittleEndian.Uint64(data[8:16])
+	h.ParentRoot = [32]byte(data[16:48])
+	h.StateRoot = [32]byte(data[48:80])
+	h.BodyRoot = [32]byte(data[80:112])
+	return nil
+}
+
+// HashTreeRoot returns the hash tree root of the header.
+func (h *BeaconBlockHeader) HashTreeRoot() ([32]byte, error) {
+	fields := [][chunkSize]byte{
+		uint64Root(h.Slot),
+		uint64Root(h.ProposerIndex),
+		h.ParentRoot,
+		h.StateRoot,
+		h.BodyRoot,
+	}
+	return merkleize(fields, len(fields)), nil
+}
+
+// SignedBeaconBlockHeader matches [SignedBeaconBlockHeader] in the spec.
+//
+// [SignedBeaconBlockHeader]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/phase0/beacon-chain.md#signedbeaconblockheader
+type SignedBeaconBlockHeader struct {
+	Message   BeaconBlockHeader
+	Signature [BLSSignatureSize]byte
+}
+
+// SizeSSZ returns the number of bytes in the encoding of the header.
+func (h *SignedBeaconBlockHeader) SizeSSZ() int {
+	return signedBeaconBlockHeaderSize
+}
+
+// MarshalSSZ returns the SSZ encoding of the header.
+func (h *SignedBeaconBlockHeader) MarshalSSZ() ([]byte, error) {
+	return h.MarshalSSZTo(make([]byte, 0, signedBeaconBlockHeaderSize))
+}
+
+// MarshalSSZTo appends the SSZ encoding of the header to `dst`.
+func (h *SignedBeaconBlockHeader) MarshalSSZTo(dst []byte) ([]byte, error) {
+	dst, err := h.Message.MarshalSSZTo(dst)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, h.Signature[:]...), nil
+}
+
+// UnmarshalSSZ decodes the header from its SSZ encoding.
+func (h *SignedBeaconBlockHeader) UnmarshalSSZ(data []byte) error {
+	if err := checkSize(data, signedBeaconBlockHeaderSize); err != nil {
+		return err
+	}
+	if err := h.Message.UnmarshalSSZ(data[:beaconBlockHeaderSize]); err != nil {
+		return err
+	}
+	h.Signature = [BLSSignatureSize]byte(data[beaconBlockHeaderSize:])
+	return nil
+}
+
+// HashTreeRoot returns the hash tree root of the header.
+func (h *SignedBeaconBlockHeader) HashTreeRoot() ([32]byte, error) {
+	messageRoot, err := h.Message.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	fields := [][chunkSize]byte{messageRoot, merkleizeBytes(h.Signature[:])}
+	return merkleize(fields, len(fields)), nil
+}
+
+// DataColumnSidecar matches [DataColumnSidecar] in the spec.
+//
+// The index, cells, commitments and proofs are the inputs of [goethkzg.Context.VerifyDataColumn]. They can also be
+// passed to [goethkzg.Context.VerifyCellKZGProofBatch], with [DataColumnSidecar.CellIndices] as the cell indices.
+//
+// [DataColumnSidecar]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/das-core.md#datacolumnsidecar
+type DataColumnSidecar struct {
+	Index                        uint64
+	Column                       CellList
+	KZGCommitments               KZGCommitmentList
+	KZGProofs                    KZGProofList
+	SignedBlockHeader            SignedBeaconBlockHeader
+	KZGCommitmentsInclusionProof [KZGCommitmentsInclusionProofDepth][32]byte
+}
+
+// CellIndices returns the index of the cell of each row of the column, which is the index of the column.
+func (s *DataColumnSidecar) CellIndices() []uint64 {
+	cellIndices := make([]uint64, len(s.Column))
+	for i := range cellIndices {
+		cellIndices[i] = s.Index
+	}
+	return cellIndices
+}
+
+// SizeSSZ returns the number of bytes in the encoding of the sidecar.
+func (s *DataColumnSidecar) SizeSSZ() int {
+	return dataColumnSidecarFixedSize + s.Column.SizeSSZ() + s.KZGCommitments.SizeSSZ() + s.KZGProofs.SizeSSZ()
+}
+
+// MarshalSSZ returns the SSZ encoding of the sidecar.
+func (s *DataColumnSidecar) MarshalSSZ() ([]byte, error) {
+	return s.MarshalSSZTo(make([]byte, 0, s.SizeSSZ()))
+}
+
+// MarshalSSZTo appends the SSZ encoding of the sidecar to `dst`.
+func (s *DataColumnSidecar) MarshalSSZTo(dst []byte) ([]byte, error) {
+	dst = binary.LittleEndian.AppendUint64(dst, s.Index)
+
+	// The variable-size fields follow the fixed part, in order
+	offset := dataColumnSidecarFixedSize
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(offset))
+	offset += s.Column.SizeSSZ()
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(offset))
+	offset += s.KZGCommitments.SizeSSZ()
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(offset))
+
+	dst, err := s.SignedBlockHeader.MarshalSSZTo(dst)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range s.KZGCommitmentsInclusionProof {
+		dst = append(dst, node[:]...)
+	}
+
+	if dst, err = s.Column.MarshalSSZTo(dst); err != nil {
+		return nil, err
+	}
+	if dst, err = s.KZGCommitments.MarshalSSZTo(dst); err != nil {
+		return nil, err
+	}
+	return s.KZGProofs.MarshalSSZTo(dst)
+}
+
+// UnmarshalSSZ decodes the sidecar from its SSZ encoding.
+//
+// If the column is not a whole number of cells, the error wraps [goethkzg.ErrInvalidCellLength].
+func (s *DataColumnSidecar) UnmarshalSSZ(data []byte) error {
+	if len(data) < dataColumnSidecarFixedSize {
+		return fmt.Errorf("%w: expected at least %d bytes, found %d", ErrUnexpectedSize, dataColumnSidecarFixedSize, len(data))
+	}
+
+	columnOffset := int(binary.LittleEndian.Uint32(data[8:12]))
+	commitmentsOffset := int(binary.LittleEndian.Uint32(data[12:16]))
+	proofsOffset := int(binary.LittleEndian.Uint32(data[16:20]))
+	if columnOffset != dataColumnSidecarFixedSize {
+		return fmt.Errorf("%w: the first offset is %d, expected %d", ErrInvalidOffset, columnOffset, dataColumnSidecarFixedSize)
+	}
+	if commitmentsOffset < columnOffset || proofsOffset < commitmentsOffset || proofsOffset > len(data) {
+		return fmt.Errorf("%w: offsets %d, %d and %d for %d bytes", ErrInvalidOffset, columnOffset, commitmentsOffset, proofsOffset, len(data))
+	}
+
+	var sidecar DataColumnSidecar
+	sidecar.Index = binary.LittleEndian.Uint64(data[0:8])
+	if err := sidecar.SignedBlockHeader.UnmarshalSSZ(data[20 : 20+signedBeaconBlockHeaderSize]); err != nil {
+		return err
+	}
+	proof := data[20+signedBeaconBlockHeaderSize : dataColumnSidecarFixedSize]
+	for i := range sidecar.KZGCommitmentsInclusionProof {
+		sidecar.KZGCommitmentsInclusionProof[i] = [32]byte(proof[i*32:])
+	}
+
+	if err := sidecar.Column.UnmarshalSSZ(data[columnOffset:commitmentsOffset]); err != nil {
+		return fmt.Errorf("column: %w", err)
+	}
+	if err := sidecar.KZGCommitments.UnmarshalSSZ(data[commitmentsOffset:proofsOffset]); err != nil {
+		return fmt.Errorf("kzg_commitments: %w", err)
+	}
+	if err := sidecar.KZGProofs.UnmarshalSSZ(data[proofsOffset:]); err != nil {
+		return fmt.Errorf("kzg_proofs: %w", err)
+	}
+
+	*s = sidecar
+	return nil
+}
+
+// HashTreeRoot returns the hash tree root of the sidecar.
+func (s *DataColumnSidecar) HashTreeRoot() ([32]byte, error) {
+	columnRoot, err := s.Column.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	commitmentsRoot, err := s.KZGCommitments.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	proofsRoot, err := s.KZGProofs.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	headerRoot, err := s.SignedBlockHeader.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	inclusionProof := make([][chunkSize]byte, KZGCommitmentsInclusionProofDepth)
+	copy(inclusionProof, s.KZGCommitmentsInclusionProof[:])
+
+	fields := [][chunkSize]byte{
+		uint64Root(s.Index),
+		columnRoot,
+		commitmentsRoot,
+		proofsRoot,
+		headerRoot,
+		merkleize(inclusionProof, len(inclusionProof)),
+	}
+	return merkleize(fields, len(fields)), nil
+}
+
+// BlobSidecar matches [BlobSidecar] in the spec.
+//
+// [BlobSidecar]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/p2p-interface.md#blobsidecar
+type BlobSidecar struct {
+	Index                       uint64
+	Blob                        *goethkzg.Blob
+	KZGCommitment               goethkzg.KZGCommitment
+	KZGProof                    goethkzg.KZGProof
+	SignedBlockHeader           SignedBeaconBlockHeader
+	KZGCommitmentInclusionProof [KZGCommitmentInclusionProofDepth][32]byte
+}
+
+// SizeSSZ returns the number of bytes in the encoding of the sidecar.
+func (s *BlobSidecar) SizeSSZ() int {
+	return blobSidecarSize
+}
+
+// MarshalSSZ returns the SSZ encoding of the sidecar.
+func (s *BlobSidecar) MarshalSSZ() ([]byte, error) {
+	return s.MarshalSSZTo(make([]byte, 0, blobSidecarSize))
+}
+
+// MarshalSSZTo appends the SSZ encoding of the sidecar to `dst`.
+func (s *BlobSidecar) MarshalSSZTo(dst []byte) ([]byte, error) {
+	if s.Blob == nil {
+		return nil, fmt.Errorf("%w: blob", ErrNilElement)
+	}
+	dst = binary.LittleEndian.AppendUint64(dst, s.Index)
+	dst = append(dst, s.Blob[:]...)
+	dst = append(dst, s.KZGCommitment[:]...)
+	dst = append(dst, s.KZGProof[:]...)
+	dst, err := s.SignedBlockHeader.MarshalSSZTo(dst)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range s.KZGCommitmentInclusionProof {
+		dst = append(dst, node[:]...)
+	}
+	return dst, nil
+}
+
+// UnmarshalSSZ decodes the sidecar from its SSZ encoding.
+func (s *BlobSidecar) UnmarshalSSZ(data []byte) error {
+	if err := checkSize(data, blobSidecarSize); err != nil {
+		return err
+	}
+
+	var sidecar BlobSidecar
+	sidecar.Index = binary.LittleEndian.Uint64(data[0:8])
+	data = data[8:]
+	blob := goethkzg.Blob(data)
+	sidecar.Blob = &blob
+	data = data[len(blob):]
+	sidecar.KZGCommitment = goethkzg.KZGCommitment(data)
+	data = data[goethkzg.CompressedG1Size:]
+	sidecar.KZGProof = goethkzg.KZGProof(data)
+	data = data[goethkzg.CompressedG1Size:]
+	if err := sidecar.SignedBlockHeader.UnmarshalSSZ(data[:signedBeaconBlockHeaderSize]); err != nil {
+		return err
+	}
+	data = data[signedBeaconBlockHeaderSize:]
+	for i := range sidecar.KZGCommitmentInclusionProof {
+		sidecar.KZGCommitmentInclusionProof[i] = [32]byte(data[i*32:])
+	}
+
+	*s = sidecar
+	return nil
+}
+
+// HashTreeRoot returns the hash tree root of the sidecar.
+func (s *BlobSidecar) HashTreeRoot() ([32]byte, error) {
+	if s.Blob == nil {
+		return [32]byte{}, fmt.Errorf("%w: blob", ErrNilElement)
+	}
+	headerRoot, err := s.SignedBlockHeader.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	inclusionProof := make([][chunkSize]byte, KZGCommitmentInclusionProofDepth)
+	copy(inclusionProof, s.KZGCommitmentInclusionProof[:])
+
+	fields := [][chunkSize]byte{
+		uint64Root(s.Index),
+		merkleizeBytes(s.Blob[:]),
+		merkleizeBytes(s.KZGCommitment[:]),
+		merkleizeBytes(s.KZGProof[:]),
+		headerRoot,
+		merkleize(inclusionProof, len(inclusionProof)),
+	}
+	return merkleize(fields, len(fields)), nil
+}
+
+func checkSize(data []byte, size int) error {
+	if len(data) != size {
+		return fmt.Errorf("%w: expected %d bytes, found %d", ErrUnexpectedSize, size, len(data))
+	}
+	return nil
+}
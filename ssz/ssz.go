@@ -0,0 +1,211 @@
+// Package ssz implements the [SSZ] encoding and hash tree root of the lists and containers that the KZG types of the
+// library appear in on the consensus layer, so that sidecars can be decoded from the wire straight into the inputs of
+// the [goethkzg.Context] methods.
+//
+// The types have the methods of the SSZ code generators that Go consensus clients use: MarshalSSZ, MarshalSSZTo,
+// UnmarshalSSZ, SizeSSZ and HashTreeRoot.
+//
+// Decoding only checks the SSZ layout: that sizes, offsets and list lengths are within bounds. Whether the cells,
+// commitments and proofs are valid is checked by the [goethkzg.Context] methods that they are passed to.
+//
+// [SSZ]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/ssz/simple-serialize.md
+package ssz
+
+import (
+	"errors"
+	"fmt"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+)
+
+// MaxBlobCommitmentsPerBlock is the limit of the lists of cells, commitments and proofs.
+//
+// It matches [MAX_BLOB_COMMITMENTS_PER_BLOCK] in the spec.
+//
+// [MAX_BLOB_COMMITMENTS_PER_BLOCK]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/beacon-chain.md#execution
+const MaxBlobCommitmentsPerBlock = 4096
+
+// CellList is a List[Cell, MAX_BLOB_COMMITMENTS_PER_BLOCK], such as the column of a [DataColumnSidecar].
+//
+// It can be passed as the `cells` of [goethkzg.Context.VerifyCellKZGProofBatch].
+type CellList []*goethkzg.Cell
+
+// KZGCommitmentList is a List[KZGCommitment, MAX_BLOB_COMMITMENTS_PER_BLOCK].
+type KZGCommitmentList []goethkzg.KZGCommitment
+
+// KZGProofList is a List[KZGProof, MAX_BLOB_COMMITMENTS_PER_BLOCK].
+type KZGProofList []goethkzg.KZGProof
+
+// SizeSSZ returns the number of bytes in the encoding of the list.
+func (l CellList) SizeSSZ() int {
+	return len(l) * goethkzg.BytesPerCell
+}
+
+// MarshalSSZ returns the SSZ encoding of the list.
+func (l CellList) MarshalSSZ() ([]byte, error) {
+	return l.MarshalSSZTo(make([]byte, 0, l.SizeSSZ()))
+}
+
+// MarshalSSZTo appends the SSZ encoding of the list to `dst`.
+func (l CellList) MarshalSSZTo(dst []byte) ([]byte, error) {
+	if err := checkListLength(len(l)); err != nil {
+		return nil, err
+	}
+	for i, cell := range l {
+		if cell == nil {
+			return nil, fmt.Errorf("%w: cell %d", ErrNilElement, i)
+		}
+		dst = append(dst, cell[:]...)
+	}
+	return dst, nil
+}
+
+// UnmarshalSSZ decodes the list from its SSZ encoding.
+//
+// If the encoding is not a whole number of cells, the error wraps [goethkzg.ErrInvalidCellLength].
+func (l *CellList) UnmarshalSSZ(data []byte) error {
+	numCells, err := listLength(data, goethkzg.BytesPerCell)
+	if errors.Is(err, ErrUnexpectedSize) {
+		return fmt.Errorf("%w: %w", goethkzg.ErrInvalidCellLength, err)
+	}
+	if err != nil {
+		return err
+	}
+	// The cells are copied, so that the buffer can be reused once they are decoded
+	backing := make([]goethkzg.Cell, numCells)
+	cells := make(CellList, numCells)
+	for i := range cells {
+		backing[i] = goethkzg.Cell(data[i*goethkzg.BytesPerCell:])
+		cells[i] = &backing[i]
+	}
+	*l = cells
+	return nil
+}
+
+// HashTreeRoot returns the hash tree root of the list.
+func (l CellList) HashTreeRoot() ([32]byte, error) {
+	if err := checkListLength(len(l)); err != nil {
+		return [32]byte{}, err
+	}
+	roots := make([][chunkSize]byte, len(l))
+	for i, cell := range l {
+		if cell == nil {
+			return [32]byte{}, fmt.Errorf("%w: cell %d", ErrNilElement, i)
+		}
+		roots[i] = merkleizeBytes(cell[:])
+	}
+	return mixInLength(merkleize(roots, MaxBlobCommitmentsPerBlock), len(l)), nil
+}
+
+// SizeSSZ returns the number of bytes in the encoding of the list.
+func (l KZGCommitmentList) SizeSSZ() int {
+	return len(l) * goethkzg.CompressedG1Size
+}
+
+// MarshalSSZ returns the SSZ encoding of the list.
+func (l KZGCommitmentList) MarshalSSZ() ([]byte, error) {
+	return l.MarshalSSZTo(make([]byte, 0, l.SizeSSZ()))
+}
+
+// MarshalSSZTo appends the SSZ encoding of the list to `dst`.
+func (l KZGCommitmentList) MarshalSSZTo(dst []byte) ([]byte, error) {
+	if err := checkListLength(len(l)); err != nil {
+		return nil, err
+	}
+	for _, commitment := range l {
+		dst = append(dst, commitment[:]...)
+	}
+	return dst, nil
+}
+
+// UnmarshalSSZ decodes the list from its SSZ encoding.
+func (l *KZGCommitmentList) UnmarshalSSZ(data []byte) error {
+	numCommitments, err := listLength(data, goethkzg.CompressedG1Size)
+	if err != nil {
+		return err
+	}
+	commitments := make(KZGCommitmentList, numCommitments)
+	for i := range commitments {
+		commitments[i] = goethkzg.KZGCommitment(data[i*goethkzg.CompressedG1Size:])
+	}
+	*l = commitments
+	return nil
+}
+
+// HashTreeRoot returns the hash tree root of the list.
+func (l KZGCommitmentList) HashTreeRoot() ([32]byte, error) {
+	if err := checkListLength(len(l)); err != nil {
+		return [32]byte{}, err
+	}
+	roots := make([][chunkSize]byte, len(l))
+	for i, commitment := range l {
+		roots[i] = merkleizeBytes(commitment[:])
+	}
+	return mixInLength(merkleize(roots, MaxBlobCommitmentsPerBlock), len(l)), nil
+}
+
+// SizeSSZ returns the number of bytes in the encoding of the list.
+func (l KZGProofList) SizeSSZ() int {
+	return len(l) * goethkzg.CompressedG1Size
+}
+
+// MarshalSSZ returns the SSZ encoding of the list.
+func (l KZGProofList) MarshalSSZ() ([]byte, error) {
+	return l.MarshalSSZTo(make([]byte, 0, l.SizeSSZ()))
+}
+
+// MarshalSSZTo appends the SSZ encoding of the list to `dst`.
+func (l KZGProofList) MarshalSSZTo(dst []byte) ([]byte, error) {
+	if err := checkListLength(len(l)); err != nil {
+		return nil, err
+	}
+	for _, proof := range l {
+		dst = append(dst, proof[:]...)
+	}
+	return dst, nil
+}
+
+// UnmarshalSSZ decodes the list from its SSZ encoding.
+func (l *KZGProofList) UnmarshalSSZ(data []byte) error {
+	numProofs, err := listLength(data, goethkzg.CompressedG1Size)
+	if err != nil {
+		return err
+	}
+	proofs := make(KZGProofList, numProofs)
+	for i := range proofs {
+		proofs[i] = goethkzg.KZGProof(data[i*goethkzg.CompressedG1Size:])
+	}
+	*l = proofs
+	return nil
+}
+
+// HashTreeRoot returns the hash tree root of the list.
+func (l KZGProofList) HashTreeRoot() ([32]byte, error) {
+	if err := checkListLength(len(l)); err != nil {
+		return [32]byte{}, err
+	}
+	roots := make([][chunkSize]byte, len(l))
+	for i, proof := range l {
+		roots[i] = merkleizeBytes(proof[:])
+	}
+	return mixInLength(merkleize(roots, MaxBlobCommitmentsPerBlock), len(l)), nil
+}
+
+func checkListLength(length int) error {
+	if length > MaxBlobCommitmentsPerBlock {
+		return fmt.Errorf("%w: %d elements, the limit is %d", ErrListTooLong, length, MaxBlobCommitmentsPerBlock)
+	}
+	return nil
+}
+
+// listLength returns the number of elements in the encoding of a list whose elements are `elemSize` bytes.
+func listLength(data []byte, elemSize int) (int, error) {
+	if len(data)%elemSize != 0 {
+		return 0, fmt.Errorf("%w: %d bytes is not a multiple of %d", ErrUnexpectedSize, len(data), elemSize)
+	}
+	length := len(data) / elemSize
+	if err := checkListLength(length); err != nil {
+		return 0, err
+	}
+	return length, nil
+}
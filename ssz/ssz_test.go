@@ -0,0 +1,241 @@
+package ssz_test
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/crate-crypto/go-eth-kzg/ssz"
+	"github.com/stretchr/testify/require"
+)
+
+const numGoRoutines = 0
+
+func randomBlob(rng *rand.Rand) *goethkzg.Blob {
+	var blob goethkzg.Blob
+	for i := 0; i < goethkzg.ScalarsPerBlob; i++ {
+		// Leave the top byte zero, so that every scalar is canonical
+		rng.Read(blob[i*goethkzg.SerializedScalarSize+1 : (i+1)*goethkzg.SerializedScalarSize])
+	}
+	return &blob
+}
+
+func randomHeader(rng *rand.Rand) ssz.SignedBeaconBlockHeader {
+	header := ssz.SignedBeaconBlockHeader{}
+	header.Message.Slot = rng.Uint64()
+	header.Message.ProposerIndex = rng.Uint64()
+	rng.Read(header.Message.ParentRoot[:])
+	rng.Read(header.Message.StateRoot[:])
+	rng.Read(header.Message.BodyRoot[:])
+	rng.Read(header.Signature[:])
+	return header
+}
+
+// dataColumnSidecar returns the sidecar of a column of `numBlobs` random blobs.
+func dataColumnSidecar(t *testing.T, ctx *goethkzg.Context, rng *rand.Rand, columnIndex uint64, numBlobs int) *ssz.DataColumnSidecar {
+	t.Helper()
+	sidecar := &ssz.DataColumnSidecar{Index: columnIndex, SignedBlockHeader: randomHeader(rng)}
+	for i := 0; i < numBlobs; i++ {
+		blob := randomBlob(rng)
+		commitment, err := ctx.BlobToKZGCommitment(blob, numGoRoutines)
+		require.NoError(t, err)
+		cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob, numGoRoutines)
+		require.NoError(t, err)
+		sidecar.Column = append(sidecar.Column, cells[columnIndex])
+		sidecar.KZGCommitments = append(sidecar.KZGCommitments, commitment)
+		sidecar.KZGProofs = append(sidecar.KZGProofs, proofs[columnIndex])
+	}
+	for i := range sidecar.KZGCommitmentsInclusionProof {
+		rng.Read(sidecar.KZGCommitmentsInclusionProof[i][:])
+	}
+	return sidecar
+}
+
+func TestDataColumnSidecar(t *testing.T) {
+	ctx, err := goethkzg.NewContext4096Secure()
+	require.NoError(t, err)
+	rng := rand.New(rand.NewSource(1))
+	sidecar := dataColumnSidecar(t, ctx, rng, 5, 3)
+
+	encoded, err := sidecar.MarshalSSZ()
+	require.NoError(t, err)
+	require.Len(t, encoded, sidecar.SizeSSZ())
+	require.Equal(t, uint64(5), binary.LittleEndian.Uint64(encoded))
+
+	// The decoded sidecar can be passed straight to the verifier
+	var decoded ssz.DataColumnSidecar
+	require.NoError(t, decoded.UnmarshalSSZ(encoded))
+	require.Equal(t, *sidecar, decoded)
+	require.Equal(t, []uint64{5, 5, 5}, decoded.CellIndices())
+	require.NoError(t, ctx.VerifyCellKZGProofBatch(decoded.KZGCommitments, decoded.CellIndices(), decoded.Column, decoded.KZGProofs))
+	require.NoError(t, ctx.VerifyDataColumn(decoded.Index, decoded.KZGCommitments, decoded.Column, decoded.KZGProofs))
+
+	// Decoding copies the cells out of the buffer
+	encoded[len(encoded)-decoded.KZGProofs.SizeSSZ()-1] ^= 1
+	require.Equal(t, *sidecar, decoded)
+
+	root, err := sidecar.HashTreeRoot()
+	require.NoError(t, err)
+	decodedRoot, err := decoded.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, root, decodedRoot)
+
+	// Every field is part of the root
+	modified := *sidecar
+	modified.KZGCommitmentsInclusionProof[3][0] ^= 1
+	modifiedRoot, err := modified.HashTreeRoot()
+	require.NoError(t, err)
+	require.NotEqual(t, root, modifiedRoot)
+	modified = *sidecar
+	modified.KZGProofs = modified.KZGProofs[:2]
+	modifiedRoot, err = modified.HashTreeRoot()
+	require.NoError(t, err)
+	require.NotEqual(t, root, modifiedRoot)
+
+	t.Run("empty", func(t *testing.T) {
+		empty := ssz.DataColumnSidecar{}
+		encoded, err := empty.MarshalSSZ()
+		require.NoError(t, err)
+		require.Len(t, encoded, 356)
+
+		var decoded ssz.DataColumnSidecar
+		require.NoError(t, decoded.UnmarshalSSZ(encoded))
+		require.Empty(t, decoded.Column)
+		require.Empty(t, decoded.KZGCommitments)
+		require.Empty(t, decoded.KZGProofs)
+	})
+}
+
+func TestDataColumnSidecarMalformed(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	sidecar := &ssz.DataColumnSidecar{Index: 3, SignedBlockHeader: randomHeader(rng)}
+	for i := 0; i < 2; i++ {
+		var cell goethkzg.Cell
+		rng.Read(cell[:])
+		sidecar.Column = append(sidecar.Column, &cell)
+		sidecar.KZGCommitments = append(sidecar.KZGCommitments, goethkzg.KZGCommitment{})
+		sidecar.KZGProofs = append(sidecar.KZGProofs, goethkzg.KZGProof{})
+	}
+	encoded, err := sidecar.MarshalSSZ()
+	require.NoError(t, err)
+	setOffset := func(index int, offset int) []byte {
+		data := append([]byte(nil), encoded...)
+		binary.LittleEndian.PutUint32(data[8+4*index:], uint32(offset))
+		return data
+	}
+	columnOffset := 356
+	commitmentsOffset := columnOffset + 2*goethkzg.BytesPerCell
+
+	var decoded ssz.DataColumnSidecar
+	require.ErrorIs(t, decoded.UnmarshalSSZ(encoded[:355]), ssz.ErrUnexpectedSize)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(nil), ssz.ErrUnexpectedSize)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(encoded[:len(encoded)-1]), ssz.ErrUnexpectedSize)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(encoded[:commitmentsOffset+48]), ssz.ErrInvalidOffset)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(setOffset(0, columnOffset+1)), ssz.ErrInvalidOffset)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(setOffset(1, columnOffset-1)), ssz.ErrInvalidOffset)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(setOffset(2, commitmentsOffset-1)), ssz.ErrInvalidOffset)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(setOffset(2, len(encoded)+1)), ssz.ErrInvalidOffset)
+
+	err = decoded.UnmarshalSSZ(setOffset(1, commitmentsOffset-1))
+	require.ErrorIs(t, err, ssz.ErrUnexpectedSize)
+	require.ErrorIs(t, err, goethkzg.ErrInvalidCellLength)
+
+	// A failed decoding leaves the sidecar as it was
+	require.Equal(t, ssz.DataColumnSidecar{}, decoded)
+
+	t.Run("nil cell", func(t *testing.T) {
+		withNil := *sidecar
+		withNil.Column = ssz.CellList{sidecar.Column[0], nil}
+		_, err := withNil.MarshalSSZ()
+		require.ErrorIs(t, err, ssz.ErrNilElement)
+		_, err = withNil.HashTreeRoot()
+		require.ErrorIs(t, err, ssz.ErrNilElement)
+	})
+}
+
+func TestListLimit(t *testing.T) {
+	commitments := make(ssz.KZGCommitmentList, ssz.MaxBlobCommitmentsPerBlock)
+	encoded, err := commitments.MarshalSSZ()
+	require.NoError(t, err)
+	var decoded ssz.KZGCommitmentList
+	require.NoError(t, decoded.UnmarshalSSZ(encoded))
+	require.Equal(t, commitments, decoded)
+
+	tooMany := append(commitments, goethkzg.KZGCommitment{})
+	_, err = tooMany.MarshalSSZ()
+	require.ErrorIs(t, err, ssz.ErrListTooLong)
+	_, err = tooMany.HashTreeRoot()
+	require.ErrorIs(t, err, ssz.ErrListTooLong)
+	encoded = append(encoded, make([]byte, goethkzg.CompressedG1Size)...)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(encoded), ssz.ErrListTooLong)
+
+	var proofs ssz.KZGProofList
+	require.ErrorIs(t, proofs.UnmarshalSSZ(encoded), ssz.ErrListTooLong)
+	require.ErrorIs(t, proofs.UnmarshalSSZ(make([]byte, 47)), ssz.ErrUnexpectedSize)
+
+	var cells ssz.CellList
+	require.ErrorIs(t, cells.UnmarshalSSZ(make([]byte, (ssz.MaxBlobCommitmentsPerBlock+1)*goethkzg.BytesPerCell)), ssz.ErrListTooLong)
+}
+
+func TestBlobSidecar(t *testing.T) {
+	ctx, err := goethkzg.NewContext4096Secure()
+	require.NoError(t, err)
+	rng := rand.New(rand.NewSource(3))
+
+	blob := randomBlob(rng)
+	commitment, err := ctx.BlobToKZGCommitment(blob, numGoRoutines)
+	require.NoError(t, err)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, numGoRoutines)
+	require.NoError(t, err)
+	sidecar := &ssz.BlobSidecar{
+		Index:             2,
+		Blob:              blob,
+		KZGCommitment:     commitment,
+		KZGProof:          proof,
+		SignedBlockHeader: randomHeader(rng),
+	}
+	for i := range sidecar.KZGCommitmentInclusionProof {
+		rng.Read(sidecar.KZGCommitmentInclusionProof[i][:])
+	}
+
+	encoded, err := sidecar.MarshalSSZ()
+	require.NoError(t, err)
+	require.Len(t, encoded, 131928)
+	require.Len(t, encoded, sidecar.SizeSSZ())
+
+	var decoded ssz.BlobSidecar
+	require.NoError(t, decoded.UnmarshalSSZ(encoded))
+	require.Equal(t, *sidecar, decoded)
+	require.NoError(t, ctx.VerifyBlobKZGProof(decoded.Blob, decoded.KZGCommitment, decoded.KZGProof))
+
+	root, err := sidecar.HashTreeRoot()
+	require.NoError(t, err)
+	decoded.KZGCommitmentInclusionProof[16][31] ^= 1
+	modifiedRoot, err := decoded.HashTreeRoot()
+	require.NoError(t, err)
+	require.NotEqual(t, root, modifiedRoot)
+
+	require.ErrorIs(t, decoded.UnmarshalSSZ(encoded[1:]), ssz.ErrUnexpectedSize)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(append(encoded, 0)), ssz.ErrUnexpectedSize)
+
+	_, err = (&ssz.BlobSidecar{}).MarshalSSZ()
+	require.ErrorIs(t, err, ssz.ErrNilElement)
+	_, err = (&ssz.BlobSidecar{}).HashTreeRoot()
+	require.ErrorIs(t, err, ssz.ErrNilElement)
+}
+
+func TestSignedBeaconBlockHeader(t *testing.T) {
+	header := randomHeader(rand.New(rand.NewSource(4)))
+	encoded, err := header.MarshalSSZ()
+	require.NoError(t, err)
+	require.Len(t, encoded, 208)
+	require.Len(t, encoded, header.SizeSSZ())
+	require.Equal(t, header.Message.Slot, binary.LittleEndian.Uint64(encoded))
+	require.Equal(t, header.Signature[:], encoded[112:])
+
+	var decoded ssz.SignedBeaconBlockHeader
+	require.NoError(t, decoded.UnmarshalSSZ(encoded))
+	require.Equal(t, header, decoded)
+	require.ErrorIs(t, decoded.UnmarshalSSZ(encoded[:207]), ssz.ErrUnexpectedSize)
+}
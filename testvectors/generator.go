@@ -0,0 +1,247 @@
+// Package testvectors writes test vectors in the format of the [consensus-specs] KZG tests, which are the vectors
+// that are under tests/ in this repository.
+//
+// Each test case is a data.yaml file with an `input` and an `output` section, which is written to
+//
+//	<dir>/<function>/<preset>/<function>_case_<name>/data.yaml
+//
+// The output is computed with a [goethkzg.Context]. As in the spec, the output is null if the function fails on the
+// input, which lets invalid inputs be turned into test vectors too.
+//
+// [consensus-specs]: https://github.com/ethereum/consensus-specs/tree/dev/tests/formats
+package testvectors
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+)
+
+// Names of the functions in the spec, which are also the names of the directories of their test vectors.
+const (
+	BlobToKZGCommitment      = "blob_to_kzg_commitment"
+	ComputeKZGProof          = "compute_kzg_proof"
+	ComputeBlobKZGProof      = "compute_blob_kzg_proof"
+	VerifyKZGProof           = "verify_kzg_proof"
+	VerifyBlobKZGProof       = "verify_blob_kzg_proof"
+	VerifyBlobKZGProofBatch  = "verify_blob_kzg_proof_batch"
+	ComputeCellsAndKZGProofs = "compute_cells_and_kzg_proofs"
+	VerifyCellKZGProofBatch  = "verify_cell_kzg_proof_batch"
+	RecoverCellsAndKZGProofs = "recover_cells_and_kzg_proofs"
+)
+
+// MainnetPreset is the name of the directory that holds the test vectors for [goethkzg.MainnetParams].
+const MainnetPreset = "kzg-mainnet"
+
+// numGoRoutines lets the library use all of the available CPUs.
+const numGoRoutines = 0
+
+// Generator writes test vectors for the spec functions to a directory.
+//
+// The inputs are given as bytes rather than as the fixed-size types of the library, so that inputs with the wrong
+// length can be written as well.
+type Generator struct {
+	ctx    *goethkzg.Context
+	dir    string
+	preset string
+}
+
+// NewGenerator creates a generator that computes the outputs with `ctx` and writes the test vectors under `dir`, in
+// the directory of the `preset`, such as [MainnetPreset].
+func NewGenerator(ctx *goethkzg.Context, dir, preset string) *Generator {
+	return &Generator{ctx: ctx, dir: dir, preset: preset}
+}
+
+// CaseID returns an identifier for a test case from its inputs, which can be appended to the name of the case to
+// keep the names of cases with different inputs apart. This is how the spec names most of its cases.
+func CaseID(inputs ...[]byte) string {
+	h := sha256.New()
+	for _, input := range inputs {
+		h.Write(input)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// BlobToKZGCommitment writes a test case for [blob_to_kzg_commitment].
+//
+// [blob_to_kzg_commitment]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob_to_kzg_commitment
+func (g *Generator) BlobToKZGCommitment(name string, blob []byte) error {
+	var output any
+	if commitment, err := g.ctx.BlobToKZGCommitmentBytes(blob, numGoRoutines); err == nil {
+		output = commitment[:]
+	}
+	return g.writeCase(BlobToKZGCommitment, name, []field{{"blob", blob}}, output)
+}
+
+// ComputeKZGProof writes a test case for [compute_kzg_proof]. The output is the proof and the evaluation at `z`.
+//
+// [compute_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_kzg_proof
+func (g *Generator) ComputeKZGProof(name string, blob, z []byte) error {
+	var output any
+	if len(z) == goethkzg.SerializedScalarSize {
+		proof, y, err := g.ctx.ComputeKZGProofBytes(blob, goethkzg.Scalar(z), numGoRoutines)
+		if err == nil {
+			output = [2][]byte{proof[:], y[:]}
+		}
+	}
+	return g.writeCase(ComputeKZGProof, name, []field{{"blob", blob}, {"z", z}}, output)
+}
+
+// ComputeBlobKZGProof writes a test case for [compute_blob_kzg_proof].
+//
+// [compute_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_blob_kzg_proof
+func (g *Generator) ComputeBlobKZGProof(name string, blob, commitment []byte) error {
+	var output any
+	if len(commitment) == goethkzg.CompressedG1Size {
+		proof, err := g.ctx.ComputeBlobKZGProofBytes(blob, goethkzg.KZGCommitment(commitment), numGoRoutines)
+		if err == nil {
+			output = proof[:]
+		}
+	}
+	return g.writeCase(ComputeBlobKZGProof, name, []field{{"blob", blob}, {"commitment", commitment}}, output)
+}
+
+// VerifyKZGProof writes a test case for [verify_kzg_proof].
+//
+// [verify_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof
+func (g *Generator) VerifyKZGProof(name string, commitment, z, y, proof []byte) error {
+	var output any
+	if len(commitment) == goethkzg.CompressedG1Size && len(z) == goethkzg.SerializedScalarSize &&
+		len(y) == goethkzg.SerializedScalarSize && len(proof) == goethkzg.CompressedG1Size {
+		output = verificationOutput(g.ctx.VerifyKZGProof(goethkzg.KZGCommitment(commitment), goethkzg.Scalar(z), goethkzg.Scalar(y), goethkzg.KZGProof(proof)))
+	}
+	input := []field{{"commitment", commitment}, {"z", z}, {"y", y}, {"proof", proof}}
+	return g.writeCase(VerifyKZGProof, name, input, output)
+}
+
+// VerifyBlobKZGProof writes a test case for [verify_blob_kzg_proof].
+//
+// [verify_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof
+func (g *Generator) VerifyBlobKZGProof(name string, blob, commitment, proof []byte) error {
+	var output any
+	if len(commitment) == goethkzg.CompressedG1Size && len(proof) == goethkzg.CompressedG1Size {
+		output = verificationOutput(g.ctx.VerifyBlobKZGProofBytes(blob, goethkzg.KZGCommitment(commitment), goethkzg.KZGProof(proof)))
+	}
+	input := []field{{"blob", blob}, {"commitment", commitment}, {"proof", proof}}
+	return g.writeCase(VerifyBlobKZGProof, name, input, output)
+}
+
+// VerifyBlobKZGProofBatch writes a test case for [verify_blob_kzg_proof_batch].
+//
+// [verify_blob_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
+func (g *Generator) VerifyBlobKZGProofBatch(name string, blobs, commitments, proofs [][]byte) error {
+	var output any
+	serCommitments, commitmentsOk := toCommitments(commitments)
+	serProofs, proofsOk := toProofs(proofs)
+	if commitmentsOk && proofsOk {
+		output = verificationOutput(g.ctx.VerifyBlobKZGProofBatchBytes(blobs, serCommitments, serProofs))
+	}
+	input := []field{{"blobs", blobs}, {"commitments", commitments}, {"proofs", proofs}}
+	return g.writeCase(VerifyBlobKZGProofBatch, name, input, output)
+}
+
+// ComputeCellsAndKZGProofs writes a test case for [compute_cells_and_kzg_proofs]. The output is the cells and their
+// proofs.
+//
+// [compute_cells_and_kzg_proofs]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_cells_and_kzg_proofs
+func (g *Generator) ComputeCellsAndKZGProofs(name string, blob []byte) error {
+	var output any
+	if cells, proofs, err := g.ctx.ComputeCellsAndKZGProofsBytes(blob, numGoRoutines); err == nil {
+		output = cellsAndProofsOutput(cells, proofs)
+	}
+	return g.writeCase(ComputeCellsAndKZGProofs, name, []field{{"blob", blob}}, output)
+}
+
+// VerifyCellKZGProofBatch writes a test case for [verify_cell_kzg_proof_batch].
+//
+// [verify_cell_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#verify_cell_kzg_proof_batch
+func (g *Generator) VerifyCellKZGProofBatch(name string, commitments [][]byte, cellIndices []uint64, cells, proofs [][]byte) error {
+	var output any
+	serCommitments, commitmentsOk := toCommitments(commitments)
+	serProofs, proofsOk := toProofs(proofs)
+	if commitmentsOk && proofsOk {
+		output = verificationOutput(g.ctx.VerifyCellKZGProofBatchBytes(serCommitments, cellIndices, cells, serProofs))
+	}
+	input := []field{{"commitments", commitments}, {"cell_indices", cellIndices}, {"cells", cells}, {"proofs", proofs}}
+	return g.writeCase(VerifyCellKZGProofBatch, name, input, output)
+}
+
+// RecoverCellsAndKZGProofs writes a test case for [recover_cells_and_kzg_proofs]. The output is all of the cells and
+// their proofs.
+//
+// [recover_cells_and_kzg_proofs]: https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#recover_cells_and_kzg_proofs
+func (g *Generator) RecoverCellsAndKZGProofs(name string, cellIndices []uint64, cells [][]byte) error {
+	var output any
+	if recoveredCells, proofs, err := g.ctx.RecoverCellsAndComputeKZGProofsBytes(cellIndices, cells, numGoRoutines); err == nil {
+		output = cellsAndProofsOutput(recoveredCells, proofs)
+	}
+	input := []field{{"cell_indices", cellIndices}, {"cells", cells}}
+	return g.writeCase(RecoverCellsAndKZGProofs, name, input, output)
+}
+
+// writeCase writes the data.yaml file of a test case.
+func (g *Generator) writeCase(function, name string, input []field, output any) error {
+	if name == "" {
+		return errors.New("testvectors: the test case has no name")
+	}
+
+	var buf bytes.Buffer
+	if err := writeTestCase(&buf, input, output); err != nil {
+		return err
+	}
+
+	caseDir := filepath.Join(g.dir, function, g.preset, function+"_case_"+name)
+	if err := os.MkdirAll(caseDir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(caseDir, "data.yaml"), buf.Bytes(), 0o644)
+}
+
+// verificationOutput turns the result of a verifier into the output of a test case: true if the proofs verify,
+// false if they do not, and null if the inputs are invalid.
+func verificationOutput(err error) any {
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, goethkzg.ErrVerifyOpeningProof) {
+		return false
+	}
+	return nil
+}
+
+func cellsAndProofsOutput(cells [][]byte, proofs []goethkzg.KZGProof) [2][][]byte {
+	serProofs := make([][]byte, len(proofs))
+	for i := range proofs {
+		serProofs[i] = proofs[i][:]
+	}
+	return [2][][]byte{cells, serProofs}
+}
+
+// toCommitments converts the commitments to the library type, and returns false if any has the wrong length.
+func toCommitments(commitments [][]byte) ([]goethkzg.KZGCommitment, bool) {
+	serCommitments := make([]goethkzg.KZGCommitment, len(commitments))
+	for i, commitment := range commitments {
+		if len(commitment) != goethkzg.CompressedG1Size {
+			return nil, false
+		}
+		serCommitments[i] = goethkzg.KZGCommitment(commitment)
+	}
+	return serCommitments, true
+}
+
+// toProofs converts the proofs to the library type, and returns false if any has the wrong length.
+func toProofs(proofs [][]byte) ([]goethkzg.KZGProof, bool) {
+	serProofs := make([]goethkzg.KZGProof, len(proofs))
+	for i, proof := range proofs {
+		if len(proof) != goethkzg.CompressedG1Size {
+			return nil, false
+		}
+		serProofs[i] = goethkzg.KZGProof(proof)
+	}
+	return serProofs, true
+}
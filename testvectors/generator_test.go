@@ -0,0 +1,171 @@
+package testvectors_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/crate-crypto/go-eth-kzg/testvectors"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// specTestDir holds the test vectors from the consensus-specs.
+const specTestDir = "../tests"
+
+// testCase is a data.yaml file, decoded without assuming anything about its layout.
+type testCase struct {
+	Input  map[string]any `yaml:"input"`
+	Output any            `yaml:"output"`
+}
+
+func readTestCase(t *testing.T, path string) testCase {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var test testCase
+	require.NoError(t, yaml.NewDecoder(file).Decode(&test))
+	return test
+}
+
+func hexField(t *testing.T, input map[string]any, key string) []byte {
+	t.Helper()
+	byts, err := hex.DecodeString(strings.TrimPrefix(input[key].(string), "0x"))
+	require.NoError(t, err)
+	return byts
+}
+
+func hexListField(t *testing.T, input map[string]any, key string) [][]byte {
+	t.Helper()
+	var list [][]byte
+	for _, item := range input[key].([]any) {
+		byts, err := hex.DecodeString(strings.TrimPrefix(item.(string), "0x"))
+		require.NoError(t, err)
+		list = append(list, byts)
+	}
+	return list
+}
+
+func indexListField(input map[string]any, key string) []uint64 {
+	var list []uint64
+	for _, item := range input[key].([]any) {
+		list = append(list, uint64(item.(int)))
+	}
+	return list
+}
+
+// TestRegenerateSpecVectors checks that feeding the inputs of the spec vectors to the generator reproduces them.
+func TestRegenerateSpecVectors(t *testing.T) {
+	ctx, err := goethkzg.NewContext4096Secure()
+	require.NoError(t, err)
+	outDir := t.TempDir()
+	generator := testvectors.NewGenerator(ctx, outDir, testvectors.MainnetPreset)
+
+	generate := map[string]func(name string, input map[string]any) error{
+		testvectors.BlobToKZGCommitment: func(name string, input map[string]any) error {
+			return generator.BlobToKZGCommitment(name, hexField(t, input, "blob"))
+		},
+		testvectors.ComputeKZGProof: func(name string, input map[string]any) error {
+			return generator.ComputeKZGProof(name, hexField(t, input, "blob"), hexField(t, input, "z"))
+		},
+		testvectors.ComputeBlobKZGProof: func(name string, input map[string]any) error {
+			return generator.ComputeBlobKZGProof(name, hexField(t, input, "blob"), hexField(t, input, "commitment"))
+		},
+		testvectors.VerifyKZGProof: func(name string, input map[string]any) error {
+			return generator.VerifyKZGProof(name, hexField(t, input, "commitment"), hexField(t, input, "z"), hexField(t, input, "y"), hexField(t, input, "proof"))
+		},
+		testvectors.VerifyBlobKZGProof: func(name string, input map[string]any) error {
+			return generator.VerifyBlobKZGProof(name, hexField(t, input, "blob"), hexField(t, input, "commitment"), hexField(t, input, "proof"))
+		},
+		testvectors.VerifyBlobKZGProofBatch: func(name string, input map[string]any) error {
+			return generator.VerifyBlobKZGProofBatch(name, hexListField(t, input, "blobs"), hexListField(t, input, "commitments"), hexListField(t, input, "proofs"))
+		},
+		testvectors.ComputeCellsAndKZGProofs: func(name string, input map[string]any) error {
+			return generator.ComputeCellsAndKZGProofs(name, hexField(t, input, "blob"))
+		},
+		testvectors.VerifyCellKZGProofBatch: func(name string, input map[string]any) error {
+			return generator.VerifyCellKZGProofBatch(name, hexListField(t, input, "commitments"), indexListField(input, "cell_indices"), hexListField(t, input, "cells"), hexListField(t, input, "proofs"))
+		},
+		testvectors.RecoverCellsAndKZGProofs: func(name string, input map[string]any) error {
+			return generator.RecoverCellsAndKZGProofs(name, indexListField(input, "cell_indices"), hexListField(t, input, "cells"))
+		},
+	}
+
+	for function, generateCase := range generate {
+		specCases, err := filepath.Glob(filepath.Join(specTestDir, function, testvectors.MainnetPreset, "*", "data.yaml"))
+		require.NoError(t, err)
+		require.NotEmpty(t, specCases)
+
+		for _, specPath := range specCases {
+			caseDir := filepath.Base(filepath.Dir(specPath))
+			t.Run(caseDir, func(t *testing.T) {
+				expected := readTestCase(t, specPath)
+				name := strings.TrimPrefix(caseDir, function+"_case_")
+				require.NoError(t, generateCase(name, expected.Input))
+
+				generated := readTestCase(t, filepath.Join(outDir, function, testvectors.MainnetPreset, caseDir, "data.yaml"))
+				require.Equal(t, expected, generated)
+			})
+		}
+	}
+}
+
+func TestGenerateRandom(t *testing.T) {
+	params := goethkzg.Params{
+		FieldElementsPerBlob: 64,
+		FieldElementsPerCell: 8,
+		ExpansionFactor:      2,
+	}
+	ctx, err := goethkzg.NewInsecureContextForTesting(big.NewInt(1337), params)
+	require.NoError(t, err)
+
+	generateDir := func(seed int64) string {
+		dir := t.TempDir()
+		generator := testvectors.NewGenerator(ctx, dir, "kzg-test")
+		require.NoError(t, generator.GenerateRandom(rand.New(rand.NewSource(seed)), 2))
+		return dir
+	}
+	dir := generateDir(1)
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "kzg-test", "*", "data.yaml"))
+	require.NoError(t, err)
+	functions := make(map[string]bool)
+	for _, path := range paths {
+		caseDir := filepath.Base(filepath.Dir(path))
+		function := filepath.Base(filepath.Dir(filepath.Dir(filepath.Dir(path))))
+		functions[function] = true
+		name := strings.TrimPrefix(caseDir, function+"_case_")
+
+		// The name of each case says what its output should be
+		test := readTestCase(t, path)
+		switch {
+		case strings.HasPrefix(name, "valid_"), strings.HasPrefix(name, "correct_"):
+			require.NotNil(t, test.Output, caseDir)
+			require.NotEqual(t, false, test.Output, caseDir)
+		case strings.HasPrefix(name, "incorrect_"):
+			require.Equal(t, false, test.Output, caseDir)
+		default:
+			require.Nil(t, test.Output, caseDir)
+		}
+	}
+	require.Len(t, functions, 9)
+
+	// The same seed gives the same vectors
+	otherDir := generateDir(1)
+	for _, path := range paths {
+		relPath, err := filepath.Rel(dir, path)
+		require.NoError(t, err)
+		expected, err := os.ReadFile(path)
+		require.NoError(t, err)
+		actual, err := os.ReadFile(filepath.Join(otherDir, relPath))
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+}
@@ -0,0 +1,266 @@
+package testvectors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"math/rand"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+)
+
+// RandomScalar returns a random canonical field element.
+func RandomScalar(rng *rand.Rand) []byte {
+	var byts [goethkzg.SerializedScalarSize]byte
+	rng.Read(byts[:])
+
+	// SetBytes reduces the value modulo the field order
+	var element fr.Element
+	element.SetBytes(byts[:])
+	scalar := element.Bytes()
+	return scalar[:]
+}
+
+// NonCanonicalScalar returns a random value that is at least the field order, which is not a valid field element.
+func NonCanonicalScalar(rng *rand.Rand) []byte {
+	value := new(big.Int).SetBytes(goethkzg.BlsModulus[:])
+	value.Add(value, big.NewInt(rng.Int63n(1<<32)))
+	return value.FillBytes(make([]byte, goethkzg.SerializedScalarSize))
+}
+
+// RandomBlob returns a blob of random canonical field elements, with the size given by `params`.
+func RandomBlob(rng *rand.Rand, params goethkzg.Params) []byte {
+	blob := make([]byte, 0, params.BytesPerBlob())
+	for i := uint64(0); i < params.FieldElementsPerBlob; i++ {
+		blob = append(blob, RandomScalar(rng)...)
+	}
+	return blob
+}
+
+// NonCanonicalBlob returns a random blob in which one of the field elements is not canonical.
+func NonCanonicalBlob(rng *rand.Rand, params goethkzg.Params) []byte {
+	blob := RandomBlob(rng, params)
+	index := rng.Intn(int(params.FieldElementsPerBlob))
+	copy(blob[index*goethkzg.SerializedScalarSize:], NonCanonicalScalar(rng))
+	return blob
+}
+
+// InvalidG1Point returns random bytes with the compression flag set that do not decode to a point in G1, which can
+// be used as an invalid commitment or proof.
+func InvalidG1Point(rng *rand.Rand) []byte {
+	for {
+		var point goethkzg.KZGCommitment
+		rng.Read(point[:])
+		point[0] = point[0]&0x1f | 0x80
+		if _, err := goethkzg.DeserializeKZGCommitment(point); err != nil {
+			return point[:]
+		}
+	}
+}
+
+// GenerateRandom writes `count` rounds of test cases from random blobs, for each of the spec functions. Every round
+// writes cases with valid inputs, with proofs that do not verify and with invalid inputs. The cases only depend on
+// `rng`, so the same seed always produces the same vectors.
+func (g *Generator) GenerateRandom(rng *rand.Rand, count int) error {
+	for i := 0; i < count; i++ {
+		if err := g.generateRandomRound(rng); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Generator) generateRandomRound(rng *rand.Rand) error {
+	params := g.ctx.Params()
+
+	// 1. Compute the values that the cases are derived from
+	//
+	blob := RandomBlob(rng, params)
+	otherBlob := RandomBlob(rng, params)
+	invalidBlob := NonCanonicalBlob(rng, params)
+	invalidPoint := InvalidG1Point(rng)
+	z := RandomScalar(rng)
+	otherZ := RandomScalar(rng)
+	invalidZ := NonCanonicalScalar(rng)
+
+	commitment, err := g.ctx.BlobToKZGCommitmentBytes(blob, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	blobProof, err := g.ctx.ComputeBlobKZGProofBytes(blob, commitment, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	otherCommitment, err := g.ctx.BlobToKZGCommitmentBytes(otherBlob, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	otherBlobProof, err := g.ctx.ComputeBlobKZGProofBytes(otherBlob, otherCommitment, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	pointProof, y, err := g.ctx.ComputeKZGProofBytes(blob, goethkzg.Scalar(z), numGoRoutines)
+	if err != nil {
+		return err
+	}
+	_, otherY, err := g.ctx.ComputeKZGProofBytes(blob, goethkzg.Scalar(otherZ), numGoRoutines)
+	if err != nil {
+		return err
+	}
+	cells, cellProofs, err := g.ctx.ComputeCellsAndKZGProofsBytes(blob, numGoRoutines)
+	if err != nil {
+		return err
+	}
+
+	// 2. blob_to_kzg_commitment, compute_blob_kzg_proof and compute_kzg_proof
+	//
+	cases := []func() error{
+		func() error { return g.BlobToKZGCommitment("valid_"+CaseID(blob), blob) },
+		func() error { return g.BlobToKZGCommitment("invalid_blob_"+CaseID(invalidBlob), invalidBlob) },
+		func() error { return g.ComputeBlobKZGProof("valid_"+CaseID(blob), blob, commitment[:]) },
+		func() error {
+			return g.ComputeBlobKZGProof("invalid_blob_"+CaseID(invalidBlob), invalidBlob, commitment[:])
+		},
+		func() error {
+			return g.ComputeBlobKZGProof("invalid_commitment_"+CaseID(blob, invalidPoint), blob, invalidPoint)
+		},
+		func() error { return g.ComputeKZGProof("valid_"+CaseID(blob, z), blob, z) },
+		func() error { return g.ComputeKZGProof("invalid_blob_"+CaseID(invalidBlob, z), invalidBlob, z) },
+		func() error { return g.ComputeKZGProof("invalid_z_"+CaseID(blob, invalidZ), blob, invalidZ) },
+	}
+
+	// 3. verify_kzg_proof and verify_blob_kzg_proof
+	//
+	cases = append(cases,
+		func() error {
+			return g.VerifyKZGProof("correct_proof_"+CaseID(commitment[:], z, y[:]), commitment[:], z, y[:], pointProof[:])
+		},
+		func() error {
+			return g.VerifyKZGProof("incorrect_proof_"+CaseID(commitment[:], z, otherY[:]), commitment[:], z, otherY[:], pointProof[:])
+		},
+		func() error {
+			return g.VerifyKZGProof("invalid_proof_"+CaseID(commitment[:], z, invalidPoint), commitment[:], z, y[:], invalidPoint)
+		},
+		func() error {
+			return g.VerifyBlobKZGProof("correct_proof_"+CaseID(blob), blob, commitment[:], blobProof[:])
+		},
+		func() error {
+			return g.VerifyBlobKZGProof("incorrect_proof_"+CaseID(blob, otherBlobProof[:]), blob, commitment[:], otherBlobProof[:])
+		},
+		func() error {
+			return g.VerifyBlobKZGProof("invalid_blob_"+CaseID(invalidBlob), invalidBlob, commitment[:], blobProof[:])
+		},
+	)
+
+	// 4. verify_blob_kzg_proof_batch
+	//
+	blobs := [][]byte{blob, otherBlob}
+	commitments := [][]byte{commitment[:], otherCommitment[:]}
+	blobProofs := [][]byte{blobProof[:], otherBlobProof[:]}
+	cases = append(cases,
+		func() error {
+			return g.VerifyBlobKZGProofBatch("valid_"+CaseID(blobs...), blobs, commitments, blobProofs)
+		},
+		func() error {
+			swapped := [][]byte{otherBlobProof[:], blobProof[:]}
+			return g.VerifyBlobKZGProofBatch("incorrect_proof_"+CaseID(blob, otherBlob, otherBlobProof[:], blobProof[:]), blobs, commitments, swapped)
+		},
+		func() error {
+			invalidBlobs := [][]byte{blob, invalidBlob}
+			return g.VerifyBlobKZGProofBatch("invalid_blob_"+CaseID(invalidBlobs...), invalidBlobs, commitments, blobProofs)
+		},
+		func() error {
+			return g.VerifyBlobKZGProofBatch("proof_length_different_"+CaseID(blobs...), blobs, commitments, blobProofs[:1])
+		},
+	)
+
+	// 5. compute_cells_and_kzg_proofs and verify_cell_kzg_proof_batch, on a random subset of the cells
+	//
+	serCellProofs := make([][]byte, len(cellProofs))
+	for i := range cellProofs {
+		serCellProofs[i] = cellProofs[i][:]
+	}
+	numCells := len(cells)
+	subset := rng.Perm(numCells)[:1+rng.Intn(numCells)]
+	subsetIndices := make([]uint64, len(subset))
+	subsetCells := make([][]byte, len(subset))
+	subsetProofs := make([][]byte, len(subset))
+	subsetCommitments := make([][]byte, len(subset))
+	for k, i := range subset {
+		subsetIndices[k] = uint64(i)
+		subsetCells[k] = cells[i]
+		subsetProofs[k] = serCellProofs[i]
+		subsetCommitments[k] = commitment[:]
+	}
+	subsetID := cellsCaseID(subsetIndices, subsetCells)
+	cases = append(cases,
+		func() error { return g.ComputeCellsAndKZGProofs("valid_"+CaseID(blob), blob) },
+		func() error { return g.ComputeCellsAndKZGProofs("invalid_blob_"+CaseID(invalidBlob), invalidBlob) },
+		func() error {
+			return g.VerifyCellKZGProofBatch("valid_"+subsetID, subsetCommitments, subsetIndices, subsetCells, subsetProofs)
+		},
+		func() error {
+			// The proof of another cell of the same blob
+			otherProofs := append([][]byte{serCellProofs[(subset[0]+1)%numCells]}, subsetProofs[1:]...)
+			return g.VerifyCellKZGProofBatch("incorrect_proof_"+subsetID, subsetCommitments, subsetIndices, subsetCells, otherProofs)
+		},
+		func() error {
+			invalidIndices := append([]uint64{uint64(numCells) + uint64(rng.Intn(numCells))}, subsetIndices[1:]...)
+			return g.VerifyCellKZGProofBatch("invalid_cell_index_"+subsetID, subsetCommitments, invalidIndices, subsetCells, subsetProofs)
+		},
+	)
+
+	// 6. recover_cells_and_kzg_proofs, with a random pattern of missing cells
+	//
+	// Recovery needs at least 1/ExpansionFactor of the cells, so the valid case keeps between that and all of them.
+	minCells := numCells / int(params.ExpansionFactor)
+	kept := rng.Perm(numCells)[:minCells+rng.Intn(numCells-minCells+1)]
+	keptIndices, keptCells := sortedCells(kept, cells)
+	tooFewIndices, tooFewCells := sortedCells(kept[:minCells-1], cells)
+	cases = append(cases,
+		func() error {
+			return g.RecoverCellsAndKZGProofs("valid_"+cellsCaseID(keptIndices, keptCells), keptIndices, keptCells)
+		},
+		func() error {
+			return g.RecoverCellsAndKZGProofs("invalid_not_enough_cells_"+cellsCaseID(tooFewIndices, tooFewCells), tooFewIndices, tooFewCells)
+		},
+	)
+
+	for _, writeCase := range cases {
+		if err := writeCase(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedCells returns the given cell indices in ascending order, and the cells at those indices.
+func sortedCells(indices []int, cells [][]byte) ([]uint64, [][]byte) {
+	isKept := make([]bool, len(cells))
+	for _, i := range indices {
+		isKept[i] = true
+	}
+
+	var sortedIndices []uint64
+	var sortedCells [][]byte
+	for i, kept := range isKept {
+		if kept {
+			sortedIndices = append(sortedIndices, uint64(i))
+			sortedCells = append(sortedCells, cells[i])
+		}
+	}
+	return sortedIndices, sortedCells
+}
+
+// cellsCaseID returns the [CaseID] of a set of cells and their indices.
+func cellsCaseID(indices []uint64, cells [][]byte) string {
+	var buf bytes.Buffer
+	for _, index := range indices {
+		buf.Write(binary.BigEndian.AppendUint64(nil, index))
+	}
+	for _, cell := range cells {
+		buf.Write(cell)
+	}
+	return CaseID(buf.Bytes())
+}
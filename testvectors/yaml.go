@@ -0,0 +1,77 @@
+package testvectors
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The spec vectors are written by hand instead of with a YAML library, so that the hex-strings are always quoted.
+// Unquoted, a YAML 1.1 parser reads a hex-string such as '0x00' as an integer.
+
+// field is a key of the input section of a test case, with its value.
+type field struct {
+	key   string
+	value any
+}
+
+// writeTestCase writes a test case with the given input and output to `w`.
+//
+// The values can be a []byte, which is written as a hex-string, a [][]byte or []uint64, which are written as flow
+// lists, a [2][][]byte, which is written as a block list of two flow lists, a bool or nil.
+func writeTestCase(w io.Writer, input []field, output any) error {
+	var sb strings.Builder
+	sb.WriteString("input:")
+	if len(input) == 0 {
+		sb.WriteString(" {}")
+	}
+	sb.WriteString("\n")
+	for _, f := range input {
+		fmt.Fprintf(&sb, "  %s: %s\n", f.key, formatValue(f.value))
+	}
+
+	if lists, ok := output.([2][][]byte); ok {
+		sb.WriteString("output:\n")
+		for _, list := range lists {
+			fmt.Fprintf(&sb, "- %s\n", formatValue(list))
+		}
+	} else {
+		fmt.Fprintf(&sb, "output: %s\n", formatValue(output))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// formatValue formats a value on a single line. It panics on types that are not used in the test vectors.
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return fmt.Sprint(v)
+	case []byte:
+		return quotedHex(v)
+	case [][]byte:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = quotedHex(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case [2][]byte:
+		return formatValue(v[:])
+	case []uint64:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		panic(fmt.Sprintf("testvectors: cannot format a value of type %T", value))
+	}
+}
+
+func quotedHex(byts []byte) string {
+	return "'0x" + hex.EncodeToString(byts) + "'"
+}
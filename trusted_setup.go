@@ -2,17 +2,27 @@ package goethkzg
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
 	"encoding/hex"
-	"sync"
+	"fmt"
+	"strings"
 
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-eth-kzg/internal/domain"
+	"github.com/crate-crypto/go-eth-kzg/internal/multiexp"
+	"github.com/crate-crypto/go-eth-kzg/internal/utils"
 )
 
-// This library will not :
+// When creating a Context, this library will not :
 // - Check that the points are in the correct subgroup.
 // - Check that setupG1Lagrange is the lagrange version of setupG1.
 //
+// These checks are expensive, so they are left to [CheckTrustedSetupIsWellFormed]
+// and [VerifyTrustedSetupConsistency], which should be used on setups that do
+// not come from a trusted source.
+//
 // Note: There is an embedded (via a //go:embed - compiler instruction) setup
 // testKzgSetupStr, to which we do check those properties in a test function.
 
@@ -20,10 +30,13 @@ import (
 //
 // The intended use-case is that library users store the trusted setup in a JSON file and we provide such a file
 // as part of the package.
+//
+// The number of G1 points is not fixed, so that setups for [Params] other than [MainnetParams] can be loaded. The
+// number of points is checked when the setup is used to create a [Context].
 type JSONTrustedSetup struct {
-	SetupG2         []G2CompressedHexStr               `json:"g2_monomial"`
-	SetupG1Lagrange [ScalarsPerBlob]G1CompressedHexStr `json:"g1_lagrange"`
-	SetupG1Monomial [ScalarsPerBlob]G1CompressedHexStr `json:"g1_monomial"`
+	SetupG2         []G2CompressedHexStr `json:"g2_monomial"`
+	SetupG1Lagrange []G1CompressedHexStr `json:"g1_lagrange"`
+	SetupG1Monomial []G1CompressedHexStr `json:"g1_monomial"`
 }
 
 // G1CompressedHexStr is a hex-string (with the 0x prefix) of a compressed G1 point.
@@ -45,7 +58,7 @@ var testKzgSetupStr string
 func CheckTrustedSetupIsWellFormed(trustedSetup *JSONTrustedSetup) error {
 	for i := 0; i < len(trustedSetup.SetupG1Lagrange); i++ {
 		var point bls12381.G1Affine
-		byts, err := hex.DecodeString(trim0xPrefix(trustedSetup.SetupG1Lagrange[i]))
+		byts, err := decodeHexStr(trustedSetup.SetupG1Lagrange[i])
 		if err != nil {
 			return err
 		}
@@ -57,7 +70,7 @@ func CheckTrustedSetupIsWellFormed(trustedSetup *JSONTrustedSetup) error {
 
 	for i := 0; i < len(trustedSetup.SetupG1Monomial); i++ {
 		var point bls12381.G1Affine
-		byts, err := hex.DecodeString(trim0xPrefix(trustedSetup.SetupG1Monomial[i]))
+		byts, err := decodeHexStr(trustedSetup.SetupG1Monomial[i])
 		if err != nil {
 			return err
 		}
@@ -69,7 +82,7 @@ func CheckTrustedSetupIsWellFormed(trustedSetup *JSONTrustedSetup) error {
 
 	for i := 0; i < len(trustedSetup.SetupG2); i++ {
 		var point bls12381.G2Affine
-		byts, err := hex.DecodeString(trim0xPrefix(trustedSetup.SetupG2[i]))
+		byts, err := decodeHexStr(trustedSetup.SetupG2[i])
 		if err != nil {
 			return err
 		}
@@ -82,21 +95,163 @@ func CheckTrustedSetupIsWellFormed(trustedSetup *JSONTrustedSetup) error {
 	return nil
 }
 
-// parseTrustedSetup parses the trusted setup in `JSONTrustedSetup` format
-// which contains hex encoded strings to corresponding group elements.
-// Elements are assumed to be well-formed.
+// VerifyTrustedSetupConsistency checks that the trusted setup is the output of a ceremony for a single secret.
 //
-// This method wil panic if the points have not been serialized correctly.
-func parseTrustedSetup(trustedSetup *JSONTrustedSetup) (bls12381.G1Affine, []bls12381.G1Affine, []bls12381.G1Affine, []bls12381.G2Affine) {
-	// The G1 generator is the first element of the monomial G1 points.
-	// We do not have that and so we use the fact that the setup started at
-	// the canonical generator point.
-	_, _, genG1, _ := bls12381.Generators()
-
-	setupLagrangeG1Points := parseG1PointsNoSubgroupCheck(trustedSetup.SetupG1Lagrange[:])
-	setupMonomialG1Points := parseG1PointsNoSubgroupCheck(trustedSetup.SetupG1Monomial[:])
-	g2Points := parseG2PointsNoSubgroupCheck(trustedSetup.SetupG2)
-	return genG1, setupMonomialG1Points, setupLagrangeG1Points, g2Points
+// To be specific, on top of the checks done by [CheckTrustedSetupIsWellFormed], this checks that:
+//   - The first monomial points are the generators of G1 and G2.
+//   - The monomial G1 points are the successive powers of the secret in the G2 points, and the G2 points are
+//     the successive powers of the secret in the G1 points. Both are done with a pairing check on a random linear
+//     combination of the points.
+//   - The lagrange points are the IFFT of the monomial G1 points. The lagrange points are stored in their natural
+//     order and bit-reversed when a [Context] is created, which matches the order of the roots of unity in the spec.
+//
+// This is much more expensive than creating a Context, and is meant to be used once on setups that do not come
+// from a trusted source, for example a file received from a third party.
+func VerifyTrustedSetupConsistency(trustedSetup *JSONTrustedSetup) error {
+	// 1. Check that there are enough points for the checks below
+	//
+	numG1 := len(trustedSetup.SetupG1Monomial)
+	numG2 := len(trustedSetup.SetupG2)
+	if numG1 < 2 || !utils.IsPowerOfTwo(uint64(numG1)) || len(trustedSetup.SetupG1Lagrange) != numG1 {
+		return fmt.Errorf("%w: the number of G1 points must be the same power of two in both forms, found %d monomial and %d lagrange points", ErrTrustedSetupInconsistent, numG1, len(trustedSetup.SetupG1Lagrange))
+	}
+	if numG2 < 2 {
+		return fmt.Errorf("%w: there must be at least 2 G2 points, found %d", ErrTrustedSetupInconsistent, numG2)
+	}
+
+	// 2. Deserialize the points, checking that they are in the correct subgroup
+	//
+	monomialG1, err := parseG1Points(trustedSetup.SetupG1Monomial)
+	if err != nil {
+		return fmt.Errorf("%w: g1_monomial %w", ErrMalformedTrustedSetup, err)
+	}
+	lagrangeG1, err := parseG1Points(trustedSetup.SetupG1Lagrange)
+	if err != nil {
+		return fmt.Errorf("%w: g1_lagrange %w", ErrMalformedTrustedSetup, err)
+	}
+	monomialG2, err := parseG2Points(trustedSetup.SetupG2)
+	if err != nil {
+		return fmt.Errorf("%w: g2_monomial %w", ErrMalformedTrustedSetup, err)
+	}
+
+	// 3. Check the generators, and that the secret is not zero or one, in which case
+	// every point would be the same.
+	//
+	_, _, genG1, genG2 := bls12381.Generators()
+	if !monomialG1[0].Equal(&genG1) {
+		return fmt.Errorf("%w: the first monomial G1 point is not the generator", ErrTrustedSetupInconsistent)
+	}
+	if !monomialG2[0].Equal(&genG2) {
+		return fmt.Errorf("%w: the first G2 point is not the generator", ErrTrustedSetupInconsistent)
+	}
+	if monomialG1[1].IsInfinity() || monomialG1[1].Equal(&genG1) {
+		return fmt.Errorf("%w: the secret is degenerate", ErrTrustedSetupInconsistent)
+	}
+
+	// 4. Check that the G1 and G2 points are powers of the same secret.
+	//
+	// If the points are the powers of a secret s, then shifting them by one multiplies
+	// each of them by s. So for a random linear combination, we should have that:
+	//
+	// e(sum r^i * G1[i+1], G2[0]) = e(sum r^i * G1[i], G2[1])
+	// e(G1[0], sum r^i * G2[i+1]) = e(G1[1], sum r^i * G2[i])
+	//
+	var r fr.Element
+	if _, err := r.SetRandom(); err != nil {
+		return err
+	}
+	rPowers := utils.ComputePowers(r, uint(max(numG1, numG2)-1))
+
+	shiftedG1, err := multiexp.MultiExpG1(rPowers[:numG1-1], monomialG1[1:], 0)
+	if err != nil {
+		return err
+	}
+	unshiftedG1, err := multiexp.MultiExpG1(rPowers[:numG1-1], monomialG1[:numG1-1], 0)
+	if err != nil {
+		return err
+	}
+	if !pairingsAreEqual(shiftedG1, &monomialG2[0], unshiftedG1, &monomialG2[1]) {
+		return fmt.Errorf("%w: the monomial G1 points are not powers of the secret in the G2 points", ErrTrustedSetupInconsistent)
+	}
+
+	shiftedG2, err := multiexp.MultiExpG2(rPowers[:numG2-1], monomialG2[1:], 0)
+	if err != nil {
+		return err
+	}
+	unshiftedG2, err := multiexp.MultiExpG2(rPowers[:numG2-1], monomialG2[:numG2-1], 0)
+	if err != nil {
+		return err
+	}
+	if !pairingsAreEqual(&monomialG1[0], shiftedG2, &monomialG1[1], unshiftedG2) {
+		return fmt.Errorf("%w: the G2 points are not powers of the secret in the G1 points", ErrTrustedSetupInconsistent)
+	}
+
+	// 5. Check that the lagrange points are the IFFT of the monomial points
+	//
+	expectedLagrangeG1 := make([]bls12381.G1Affine, numG1)
+	copy(expectedLagrangeG1, monomialG1)
+	if err := domain.NewDomain(uint64(numG1)).IfftG1Ctx(context.Background(), expectedLagrangeG1, 0); err != nil {
+		return err
+	}
+	for i := range expectedLagrangeG1 {
+		if !expectedLagrangeG1[i].Equal(&lagrangeG1[i]) {
+			return fmt.Errorf("%w: lagrange point %d is not the IFFT of the monomial points", ErrTrustedSetupInconsistent, i)
+		}
+	}
+
+	return nil
+}
+
+// pairingsAreEqual returns true if e(a1, b1) = e(a2, b2).
+func pairingsAreEqual(a1 *bls12381.G1Affine, b1 *bls12381.G2Affine, a2 *bls12381.G1Affine, b2 *bls12381.G2Affine) bool {
+	var negA2 bls12381.G1Affine
+	negA2.Neg(a2)
+	check, err := bls12381.PairingCheck([]bls12381.G1Affine{*a1, negA2}, []bls12381.G2Affine{*b1, *b2})
+	return err == nil && check
+}
+
+// parseG1Points parses a slice of hex-strings (with the 0x prefix) into a slice of G1 points,
+// checking that each of them is in the correct subgroup.
+func parseG1Points(hexStrings []string) ([]bls12381.G1Affine, error) {
+	points := make([]bls12381.G1Affine, len(hexStrings))
+	errs := make([]error, len(hexStrings))
+	utils.ParallelFor(len(hexStrings), 0, func(start, end int) {
+		for i := start; i < end; i++ {
+			byts, err := decodeHexStr(hexStrings[i])
+			if err == nil {
+				_, err = points[i].SetBytes(byts)
+			}
+			errs[i] = err
+		}
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+	}
+	return points, nil
+}
+
+// parseG2Points parses a slice of hex-strings (with the 0x prefix) into a slice of G2 points,
+// checking that each of them is in the correct subgroup.
+func parseG2Points(hexStrings []string) ([]bls12381.G2Affine, error) {
+	points := make([]bls12381.G2Affine, len(hexStrings))
+	errs := make([]error, len(hexStrings))
+	utils.ParallelFor(len(hexStrings), 0, func(start, end int) {
+		for i := start; i < end; i++ {
+			byts, err := decodeHexStr(hexStrings[i])
+			if err == nil {
+				_, err = points[i].SetBytes(byts)
+			}
+			errs[i] = err
+		}
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+	}
+	return points, nil
 }
 
 // parseG1PointNoSubgroupCheck parses a hex-string (with the 0x prefix) into a G1 point.
@@ -104,7 +259,7 @@ func parseTrustedSetup(trustedSetup *JSONTrustedSetup) (bls12381.G1Affine, []bls
 // This function performs no (expensive) subgroup checks, and should only be used
 // for trusted inputs.
 func parseG1PointNoSubgroupCheck(hexString string) (bls12381.G1Affine, error) {
-	byts, err := hex.DecodeString(trim0xPrefix(hexString))
+	byts, err := decodeHexStr(hexString)
 	if err != nil {
 		return bls12381.G1Affine{}, err
 	}
@@ -121,7 +276,7 @@ func parseG1PointNoSubgroupCheck(hexString string) (bls12381.G1Affine, error) {
 // This function performs no (expensive) subgroup checks, and should only be used
 // for trusted inputs.
 func parseG2PointNoSubgroupCheck(hexString string) (bls12381.G2Affine, error) {
-	byts, err := hex.DecodeString(trim0xPrefix(hexString))
+	byts, err := decodeHexStr(hexString)
 	if err != nil {
 		return bls12381.G2Affine{}, err
 	}
@@ -137,65 +292,72 @@ func parseG2PointNoSubgroupCheck(hexString string) (bls12381.G2Affine, error) {
 // slice of G1 points.
 //
 // This is essentially a parallelized version of calling [parseG1PointNoSubgroupCheck]
-// on each element of the slice individually.
+// on each element of the slice individually. If any of the points cannot be parsed,
+// the error for the first of them is returned.
 //
 // This function performs no (expensive) subgroup checks, and should only be used
 // for trusted inputs.
-func parseG1PointsNoSubgroupCheck(hexStrings []string) []bls12381.G1Affine {
+func parseG1PointsNoSubgroupCheck(hexStrings []string) ([]bls12381.G1Affine, error) {
 	numG1 := len(hexStrings)
 	g1Points := make([]bls12381.G1Affine, numG1)
+	errs := make([]error, numG1)
 
-	var wg sync.WaitGroup
-	wg.Add(numG1)
-	for i := 0; i < numG1; i++ {
-		go func(j int) {
-			g1Point, err := parseG1PointNoSubgroupCheck(hexStrings[j])
-			if err != nil {
-				panic(err)
-			}
-			g1Points[j] = g1Point
-			wg.Done()
-		}(i)
+	utils.ParallelFor(numG1, 0, func(start, end int) {
+		for i := start; i < end; i++ {
+			g1Points[i], errs[i] = parseG1PointNoSubgroupCheck(hexStrings[i])
+		}
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
 	}
-	wg.Wait()
 
-	return g1Points
+	return g1Points, nil
 }
 
 // parseG2PointsNoSubgroupCheck parses a slice hex-string (with the 0x prefix) into a
 // slice of G2 points.
 //
 // This is essentially a parallelized version of calling [parseG2PointNoSubgroupCheck]
-// on each element of the slice individually.
+// on each element of the slice individually. If any of the points cannot be parsed,
+// the error for the first of them is returned.
 //
 // This function performs no (expensive) subgroup checks, and should only be used
 // for trusted inputs.
-func parseG2PointsNoSubgroupCheck(hexStrings []string) []bls12381.G2Affine {
+func parseG2PointsNoSubgroupCheck(hexStrings []string) ([]bls12381.G2Affine, error) {
 	numG2 := len(hexStrings)
 	g2Points := make([]bls12381.G2Affine, numG2)
+	errs := make([]error, numG2)
 
-	var wg sync.WaitGroup
-	wg.Add(numG2)
-	for i := 0; i < numG2; i++ {
-		go func(_i int) {
-			g2Point, err := parseG2PointNoSubgroupCheck(hexStrings[_i])
-			if err != nil {
-				panic(err)
-			}
-			g2Points[_i] = g2Point
-			wg.Done()
-		}(i)
+	utils.ParallelFor(numG2, 0, func(start, end int) {
+		for i := start; i < end; i++ {
+			g2Points[i], errs[i] = parseG2PointNoSubgroupCheck(hexStrings[i])
+		}
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
 	}
-	wg.Wait()
 
-	return g2Points
+	return g2Points, nil
+}
+
+// decodeHexStr decodes a hex-string with the 0x prefix.
+func decodeHexStr(hexString string) ([]byte, error) {
+	trimmed, err := trim0xPrefix(hexString)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(trimmed)
 }
 
 // trim0xPrefix removes the "0x" from a hex-string.
-func trim0xPrefix(hexString string) string {
+func trim0xPrefix(hexString string) (string, error) {
 	// Check that we are trimming off 0x
-	if hexString[0:2] != "0x" {
-		panic("hex string is not prefixed with 0x")
+	if !strings.HasPrefix(hexString, "0x") {
+		return "", ErrMissingHexPrefix
 	}
-	return hexString[2:]
+	return hexString[2:], nil
 }
@@ -0,0 +1,290 @@
+package goethkzg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The trusted setup can be loaded from three formats. They all hold the same points, in the same order, and
+// loading any of them produces a [JSONTrustedSetup]:
+//
+//   - JSON: the format of trusted_setup.json, see [LoadTrustedSetupJSON].
+//   - Text: the trusted_setup.txt format used by c-kzg, see [LoadTrustedSetupText].
+//   - Binary: a compact format holding the raw compressed points, see [LoadTrustedSetupBinary].
+//
+// The loaders only check that the points are encoded correctly and that there are as many of them as there should
+// be. The points themselves are decompressed when the setup is used to create a [Context], and
+// [CheckTrustedSetupIsWellFormed] can be used to also check that they are in the correct subgroup.
+
+// A trusted setup in the binary format has the following layout:
+//
+//   - magic:       8 bytes, always equal to `trustedSetupMagic`
+//   - version:     8 bytes, big-endian
+//   - numG1:       8 bytes, big-endian number of G1 points in each of the lagrange and monomial forms
+//   - numG2:       8 bytes, big-endian number of G2 points
+//   - g1_lagrange: numG1 compressed G1 points of 48 bytes each
+//   - g2_monomial: numG2 compressed G2 points of 96 bytes each
+//   - g1_monomial: numG1 compressed G1 points of 48 bytes each
+//
+// This is the same order as the text format.
+
+// trustedSetupMagic identifies a trusted setup in the binary format.
+var trustedSetupMagic = [8]byte{'K', 'Z', 'G', 'S', 'E', 'T', 'U', 'P'}
+
+// trustedSetupFormatVersion is incremented whenever the binary format changes.
+const trustedSetupFormatVersion = 1
+
+// trustedSetupHeaderSize is the size of the magic, version and count fields of the binary format.
+const trustedSetupHeaderSize = len(trustedSetupMagic) + 3*8
+
+// maxTrustedSetupPoints bounds the number of points that the loaders will accept, so that a malformed
+// count cannot make them allocate an unbounded amount of memory.
+const maxTrustedSetupPoints = 1 << 20
+
+// LoadTrustedSetupJSON reads a trusted setup in the JSON format of trusted_setup.json.
+func LoadTrustedSetupJSON(r io.Reader) (*JSONTrustedSetup, error) {
+	decoder := json.NewDecoder(r)
+
+	var trustedSetup JSONTrustedSetup
+	if err := decoder.Decode(&trustedSetup); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedTrustedSetup, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: trailing data after the JSON object", ErrMalformedTrustedSetup)
+	}
+	if err := checkTrustedSetupEncoding(&trustedSetup); err != nil {
+		return nil, err
+	}
+
+	return &trustedSetup, nil
+}
+
+// WriteTrustedSetupJSON writes the trusted setup in the JSON format of trusted_setup.json.
+func WriteTrustedSetupJSON(w io.Writer, trustedSetup *JSONTrustedSetup) error {
+	if err := checkTrustedSetupEncoding(trustedSetup); err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(trustedSetup)
+}
+
+// LoadTrustedSetupText reads a trusted setup in the trusted_setup.txt format used by c-kzg.
+//
+// The first two lines hold the number of G1 and G2 points. They are followed by one line for each point, holding
+// its compressed encoding as a hex-string without the 0x prefix: first the G1 points in lagrange form, then the G2
+// points in monomial form and finally the G1 points in monomial form. Empty lines are ignored.
+func LoadTrustedSetupText(r io.Reader) (*JSONTrustedSetup, error) {
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	// nextLine returns the next line that is not empty
+	nextLine := func() (string, error) {
+		for scanner.Scan() {
+			lineNumber++
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" {
+				return line, nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrMalformedTrustedSetup, err)
+		}
+		return "", fmt.Errorf("%w: unexpected end of file after line %d", ErrMalformedTrustedSetup, lineNumber)
+	}
+	readCount := func(name string) (int, error) {
+		line, err := nextLine()
+		if err != nil {
+			return 0, err
+		}
+		count, err := strconv.Atoi(line)
+		if err != nil || count < 0 || count > maxTrustedSetupPoints {
+			return 0, fmt.Errorf("%w: line %d: invalid number of %s points %q", ErrMalformedTrustedSetup, lineNumber, name, line)
+		}
+		return count, nil
+	}
+	readPoints := func(name string, count, size int) ([]string, error) {
+		points := make([]string, count)
+		for i := range points {
+			line, err := nextLine()
+			if err != nil {
+				return nil, err
+			}
+			byts, err := hex.DecodeString(line)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %s point %d: %w", ErrMalformedTrustedSetup, lineNumber, name, i, err)
+			}
+			if len(byts) != size {
+				return nil, fmt.Errorf("%w: line %d: %s point %d has %d bytes, expected %d", ErrMalformedTrustedSetup, lineNumber, name, i, len(byts), size)
+			}
+			points[i] = "0x" + hex.EncodeToString(byts)
+		}
+		return points, nil
+	}
+
+	numG1, err := readCount("G1")
+	if err != nil {
+		return nil, err
+	}
+	numG2, err := readCount("G2")
+	if err != nil {
+		return nil, err
+	}
+
+	var trustedSetup JSONTrustedSetup
+	if trustedSetup.SetupG1Lagrange, err = readPoints("g1_lagrange", numG1, CompressedG1Size); err != nil {
+		return nil, err
+	}
+	if trustedSetup.SetupG2, err = readPoints("g2_monomial", numG2, CompressedG2Size); err != nil {
+		return nil, err
+	}
+	if trustedSetup.SetupG1Monomial, err = readPoints("g1_monomial", numG1, CompressedG1Size); err != nil {
+		return nil, err
+	}
+
+	if _, err := nextLine(); err == nil {
+		return nil, fmt.Errorf("%w: line %d: trailing data after the last point", ErrMalformedTrustedSetup, lineNumber)
+	}
+	if err := checkTrustedSetupEncoding(&trustedSetup); err != nil {
+		return nil, err
+	}
+
+	return &trustedSetup, nil
+}
+
+// WriteTrustedSetupText writes the trusted setup in the trusted_setup.txt format used by c-kzg.
+//
+// See [LoadTrustedSetupText] for a description of the format.
+func WriteTrustedSetupText(w io.Writer, trustedSetup *JSONTrustedSetup) error {
+	if err := checkTrustedSetupEncoding(trustedSetup); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%d\n%d\n", len(trustedSetup.SetupG1Lagrange), len(trustedSetup.SetupG2))
+	for _, points := range [][]string{trustedSetup.SetupG1Lagrange, trustedSetup.SetupG2, trustedSetup.SetupG1Monomial} {
+		for _, point := range points {
+			// The encoding has been checked, so this cannot fail
+			trimmed, _ := trim0xPrefix(point)
+			fmt.Fprintln(bw, strings.ToLower(trimmed))
+		}
+	}
+	return bw.Flush()
+}
+
+// LoadTrustedSetupBinary reads a trusted setup in the compact binary format written by [WriteTrustedSetupBinary].
+//
+// Only the bytes belonging to the trusted setup are consumed from `r`.
+func LoadTrustedSetupBinary(r io.Reader) (*JSONTrustedSetup, error) {
+	header := make([]byte, trustedSetupHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: truncated header: %w", ErrMalformedTrustedSetup, err)
+	}
+	if !bytes.Equal(header[:len(trustedSetupMagic)], trustedSetupMagic[:]) {
+		return nil, fmt.Errorf("%w: data is not a trusted setup in the binary format", ErrMalformedTrustedSetup)
+	}
+	offset := len(trustedSetupMagic)
+	version := binary.BigEndian.Uint64(header[offset:])
+	if version != trustedSetupFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported binary format version %d", ErrMalformedTrustedSetup, version)
+	}
+	numG1 := binary.BigEndian.Uint64(header[offset+8:])
+	numG2 := binary.BigEndian.Uint64(header[offset+16:])
+	if numG1 > maxTrustedSetupPoints || numG2 > maxTrustedSetupPoints {
+		return nil, fmt.Errorf("%w: too many points (%d G1, %d G2)", ErrMalformedTrustedSetup, numG1, numG2)
+	}
+
+	readPoints := func(name string, count uint64, size int) ([]string, error) {
+		byts := make([]byte, int(count)*size)
+		if _, err := io.ReadFull(r, byts); err != nil {
+			return nil, fmt.Errorf("%w: truncated %s points: %w", ErrMalformedTrustedSetup, name, err)
+		}
+		points := make([]string, count)
+		for i := range points {
+			points[i] = "0x" + hex.EncodeToString(byts[i*size:(i+1)*size])
+		}
+		return points, nil
+	}
+
+	var trustedSetup JSONTrustedSetup
+	var err error
+	if trustedSetup.SetupG1Lagrange, err = readPoints("g1_lagrange", numG1, CompressedG1Size); err != nil {
+		return nil, err
+	}
+	if trustedSetup.SetupG2, err = readPoints("g2_monomial", numG2, CompressedG2Size); err != nil {
+		return nil, err
+	}
+	if trustedSetup.SetupG1Monomial, err = readPoints("g1_monomial", numG1, CompressedG1Size); err != nil {
+		return nil, err
+	}
+	if err := checkTrustedSetupEncoding(&trustedSetup); err != nil {
+		return nil, err
+	}
+
+	return &trustedSetup, nil
+}
+
+// WriteTrustedSetupBinary writes the trusted setup in a compact binary format, which holds the raw compressed
+// points. It is about half the size of the other formats and can be read with [LoadTrustedSetupBinary].
+func WriteTrustedSetupBinary(w io.Writer, trustedSetup *JSONTrustedSetup) error {
+	if err := checkTrustedSetupEncoding(trustedSetup); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.Write(trustedSetupMagic[:])
+	bw.Write(binary.BigEndian.AppendUint64(nil, trustedSetupFormatVersion))
+	bw.Write(binary.BigEndian.AppendUint64(nil, uint64(len(trustedSetup.SetupG1Lagrange))))
+	bw.Write(binary.BigEndian.AppendUint64(nil, uint64(len(trustedSetup.SetupG2))))
+	for _, points := range [][]string{trustedSetup.SetupG1Lagrange, trustedSetup.SetupG2, trustedSetup.SetupG1Monomial} {
+		for _, point := range points {
+			// The encoding has been checked, so this cannot fail
+			byts, _ := decodeHexStr(point)
+			bw.Write(byts)
+		}
+	}
+	return bw.Flush()
+}
+
+// checkTrustedSetupEncoding checks that every point in the trusted setup is a hex-string with the 0x prefix that
+// has the length of a compressed point, and that there are as many G1 points in lagrange form as in monomial form.
+//
+// The points are not decompressed, which is left to [NewContext4096] and [CheckTrustedSetupIsWellFormed].
+func checkTrustedSetupEncoding(trustedSetup *JSONTrustedSetup) error {
+	numG1 := len(trustedSetup.SetupG1Lagrange)
+	if numG1 == 0 {
+		return fmt.Errorf("%w: there are no G1 points", ErrMalformedTrustedSetup)
+	}
+	if len(trustedSetup.SetupG1Monomial) != numG1 {
+		return fmt.Errorf("%w: %d G1 points in lagrange form, but %d in monomial form", ErrMalformedTrustedSetup, numG1, len(trustedSetup.SetupG1Monomial))
+	}
+	if len(trustedSetup.SetupG2) < 2 {
+		return fmt.Errorf("%w: there must be at least 2 G2 points, found %d", ErrMalformedTrustedSetup, len(trustedSetup.SetupG2))
+	}
+
+	checkPoints := func(name string, points []string, size int) error {
+		for i, point := range points {
+			byts, err := decodeHexStr(point)
+			if err != nil {
+				return fmt.Errorf("%w: %s point %d: %w", ErrMalformedTrustedSetup, name, i, err)
+			}
+			if len(byts) != size {
+				return fmt.Errorf("%w: %s point %d has %d bytes, expected %d", ErrMalformedTrustedSetup, name, i, len(byts), size)
+			}
+		}
+		return nil
+	}
+	if err := checkPoints("g1_lagrange", trustedSetup.SetupG1Lagrange, CompressedG1Size); err != nil {
+		return err
+	}
+	if err := checkPoints("g2_monomial", trustedSetup.SetupG2, CompressedG2Size); err != nil {
+		return err
+	}
+	return checkPoints("g1_monomial", trustedSetup.SetupG1Monomial, CompressedG1Size)
+}
@@ -1,9 +1,15 @@
 package goethkzg
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"io"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/crate-crypto/go-eth-kzg/internal/domain"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,3 +21,199 @@ func TestTransformTrustedSetup(t *testing.T) {
 	err = CheckTrustedSetupIsWellFormed(&parsedSetup)
 	require.NoError(t, err)
 }
+
+func TestTrustedSetupFormatsRoundTrip(t *testing.T) {
+	expected, err := LoadTrustedSetupJSON(strings.NewReader(testKzgSetupStr))
+	require.NoError(t, err)
+	require.Len(t, expected.SetupG1Lagrange, ScalarsPerBlob)
+
+	formats := []struct {
+		name  string
+		write func(io.Writer, *JSONTrustedSetup) error
+		load  func(io.Reader) (*JSONTrustedSetup, error)
+	}{
+		{"json", WriteTrustedSetupJSON, LoadTrustedSetupJSON},
+		{"text", WriteTrustedSetupText, LoadTrustedSetupText},
+		{"binary", WriteTrustedSetupBinary, LoadTrustedSetupBinary},
+	}
+	for _, from := range formats {
+		var buf bytes.Buffer
+		require.NoError(t, from.write(&buf, expected))
+		got, err := from.load(&buf)
+		require.NoError(t, err, from.name)
+		require.Equal(t, expected, got, from.name)
+
+		// Converting between any two formats gives back the same setup
+		for _, to := range formats {
+			var converted bytes.Buffer
+			require.NoError(t, to.write(&converted, got))
+			roundTripped, err := to.load(&converted)
+			require.NoError(t, err, to.name)
+			require.Equal(t, expected, roundTripped, "%s to %s", from.name, to.name)
+		}
+	}
+}
+
+func TestLoadTrustedSetupText(t *testing.T) {
+	trustedSetup, err := LoadTrustedSetupText(strings.NewReader(smallSetupText))
+	require.NoError(t, err)
+	require.Equal(t, &JSONTrustedSetup{
+		SetupG1Lagrange: []string{"0x" + g1Generator, "0x" + g1Generator},
+		SetupG2:         []string{"0x" + g2Generator, "0x" + g2Generator},
+		SetupG1Monomial: []string{"0x" + g1Generator, "0x" + g1Generator},
+	}, trustedSetup)
+
+	malformed := map[string]string{
+		"empty":             "",
+		"bad count":         "two\n2\n",
+		"negative count":    "-1\n2\n",
+		"missing points":    "2\n2\n" + g1Generator + "\n",
+		"not hex":           strings.Replace(smallSetupText, g1Generator, "zz"+g1Generator[2:], 1),
+		"wrong point size":  strings.Replace(smallSetupText, g1Generator, g1Generator[2:], 1),
+		"trailing data":     smallSetupText + g1Generator + "\n",
+		"too few g2 points": "2\n1\n" + g1Generator + "\n" + g1Generator + "\n" + g2Generator + "\n" + g1Generator + "\n" + g1Generator + "\n",
+	}
+	for name, text := range malformed {
+		_, err := LoadTrustedSetupText(strings.NewReader(text))
+		require.ErrorIs(t, err, ErrMalformedTrustedSetup, name)
+	}
+}
+
+func TestLoadTrustedSetupBinaryMalformed(t *testing.T) {
+	trustedSetup, err := LoadTrustedSetupText(strings.NewReader(smallSetupText))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, WriteTrustedSetupBinary(&buf, trustedSetup))
+	valid := buf.Bytes()
+
+	badMagic := bytes.Clone(valid)
+	badMagic[0] ^= 1
+	badVersion := bytes.Clone(valid)
+	badVersion[len(trustedSetupMagic)+7] = 2
+	hugeCount := bytes.Clone(valid)
+	hugeCount[len(trustedSetupMagic)+8] = 0xff
+
+	malformed := map[string][]byte{
+		"empty":       nil,
+		"bad magic":   badMagic,
+		"bad version": badVersion,
+		"huge count":  hugeCount,
+		"truncated":   valid[:len(valid)-1],
+	}
+	for name, data := range malformed {
+		_, err := LoadTrustedSetupBinary(bytes.NewReader(data))
+		require.ErrorIs(t, err, ErrMalformedTrustedSetup, name)
+	}
+}
+
+func TestLoadTrustedSetupJSONMalformed(t *testing.T) {
+	malformed := map[string]string{
+		"not json":          "{",
+		"missing prefix":    `{"g1_lagrange": ["` + g1Generator + `"], "g1_monomial": ["0x` + g1Generator + `"], "g2_monomial": ["0x` + g2Generator + `", "0x` + g2Generator + `"]}`,
+		"short prefix":      `{"g1_lagrange": ["0"], "g1_monomial": ["0x` + g1Generator + `"], "g2_monomial": ["0x` + g2Generator + `", "0x` + g2Generator + `"]}`,
+		"mismatched counts": `{"g1_lagrange": ["0x` + g1Generator + `"], "g1_monomial": [], "g2_monomial": ["0x` + g2Generator + `", "0x` + g2Generator + `"]}`,
+		"trailing data":     `{"g1_lagrange": ["0x` + g1Generator + `"], "g1_monomial": ["0x` + g1Generator + `"], "g2_monomial": ["0x` + g2Generator + `", "0x` + g2Generator + `"]} {}`,
+	}
+	for name, text := range malformed {
+		_, err := LoadTrustedSetupJSON(strings.NewReader(text))
+		require.ErrorIs(t, err, ErrMalformedTrustedSetup, name)
+	}
+}
+
+func TestNewContextMalformedTrustedSetup(t *testing.T) {
+	valid, err := LoadTrustedSetupJSON(strings.NewReader(testKzgSetupStr))
+	require.NoError(t, err)
+
+	// These used to panic, but are now reported as errors
+	missingPrefix := *valid
+	missingPrefix.SetupG1Lagrange = slices.Clone(valid.SetupG1Lagrange)
+	missingPrefix.SetupG1Lagrange[7] = missingPrefix.SetupG1Lagrange[7][2:]
+	_, err = NewContext4096(&missingPrefix)
+	require.ErrorIs(t, err, ErrMalformedTrustedSetup)
+	require.ErrorIs(t, err, ErrMissingHexPrefix)
+	require.ErrorContains(t, err, "g1_lagrange point 7")
+
+	notAPoint := *valid
+	notAPoint.SetupG2 = slices.Clone(valid.SetupG2)
+	notAPoint.SetupG2[1] = "0x" + strings.Repeat("ff", CompressedG2Size)
+	_, err = NewContext4096(&notAPoint)
+	require.ErrorIs(t, err, ErrMalformedTrustedSetup)
+	require.ErrorContains(t, err, "g2_monomial point 1")
+}
+
+// The compressed generators of G1 and G2, which are used to build small setups in the text format
+const (
+	g1Generator = "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+	g2Generator = "93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8"
+)
+
+var smallSetupText = "2\n2\n" +
+	g1Generator + "\n" + g1Generator + "\n" +
+	g2Generator + "\n\n" + g2Generator + "\n" +
+	g1Generator + "\n" + g1Generator + "\n"
+
+func TestVerifyTrustedSetupConsistency(t *testing.T) {
+	mainnetSetup, err := LoadTrustedSetupJSON(strings.NewReader(testKzgSetupStr))
+	require.NoError(t, err)
+	require.NoError(t, VerifyTrustedSetupConsistency(mainnetSetup))
+
+	// The first monomial points of a setup are a valid setup for a smaller size, once the lagrange
+	// points have been computed for it.
+	const numG1 = 16
+	monomialG1, err := parseG1Points(mainnetSetup.SetupG1Monomial[:numG1])
+	require.NoError(t, err)
+	domain.NewDomain(numG1).IfftG1(monomialG1)
+	small := &JSONTrustedSetup{
+		SetupG2:         mainnetSetup.SetupG2[:4],
+		SetupG1Lagrange: make([]string, numG1),
+		SetupG1Monomial: mainnetSetup.SetupG1Monomial[:numG1],
+	}
+	for i, point := range monomialG1 {
+		byts := point.Bytes()
+		small.SetupG1Lagrange[i] = "0x" + hex.EncodeToString(byts[:])
+	}
+	require.NoError(t, VerifyTrustedSetupConsistency(small))
+
+	tamper := func(modify func(setup *JSONTrustedSetup)) *JSONTrustedSetup {
+		setup := &JSONTrustedSetup{
+			SetupG2:         slices.Clone(small.SetupG2),
+			SetupG1Lagrange: slices.Clone(small.SetupG1Lagrange),
+			SetupG1Monomial: slices.Clone(small.SetupG1Monomial),
+		}
+		modify(setup)
+		return setup
+	}
+	inconsistent := map[string]*JSONTrustedSetup{
+		"lagrange points swapped": tamper(func(setup *JSONTrustedSetup) {
+			setup.SetupG1Lagrange[1], setup.SetupG1Lagrange[2] = setup.SetupG1Lagrange[2], setup.SetupG1Lagrange[1]
+		}),
+		"lagrange points bit-reversed": tamper(func(setup *JSONTrustedSetup) {
+			domain.BitReverse(setup.SetupG1Lagrange)
+		}),
+		"first monomial point is not the generator": tamper(func(setup *JSONTrustedSetup) {
+			setup.SetupG1Monomial[0] = setup.SetupG1Monomial[1]
+		}),
+		"monomial point is not a power of the secret": tamper(func(setup *JSONTrustedSetup) {
+			setup.SetupG1Monomial[5] = setup.SetupG1Monomial[6]
+		}),
+		"G2 point is not a power of the secret": tamper(func(setup *JSONTrustedSetup) {
+			setup.SetupG2[3] = setup.SetupG2[2]
+		}),
+		"G1 and G2 points have different secrets": tamper(func(setup *JSONTrustedSetup) {
+			setup.SetupG2 = setup.SetupG2[1:]
+		}),
+		"number of G1 points is not a power of two": tamper(func(setup *JSONTrustedSetup) {
+			setup.SetupG1Lagrange = setup.SetupG1Lagrange[:numG1-1]
+			setup.SetupG1Monomial = setup.SetupG1Monomial[:numG1-1]
+		}),
+	}
+	for name, setup := range inconsistent {
+		err := VerifyTrustedSetupConsistency(setup)
+		require.ErrorIs(t, err, ErrTrustedSetupInconsistent, name)
+	}
+
+	notInSubgroup := tamper(func(setup *JSONTrustedSetup) {
+		setup.SetupG1Monomial[3] = "0x" + strings.Repeat("ff", CompressedG1Size)
+	})
+	require.ErrorIs(t, VerifyTrustedSetupConsistency(notInSubgroup), ErrMalformedTrustedSetup)
+}
@@ -1,7 +1,11 @@
 package goethkzg
 
 import (
+	"context"
+	"errors"
+
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/crate-crypto/go-eth-kzg/internal/kzg"
 	"golang.org/x/sync/errgroup"
 )
@@ -46,21 +50,44 @@ func (c *Context) VerifyKZGProof(blobCommitment KZGCommitment, inputPointBytes,
 //
 // [verify_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof
 func (c *Context) VerifyBlobKZGProof(blob *Blob, blobCommitment KZGCommitment, kzgProof KZGProof) error {
+	return c.VerifyBlobKZGProofBytes(blobBytes(blob), blobCommitment, kzgProof)
+}
+
+// VerifyBlobKZGProofBytes is the same as [Context.VerifyBlobKZGProof] for a blob whose size is given by the context
+// parameters. See [Params].
+func (c *Context) VerifyBlobKZGProofBytes(blob []byte, blobCommitment KZGCommitment, kzgProof KZGProof) error {
+	err := c.verifyBlobKZGProof(context.Background(), 0, blob, blobCommitment, kzgProof)
+
+	// This is not a batch API, so only the underlying error is returned
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		return batchErr.Err
+	}
+	return err
+}
+
+// verifyBlobKZGProof verifies the proof for a single blob, which is at `index` in a batch.
+//
+// Errors that are caused by the inputs are returned as a [BatchError].
+func (c *Context) verifyBlobKZGProof(ctx context.Context, index int, blob []byte, blobCommitment KZGCommitment, kzgProof KZGProof) error {
 	// 1. Deserialize
 	//
-	polynomial, err := DeserializeBlob(blob)
+	polynomial, err := c.deserializeBlob(blob)
 	if err != nil {
+		return newBatchError(index, ErrorKindMalformedBlob, err)
+	}
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 
 	polynomialCommitment, err := DeserializeKZGCommitment(blobCommitment)
 	if err != nil {
-		return err
+		return newBatchError(index, ErrorKindMalformedCommitment, err)
 	}
 
 	quotientCommitment, err := DeserializeKZGProof(kzgProof)
 	if err != nil {
-		return err
+		return newBatchError(index, ErrorKindMalformedProof, err)
 	}
 
 	// 2. Compute the evaluation challenge
@@ -71,6 +98,9 @@ func (c *Context) VerifyBlobKZGProof(blob *Blob, blobCommitment KZGCommitment, k
 	if err != nil {
 		return err
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// 4. Verify opening proof
 	openingProof := kzg.OpeningProof{
@@ -79,19 +109,40 @@ func (c *Context) VerifyBlobKZGProof(blob *Blob, blobCommitment KZGCommitment, k
 		ClaimedValue:       *outputPoint,
 	}
 
-	return kzg.Verify(&polynomialCommitment, &openingProof, c.openKey4844)
+	if err := kzg.Verify(&polynomialCommitment, &openingProof, c.openKey4844); err != nil {
+		if errors.Is(err, ErrVerifyOpeningProof) {
+			return newBatchError(index, ErrorKindInvalidProof, err)
+		}
+		return err
+	}
+	return nil
 }
 
 // VerifyBlobKZGProofBatch implements [verify_blob_kzg_proof_batch].
 //
+// Errors that are caused by the inputs are returned as a [BatchError], whose Index is the position of the offending
+// blob in the batch. If the blobs are well-formed but the proofs do not verify, the error wraps
+// [ErrVerifyOpeningProof]. Since the proofs are checked together, the Index is then only known for a batch of one.
+// [Context.FindInvalidBlobProofs] can be used to find the proofs that failed.
+//
 // [verify_blob_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
 func (c *Context) VerifyBlobKZGProofBatch(blobs []*Blob, polynomialCommitments []KZGCommitment, kzgProofs []KZGProof) error {
+	serBlobs := make([][]byte, len(blobs))
+	for i, blob := range blobs {
+		serBlobs[i] = blobBytes(blob)
+	}
+	return c.VerifyBlobKZGProofBatchBytes(serBlobs, polynomialCommitments, kzgProofs)
+}
+
+// VerifyBlobKZGProofBatchBytes is the same as [Context.VerifyBlobKZGProofBatch] for blobs whose size is given by the
+// context parameters. See [Params].
+func (c *Context) VerifyBlobKZGProofBatchBytes(blobs [][]byte, polynomialCommitments []KZGCommitment, kzgProofs []KZGProof) error {
 	// 1. Check that all components in the batch have the same size
 	//
 	blobsLen := len(blobs)
 	lengthsAreEqual := blobsLen == len(polynomialCommitments) && blobsLen == len(kzgProofs)
 	if !lengthsAreEqual {
-		return ErrBatchLengthCheck
+		return newBatchError(-1, ErrorKindBatchLength, ErrBatchLengthCheck)
 	}
 	batchSize := blobsLen
 
@@ -100,47 +151,73 @@ func (c *Context) VerifyBlobKZGProofBatch(blobs []*Blob, polynomialCommitments [
 	openingProofs := make([]kzg.OpeningProof, batchSize)
 	commitments := make([]bls12381.G1Affine, batchSize)
 	for i := 0; i < batchSize; i++ {
-		// 2a. Deserialize
-		//
-		serComm := polynomialCommitments[i]
-		polynomialCommitment, err := DeserializeKZGCommitment(serComm)
+		commitment, openingProof, err := c.blobOpeningProof(i, blobs[i], polynomialCommitments[i], kzgProofs[i])
 		if err != nil {
 			return err
 		}
+		openingProofs[i] = openingProof
+		commitments[i] = commitment
+	}
 
-		kzgProof := kzgProofs[i]
-		quotientCommitment, err := DeserializeKZGProof(kzgProof)
-		if err != nil {
-			return err
-		}
+	// 3. Verify opening proofs
+	return verificationError(batchSize, c.batchVerifyBlobOpeningProofs(polynomialCommitments, kzgProofs, commitments, openingProofs))
+}
 
-		blob := blobs[i]
-		polynomial, err := DeserializeBlob(blob)
-		if err != nil {
-			return err
-		}
+// blobOpeningProof deserializes the inputs for the blob at `index` in a batch, and computes the opening proof that
+// is checked for it.
+//
+// Errors that are caused by the inputs are returned as a [BatchError].
+func (c *Context) blobOpeningProof(index int, blob []byte, serComm KZGCommitment, kzgProof KZGProof) (bls12381.G1Affine, kzg.OpeningProof, error) {
+	// 1. Deserialize
+	//
+	polynomialCommitment, err := DeserializeKZGCommitment(serComm)
+	if err != nil {
+		return bls12381.G1Affine{}, kzg.OpeningProof{}, newBatchError(index, ErrorKindMalformedCommitment, err)
+	}
 
-		// 2b. Compute the evaluation challenge
-		evaluationChallenge := computeChallenge(blob, serComm)
+	quotientCommitment, err := DeserializeKZGProof(kzgProof)
+	if err != nil {
+		return bls12381.G1Affine{}, kzg.OpeningProof{}, newBatchError(index, ErrorKindMalformedProof, err)
+	}
 
-		// 2c. Compute output point/ claimed value
-		outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomial, evaluationChallenge)
-		if err != nil {
-			return err
-		}
+	polynomial, err := c.deserializeBlob(blob)
+	if err != nil {
+		return bls12381.G1Affine{}, kzg.OpeningProof{}, newBatchError(index, ErrorKindMalformedBlob, err)
+	}
 
-		// 2d. Append opening proof to list
-		openingProof := kzg.OpeningProof{
-			QuotientCommitment: quotientCommitment,
-			InputPoint:         evaluationChallenge,
-			ClaimedValue:       *outputPoint,
-		}
-		openingProofs[i] = openingProof
-		commitments[i] = polynomialCommitment
+	// 2. Compute the evaluation challenge
+	evaluationChallenge := computeChallenge(blob, serComm)
+
+	// 3. Compute output point/ claimed value
+	outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomial, evaluationChallenge)
+	if err != nil {
+		return bls12381.G1Affine{}, kzg.OpeningProof{}, err
 	}
 
-	// 3. Verify opening proofs
-	return kzg.BatchVerifyMultiPoints(commitments, openingProofs, c.openKey4844)
+	openingProof := kzg.OpeningProof{
+		QuotientCommitment: quotientCommitment,
+		InputPoint:         evaluationChallenge,
+		ClaimedValue:       *outputPoint,
+	}
+	return polynomialCommitment, openingProof, nil
+}
+
+// batchVerifyBlobOpeningProofs checks the opening proofs for a batch of blobs together. `serComms` and `kzgProofs`
+// are the serialized commitments and proofs, which are only needed to compute the challenge in deterministic mode.
+func (c *Context) batchVerifyBlobOpeningProofs(serComms []KZGCommitment, kzgProofs []KZGProof, commitments []bls12381.G1Affine, openingProofs []kzg.OpeningProof) error {
+	r, err := c.batchVerificationScalar(func() fr.Element {
+		zs := make([]fr.Element, len(openingProofs))
+		ys := make([]fr.Element, len(openingProofs))
+		for i, openingProof := range openingProofs {
+			zs[i] = openingProof.InputPoint
+			ys[i] = openingProof.ClaimedValue
+		}
+		return computeBlobBatchChallenge(c.params.FieldElementsPerBlob, serComms, zs, ys, kzgProofs)
+	})
+	if err != nil {
+		return err
+	}
+	return kzg.BatchVerifyMultiPoints(commitments, openingProofs, r, c.openKey4844)
 }
 
 // VerifyBlobKZGProofBatchPar implements [verify_blob_kzg_proof_batch]. This is the parallelized version of
@@ -148,19 +225,31 @@ func (c *Context) VerifyBlobKZGProofBatch(blobs []*Blob, polynomialCommitments [
 // parallel. If you are worried about resource starvation on large batches, it is advised to schedule your own
 // go-routines in a more intricate way than done below for large batches.
 //
+// Errors are reported as in [Context.VerifyBlobKZGProofBatch], except that each proof is checked on its own, so the
+// Index of a proof that does not verify is always known. When several blobs are rejected, any one of them may be
+// reported.
+//
 // [verify_blob_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
 func (c *Context) VerifyBlobKZGProofBatchPar(blobs []*Blob, commitments []KZGCommitment, proofs []KZGProof) error {
+	return c.VerifyBlobKZGProofBatchParCtx(context.Background(), blobs, commitments, proofs)
+}
+
+// VerifyBlobKZGProofBatchParCtx is the same as [Context.VerifyBlobKZGProofBatchPar], but stops early and returns
+// ctx.Err() if `ctx` is cancelled or its deadline passes.
+//
+// The go-routines that are still running when the first proof fails to verify are also stopped early.
+func (c *Context) VerifyBlobKZGProofBatchParCtx(ctx context.Context, blobs []*Blob, commitments []KZGCommitment, proofs []KZGProof) error {
 	// 1. Check that all components in the batch have the same size
 	if len(commitments) != len(blobs) || len(proofs) != len(blobs) {
-		return ErrBatchLengthCheck
+		return newBatchError(-1, ErrorKindBatchLength, ErrBatchLengthCheck)
 	}
 
 	// 2. Verify each opening proof using green threads
-	var errG errgroup.Group
+	errG, groupCtx := errgroup.WithContext(ctx)
 	for i := range blobs {
 		j := i // Capture the value of the loop variable
 		errG.Go(func() error {
-			return c.VerifyBlobKZGProof(blobs[j], commitments[j], proofs[j])
+			return c.verifyBlobKZGProof(groupCtx, j, blobBytes(blobs[j]), commitments[j], proofs[j])
 		})
 	}
 
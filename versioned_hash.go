@@ -0,0 +1,107 @@
+package goethkzg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// VersionedHashVersionKZG is the version byte of a versioned hash for a KZG commitment.
+//
+// It matches [VERSIONED_HASH_VERSION_KZG] in the spec.
+//
+// [VERSIONED_HASH_VERSION_KZG]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/beacon-chain.md#blob
+const VersionedHashVersionKZG = 0x01
+
+// VersionedHash is the hash of a commitment that blob transactions and execution payloads refer to blobs by.
+//
+// It matches [VersionedHash] in the spec.
+//
+// A VersionedHash is encoded as a hex-string with the 0x prefix by [encoding/json] and other packages that use
+// [encoding.TextMarshaler].
+//
+// [VersionedHash]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/beacon-chain.md#custom-types
+type VersionedHash [sha256.Size]byte
+
+// VersionedHash implements [kzg_commitment_to_versioned_hash]: it is the SHA-256 hash of the commitment, with the
+// first byte replaced by [VersionedHashVersionKZG].
+//
+// The commitment is not checked to be a valid point, since only its bytes are hashed.
+//
+// [kzg_commitment_to_versioned_hash]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/beacon-chain.md#kzg_commitment_to_versioned_hash
+func (c KZGCommitment) VersionedHash() VersionedHash {
+	versionedHash := sha256.Sum256(c[:])
+	versionedHash[0] = VersionedHashVersionKZG
+	return versionedHash
+}
+
+// Version returns the version byte of the hash.
+func (h VersionedHash) Version() byte {
+	return h[0]
+}
+
+// String returns the hash as a hex-string with the 0x prefix.
+func (h VersionedHash) String() string {
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (h VersionedHash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler]. See [ParseVersionedHash].
+func (h *VersionedHash) UnmarshalText(text []byte) error {
+	parsed, err := ParseVersionedHash(string(text))
+	if err != nil {
+		return err
+	}
+	*h = parsed
+	return nil
+}
+
+// ParseVersionedHash parses a versioned hash from a hex-string with the 0x prefix.
+//
+// Any version byte is accepted, so that hashes of other versions can be parsed and then rejected by
+// [VerifyVersionedHashes].
+func ParseVersionedHash(hexString string) (VersionedHash, error) {
+	byts, err := decodeHexStr(hexString)
+	if err != nil {
+		return VersionedHash{}, fmt.Errorf("%w: %w", ErrMalformedVersionedHash, err)
+	}
+	if len(byts) != len(VersionedHash{}) {
+		return VersionedHash{}, fmt.Errorf("%w: expected %d bytes, found %d", ErrMalformedVersionedHash, len(VersionedHash{}), len(byts))
+	}
+	return VersionedHash(byts), nil
+}
+
+// VersionedHashes returns the versioned hash of each of the commitments.
+func VersionedHashes(commitments []KZGCommitment) []VersionedHash {
+	versionedHashes := make([]VersionedHash, len(commitments))
+	for i, commitment := range commitments {
+		versionedHashes[i] = commitment.VersionedHash()
+	}
+	return versionedHashes
+}
+
+// VerifyVersionedHashes checks that each of the versioned hashes of a blob transaction is the versioned hash of the
+// commitment at the same index.
+//
+// The first element that does not match is reported as a [BatchError] of kind [ErrorKindVersionedHashMismatch],
+// which wraps [ErrUnsupportedVersionedHashVersion] if the version byte is not [VersionedHashVersionKZG] and
+// [ErrVersionedHashMismatch] otherwise. If the lengths differ, the [BatchError] has kind [ErrorKindBatchLength].
+func VerifyVersionedHashes(versionedHashes []VersionedHash, commitments []KZGCommitment) error {
+	if len(versionedHashes) != len(commitments) {
+		return newBatchError(-1, ErrorKindBatchLength, ErrBatchLengthCheck)
+	}
+
+	for i, versionedHash := range versionedHashes {
+		if versionedHash.Version() != VersionedHashVersionKZG {
+			return newBatchError(i, ErrorKindVersionedHashMismatch, fmt.Errorf("%w: 0x%02x", ErrUnsupportedVersionedHashVersion, versionedHash.Version()))
+		}
+		if versionedHash != commitments[i].VersionedHash() {
+			return newBatchError(i, ErrorKindVersionedHashMismatch, ErrVersionedHashMismatch)
+		}
+	}
+	return nil
+}
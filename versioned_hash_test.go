@@ -0,0 +1,114 @@
+package goethkzg_test
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestVersionedHash(t *testing.T) {
+	// The versioned hash of the point at infinity, which is the commitment to the zero blob
+	var infinity goethkzg.KZGCommitment
+	infinity[0] = 0xc0
+	expected, err := goethkzg.ParseVersionedHash("0x010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c444014")
+	require.NoError(t, err)
+	require.Equal(t, expected, infinity.VersionedHash())
+	require.Equal(t, byte(goethkzg.VersionedHashVersionKZG), expected.Version())
+
+	t.Run("json", func(t *testing.T) {
+		encoded, err := json.Marshal([]goethkzg.VersionedHash{expected})
+		require.NoError(t, err)
+		require.Equal(t, `["0x010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c444014"]`, string(encoded))
+
+		var decoded []goethkzg.VersionedHash
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		require.Equal(t, []goethkzg.VersionedHash{expected}, decoded)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, hexStr := range []string{
+			"010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c444014",
+			"0x010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c4440",
+			"0x010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c44401400",
+			"0x010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c44401g",
+		} {
+			_, err := goethkzg.ParseVersionedHash(hexStr)
+			require.ErrorIs(t, err, goethkzg.ErrMalformedVersionedHash, hexStr)
+
+			var versionedHash goethkzg.VersionedHash
+			require.ErrorIs(t, json.Unmarshal([]byte(`"`+hexStr+`"`), &versionedHash), goethkzg.ErrMalformedVersionedHash)
+		}
+	})
+}
+
+func TestVerifyVersionedHashes(t *testing.T) {
+	type Test struct {
+		Input struct {
+			Commitments []string `yaml:"commitments"`
+		}
+	}
+
+	// The commitments of the blob batches in the spec vectors stand in for the commitments of blob transactions
+	tests, err := filepath.Glob(verifyBlobKZGProofBatchTests)
+	require.NoError(t, err)
+	require.True(t, len(tests) > 0)
+
+	numChecked := 0
+	for _, testPath := range tests {
+		testFile, err := os.Open(testPath)
+		require.NoError(t, err)
+		test := Test{}
+		err = yaml.NewDecoder(testFile).Decode(&test)
+		testFile.Close()
+		require.NoError(t, err)
+
+		commitments := make([]goethkzg.KZGCommitment, 0, len(test.Input.Commitments))
+		for _, commitmentStr := range test.Input.Commitments {
+			commitment, err := hexStrToCommitment(commitmentStr)
+			if err != nil {
+				break
+			}
+			commitments = append(commitments, commitment)
+		}
+		if len(commitments) != len(test.Input.Commitments) || len(commitments) == 0 {
+			continue
+		}
+		numChecked++
+
+		versionedHashes := goethkzg.VersionedHashes(commitments)
+		for i, commitment := range commitments {
+			hash := sha256.Sum256(commitment[:])
+			require.Equal(t, byte(goethkzg.VersionedHashVersionKZG), versionedHashes[i][0])
+			require.Equal(t, hash[1:], versionedHashes[i][1:])
+		}
+		require.NoError(t, goethkzg.VerifyVersionedHashes(versionedHashes, commitments))
+
+		// Every index of a mismatch is reported
+		for i := range versionedHashes {
+			wrongHashes := append([]goethkzg.VersionedHash(nil), versionedHashes...)
+			wrongHashes[i][31] ^= 1
+			err := goethkzg.VerifyVersionedHashes(wrongHashes, commitments)
+			requireBatchError(t, err, i, goethkzg.ErrorKindVersionedHashMismatch)
+			require.ErrorIs(t, err, goethkzg.ErrVersionedHashMismatch)
+
+			wrongHashes = append([]goethkzg.VersionedHash(nil), versionedHashes...)
+			wrongHashes[i][0] = 0x02
+			err = goethkzg.VerifyVersionedHashes(wrongHashes, commitments)
+			requireBatchError(t, err, i, goethkzg.ErrorKindVersionedHashMismatch)
+			require.ErrorIs(t, err, goethkzg.ErrUnsupportedVersionedHashVersion)
+		}
+
+		err = goethkzg.VerifyVersionedHashes(versionedHashes[1:], commitments)
+		requireBatchError(t, err, -1, goethkzg.ErrorKindBatchLength)
+	}
+	require.True(t, numChecked > 0)
+
+	require.NoError(t, goethkzg.VerifyVersionedHashes(nil, nil))
+	require.False(t, goethkzg.ErrorKindVersionedHashMismatch.IsMalformed())
+}